@@ -24,12 +24,9 @@ func main() {
 		log.Fatalf("Failed to connect to cluster: %v", err)
 	}
 
-	mgmClient := management.New(ctx, client)
-
-	// wait for 5 seconds to ensure all PrometheusRules are updated
-	for i := 0; i < 5; i++ {
-		fmt.Println("Waiting for PrometheusRules to be updated...")
-		time.Sleep(1 * time.Second)
+	mgmClient, err := management.New(ctx, client)
+	if err != nil {
+		log.Fatalf("Failed to create management client: %v", err)
 	}
 
 	rules, err := mgmClient.ListRules(ctx, management.PrometheusRuleOptions{Namespace: "openshift-monitoring"}, management.AlertRuleOptions{})
@@ -12,12 +12,29 @@ func (hr *httpRouter) DeleteUserDefinedAlertRuleById(w http.ResponseWriter, req
 		return
 	}
 
-	if err := hr.managementClient.DeleteUserDefinedAlertRuleById(req.Context(), ruleId); err != nil {
+	// Deleting a rule updates its PrometheusRule, or deletes it with its last rule
+	if !hr.authorizeAlertRule(w, req, ruleId, "update", "delete") {
+		return
+	}
+
+	ctx, err := withIfMatch(req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, dryRun, err := withDryRun(ctx, req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := hr.managementClient.DeleteUserDefinedAlertRuleById(ctx, ruleId); err != nil {
 		handleError(w, err)
 		return
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	writeEditResponse(w, http.StatusNoContent, nil, dryRun)
 }
 
 type BulkDeleteUserDefinedAlertRulesRequest struct {
@@ -45,6 +62,14 @@ func (hr *httpRouter) BulkDeleteUserDefinedAlertRules(w http.ResponseWriter, req
 		return
 	}
 
+	// The rules are deleted one at a time, so in a dry run each deletion is
+	// validated against the PrometheusRule without the other deletions
+	ctx, dryRun, err := withDryRun(req.Context(), req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	results := make([]DeleteUserDefinedAlertRulesResponse, 0, len(payload.RuleIds))
 
 	for _, rawId := range payload.RuleIds {
@@ -58,7 +83,16 @@ func (hr *httpRouter) BulkDeleteUserDefinedAlertRules(w http.ResponseWriter, req
 			continue
 		}
 
-		if err := hr.managementClient.DeleteUserDefinedAlertRuleById(req.Context(), id); err != nil {
+		if status, message := hr.checkAlertRuleAccess(req.Context(), id, "update", "delete"); status != http.StatusOK {
+			results = append(results, DeleteUserDefinedAlertRulesResponse{
+				Id:         id,
+				StatusCode: status,
+				Message:    message,
+			})
+			continue
+		}
+
+		if err := hr.managementClient.DeleteUserDefinedAlertRuleById(ctx, id); err != nil {
 			status, message := parseError(err)
 			results = append(results, DeleteUserDefinedAlertRulesResponse{
 				Id:         id,
@@ -73,9 +107,7 @@ func (hr *httpRouter) BulkDeleteUserDefinedAlertRules(w http.ResponseWriter, req
 		})
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	_ = json.NewEncoder(w).Encode(BulkDeleteUserDefinedAlertRulesResponse{
+	writeEditResponse(w, http.StatusOK, BulkDeleteUserDefinedAlertRulesResponse{
 		Rules: results,
-	})
+	}, dryRun)
 }
@@ -10,8 +10,17 @@ import (
 )
 
 type GetAlertsQueryParams struct {
-	Labels map[string]string `form:"labels"`
-	State  string            `form:"state"`
+	Labels    map[string]string `form:"labels"`
+	State     string            `form:"state"`
+	Namespace string            `form:"namespace"`
+
+	// AlertRuleId only returns the alerts of the rule
+	AlertRuleId string `form:"alertRuleId"`
+
+	// Alertmanager merges the silences, inhibitions and receivers of the alerts in Alertmanager
+	Alertmanager bool  `form:"alertmanager"`
+	Silenced     *bool `form:"silenced"`
+	Inhibited    *bool `form:"inhibited"`
 }
 
 type GetAlertsResponse struct {
@@ -31,9 +40,39 @@ func (hr *httpRouter) GetAlerts(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	// Namespace-scoped alerts are queried with the caller token, so that only
+	// callers with access to the namespace can see its alerts
+	var bearerToken string
+	if params.Namespace != "" {
+		bearerToken = getBearerToken(req)
+		if bearerToken == "" {
+			writeError(w, http.StatusUnauthorized, "a bearer token is required to get namespace-scoped alerts")
+			return
+		}
+	} else if !hr.authorize(w, req, clusterAlertsAttributes) {
+		return
+	}
+
+	// The rule must not be disclosed to users who cannot get it
+	if params.AlertRuleId != "" && !hr.authorizeAlertRule(w, req, params.AlertRuleId, "get") {
+		return
+	}
+
+	includeAlertmanager := params.Alertmanager || params.Silenced != nil || params.Inhibited != nil
+	if includeAlertmanager && !hr.authorize(w, req, alertmanagerAPIAttributes("get")) {
+		return
+	}
+
 	alerts, err := hr.managementClient.GetAlerts(req.Context(), k8s.GetAlertsRequest{
-		Labels: params.Labels,
-		State:  params.State,
+		Labels:      params.Labels,
+		State:       params.State,
+		Namespace:   params.Namespace,
+		BearerToken: bearerToken,
+		AlertRuleId: params.AlertRuleId,
+
+		IncludeAlertmanager: includeAlertmanager,
+		Silenced:            params.Silenced,
+		Inhibited:           params.Inhibited,
 	})
 	if err != nil {
 		handleError(w, err)
@@ -107,6 +107,56 @@ var _ = Describe("GetAlerts", func() {
 		})
 	})
 
+	Context("when getting namespace-scoped alerts", func() {
+		It("should forward the namespace and the caller bearer token", func() {
+			var received k8s.GetAlertsRequest
+			mockPrometheusAlerts.GetAlertsFunc = func(ctx context.Context, req k8s.GetAlertsRequest) ([]k8s.PrometheusAlert, error) {
+				received = req
+				return []k8s.PrometheusAlert{}, nil
+			}
+
+			By("making the request")
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/alerting/alerts?namespace=my-app", nil)
+			req.Header.Set("Authorization", "Bearer user-token")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			By("verifying the request was forwarded")
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(received.Namespace).To(Equal("my-app"))
+			Expect(received.BearerToken).To(Equal("user-token"))
+		})
+
+		It("should not forward the bearer token for cluster-wide requests", func() {
+			var received k8s.GetAlertsRequest
+			mockPrometheusAlerts.GetAlertsFunc = func(ctx context.Context, req k8s.GetAlertsRequest) ([]k8s.PrometheusAlert, error) {
+				received = req
+				return []k8s.PrometheusAlert{}, nil
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/alerting/alerts", nil)
+			req.Header.Set("Authorization", "Bearer user-token")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(received.Namespace).To(BeEmpty())
+			Expect(received.BearerToken).To(BeEmpty())
+		})
+
+		It("should return 401 when the bearer token is missing", func() {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/alerting/alerts?namespace=my-app", nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			Expect(w.Body.String()).To(ContainSubstring("bearer token is required"))
+		})
+	})
+
 	Context("when handling errors", func() {
 		It("should return 500 when GetAlerts fails", func() {
 			By("configuring mock to return error")
@@ -126,4 +176,55 @@ var _ = Describe("GetAlerts", func() {
 		})
 	})
 
+	Context("when filtering by Alertmanager status", func() {
+		BeforeEach(func() {
+			mockPrometheusAlerts.SetActiveAlerts([]k8s.PrometheusAlert{
+				{Labels: map[string]string{"alertname": "Muted"}, State: "firing"},
+				{Labels: map[string]string{"alertname": "Loud"}, State: "firing"},
+			})
+			mockK8s.AlertmanagerAlertsFunc = func() k8s.AlertmanagerAlertsInterface {
+				return &testutils.MockAlertmanagerAlertsInterface{
+					Alerts: []k8s.AlertmanagerAlert{
+						{Labels: map[string]string{"alertname": "Muted"}, Fingerprint: "fp1", Status: k8s.AlertmanagerAlertStatus{SilencedBy: []string{"s1"}}},
+						{Labels: map[string]string{"alertname": "Loud"}, Fingerprint: "fp2", Receivers: []k8s.AlertmanagerReceiver{{Name: "pager"}}},
+					},
+				}
+			}
+		})
+
+		It("should hide silenced alerts with silenced=false", func() {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/alerting/alerts?silenced=false", nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var response httprouter.GetAlertsResponse
+			Expect(json.NewDecoder(w.Body).Decode(&response)).To(Succeed())
+			Expect(response.Data.Alerts).To(HaveLen(1))
+			Expect(response.Data.Alerts[0].Labels["alertname"]).To(Equal("Loud"))
+			Expect(response.Data.Alerts[0].Fingerprint).To(Equal("fp2"))
+			Expect(response.Data.Alerts[0].Receivers).To(Equal([]string{"pager"}))
+		})
+
+		It("should return 400 for invalid filters", func() {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/alerting/alerts?silenced=maybe", nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	Context("when filtering by rule ID", func() {
+		It("should return 404 for unknown rule IDs", func() {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/alerting/alerts?alertRuleId=unknown", nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+	})
 })
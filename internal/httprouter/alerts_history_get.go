@@ -0,0 +1,94 @@
+package httprouter
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-playground/form/v4"
+
+	"github.com/machadovilaca/alerts-ui-management/pkg/management"
+)
+
+// defaultAlertHistoryRange is the range of the alert history when start is not set
+const defaultAlertHistoryRange = 24 * time.Hour
+
+type GetAlertHistoryQueryParams struct {
+	Labels map[string]string `form:"labels"`
+
+	// AlertRuleId only returns the history of the alerts of the rule
+	AlertRuleId string `form:"alertRuleId"`
+
+	// Start and End are RFC3339 times, defaulting to the last 24 hours
+	Start time.Time `form:"start"`
+	End   time.Time `form:"end"`
+
+	// Step is the resolution of the history as a Go duration, e.g. 1m
+	Step string `form:"step"`
+}
+
+type GetAlertHistoryResponse struct {
+	Data   GetAlertHistoryResponseData `json:"data"`
+	Status string                      `json:"status"`
+}
+
+type GetAlertHistoryResponseData struct {
+	Alerts []management.AlertHistory `json:"alerts"`
+}
+
+func (hr *httpRouter) GetAlertHistory(w http.ResponseWriter, req *http.Request) {
+	var params GetAlertHistoryQueryParams
+
+	if err := form.NewDecoder().Decode(&params, req.URL.Query()); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid query parameters: "+err.Error())
+		return
+	}
+
+	var step time.Duration
+	if params.Step != "" {
+		var err error
+		step, err = time.ParseDuration(params.Step)
+		if err != nil || step <= 0 {
+			writeError(w, http.StatusBadRequest, "Invalid query parameters: step must be a positive duration")
+			return
+		}
+	}
+
+	end := params.End
+	if end.IsZero() {
+		end = time.Now()
+	}
+	start := params.Start
+	if start.IsZero() {
+		start = end.Add(-defaultAlertHistoryRange)
+	}
+
+	// The history is read from the cluster-wide Prometheus API
+	if !hr.authorize(w, req, clusterAlertsAttributes) {
+		return
+	}
+
+	// The rule must not be disclosed to users who cannot get it
+	if params.AlertRuleId != "" && !hr.authorizeAlertRule(w, req, params.AlertRuleId, "get") {
+		return
+	}
+
+	history, err := hr.managementClient.GetAlertHistory(req.Context(), management.AlertHistoryFilters{
+		Labels:      params.Labels,
+		AlertRuleId: params.AlertRuleId,
+		Step:        step,
+	}, start, end)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(GetAlertHistoryResponse{
+		Data: GetAlertHistoryResponseData{
+			Alerts: history,
+		},
+		Status: "success",
+	})
+}
@@ -0,0 +1,106 @@
+package httprouter_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/machadovilaca/alerts-ui-management/internal/httprouter"
+	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management/testutils"
+)
+
+var _ = Describe("GetAlertHistory", func() {
+	var (
+		queries []k8s.QueryRangeRequest
+		mgmt    management.Client
+		router  http.Handler
+	)
+
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	BeforeEach(func() {
+		queries = nil
+		mockK8s := &testutils.MockClient{
+			PrometheusQueryFunc: func() k8s.PrometheusQueryInterface {
+				return &testutils.MockPrometheusQueryInterface{
+					QueryRangeFunc: func(ctx context.Context, req k8s.QueryRangeRequest) ([]k8s.RangeSeries, error) {
+						queries = append(queries, req)
+						if strings.HasPrefix(req.Query, "ALERTS_FOR_STATE") {
+							return nil, nil
+						}
+						return []k8s.RangeSeries{{
+							Metric:  map[string]string{"__name__": "ALERTS", "alertname": "Foo", "alertstate": "firing"},
+							Samples: []k8s.Sample{{Time: start, Value: 1}, {Time: start.Add(time.Minute), Value: 1}},
+						}}, nil
+					},
+				}
+			},
+		}
+
+		mgmt = management.NewWithCustomMapper(context.Background(), mockK8s, &testutils.MockMapperClient{})
+		router = httprouter.New(mgmt)
+	})
+
+	serve := func(target string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	It("should return the firing intervals of the alerts in the range", func() {
+		w := serve("/api/v1/alerting/alerts/history?start=2024-01-01T12:00:00Z&end=2024-01-01T13:00:00Z&step=1m&labels[namespace]=team-a")
+
+		Expect(w.Code).To(Equal(http.StatusOK))
+		Expect(queries).To(HaveLen(2))
+		Expect(queries[0].Query).To(Equal(`ALERTS{alertstate="firing",namespace="team-a"}`))
+		Expect(queries[0].Start).To(Equal(start))
+		Expect(queries[0].End).To(Equal(start.Add(time.Hour)))
+		Expect(queries[0].Step).To(Equal(time.Minute))
+
+		var response httprouter.GetAlertHistoryResponse
+		Expect(json.NewDecoder(w.Body).Decode(&response)).To(Succeed())
+		Expect(response.Status).To(Equal("success"))
+		Expect(response.Data.Alerts).To(HaveLen(1))
+		Expect(response.Data.Alerts[0].Labels).To(Equal(map[string]string{"alertname": "Foo"}))
+		Expect(response.Data.Alerts[0].Intervals).To(Equal([]management.FiringInterval{
+			{Start: start, End: start.Add(time.Minute)},
+		}))
+	})
+
+	It("should default to the last 24 hours", func() {
+		Expect(serve("/api/v1/alerting/alerts/history").Code).To(Equal(http.StatusOK))
+
+		Expect(queries[0].End).To(BeTemporally("~", time.Now(), time.Minute))
+		Expect(queries[0].End.Sub(queries[0].Start)).To(Equal(24 * time.Hour))
+	})
+
+	It("should return 400 for invalid ranges and steps", func() {
+		Expect(serve("/api/v1/alerting/alerts/history?step=often").Code).To(Equal(http.StatusBadRequest))
+		Expect(serve("/api/v1/alerting/alerts/history?start=yesterday").Code).To(Equal(http.StatusBadRequest))
+		Expect(serve("/api/v1/alerting/alerts/history?start=2024-01-01T13:00:00Z&end=2024-01-01T12:00:00Z").Code).To(Equal(http.StatusBadRequest))
+		Expect(queries).To(BeEmpty())
+	})
+
+	It("should require access to the cluster-wide alerts", func() {
+		authorizer := testutils.NewFakeAuthorizer()
+		authorizer.AddUser("alice-token", k8s.UserInfo{Username: "alice"})
+		router = httprouter.NewWithAuthorizer(mgmt, authorizer)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/alerting/alerts/history", nil)
+		req.Header.Set("Authorization", "Bearer alice-token")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		Expect(w.Code).To(Equal(http.StatusForbidden))
+		Expect(queries).To(BeEmpty())
+	})
+})
@@ -0,0 +1,82 @@
+package httprouter
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/machadovilaca/alerts-ui-management/pkg/management"
+)
+
+type SilenceAlertRequest struct {
+	management.SilenceOptions
+
+	// Labels are the labels of the alert, as returned by GET /api/v1/alerting/alerts
+	Labels map[string]string `json:"labels"`
+}
+
+type SilenceAlertResponse struct {
+	Id string `json:"id"`
+}
+
+func (hr *httpRouter) SilenceAlert(w http.ResponseWriter, req *http.Request) {
+	var payload SilenceAlertRequest
+	if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if len(payload.Labels) == 0 {
+		writeError(w, http.StatusBadRequest, "labels is required")
+		return
+	}
+
+	if !hr.authorize(w, req, alertmanagerAPIAttributes("create")) {
+		return
+	}
+
+	id, err := hr.managementClient.SilenceAlert(req.Context(), payload.Labels, payload.SilenceOptions)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(SilenceAlertResponse{
+		Id: id,
+	})
+}
+
+type SilenceAlertRuleRequest = management.SilenceOptions
+
+func (hr *httpRouter) SilenceAlertRule(w http.ResponseWriter, req *http.Request) {
+	ruleId, err := getParam(req, "ruleId")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var payload SilenceAlertRuleRequest
+	if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	// The matchers are built from the rule, which must not be disclosed to
+	// users who cannot get it
+	if !hr.authorizeAlertRule(w, req, ruleId, "get") ||
+		!hr.authorize(w, req, alertmanagerAPIAttributes("create")) {
+		return
+	}
+
+	id, err := hr.managementClient.SilenceAlertRule(req.Context(), ruleId, payload)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(SilenceAlertResponse{
+		Id: id,
+	})
+}
@@ -0,0 +1,231 @@
+package httprouter
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management/mapper"
+)
+
+const (
+	monitoringCoreOSGroup    = "monitoring.coreos.com"
+	monitoringOpenShiftGroup = "monitoring.openshift.io"
+
+	prometheusRulesResource     = "prometheusrules"
+	alertRelabelConfigsResource = "alertrelabelconfigs"
+)
+
+// clusterAlertsAttributes is the access kube-rbac-proxy requires in front of
+// the platform Prometheus API, which serves the cluster-wide alerts
+var clusterAlertsAttributes = k8s.ResourceAttributes{
+	Namespace:   "openshift-monitoring",
+	Verb:        "get",
+	Group:       monitoringCoreOSGroup,
+	Resource:    "prometheuses",
+	Subresource: "api",
+	Name:        "k8s",
+}
+
+// alertmanagerAPIAttributes returns the access kube-rbac-proxy requires in
+// front of the platform Alertmanager API for requests with the verb, e.g. get
+// to read silences, create to create or update them and delete to expire them
+func alertmanagerAPIAttributes(verb string) k8s.ResourceAttributes {
+	return k8s.ResourceAttributes{
+		Namespace:   "openshift-monitoring",
+		Verb:        verb,
+		Group:       monitoringCoreOSGroup,
+		Resource:    "alertmanagers",
+		Subresource: "api",
+		Name:        "main",
+	}
+}
+
+// authenticate validates the bearer token of the request with a TokenReview
+// and adds the authenticated user to the request context
+func (hr *httpRouter) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		token := getBearerToken(req)
+		if token == "" {
+			writeError(w, http.StatusUnauthorized, "missing bearer token")
+			return
+		}
+
+		user, err := hr.authorizer.Authenticate(req.Context(), token)
+		if err != nil {
+			log.Printf("Failed to authenticate request: %v", err)
+			writeError(w, http.StatusInternalServerError, "An unexpected error occurred")
+			return
+		}
+		if user == nil {
+			writeError(w, http.StatusUnauthorized, "invalid bearer token")
+			return
+		}
+
+		next.ServeHTTP(w, req.WithContext(k8s.WithUser(req.Context(), *user)))
+	})
+}
+
+// authorize checks with a SubjectAccessReview whether the request user can
+// perform the action, writing an error response if not
+func (hr *httpRouter) authorize(w http.ResponseWriter, req *http.Request, attrs k8s.ResourceAttributes) bool {
+	status, message := hr.checkAccess(req.Context(), attrs)
+	if status != http.StatusOK {
+		writeError(w, status, message)
+		return false
+	}
+	return true
+}
+
+// checkAccess returns http.StatusOK if the user in ctx can perform the action,
+// or the status and message of the error otherwise
+func (hr *httpRouter) checkAccess(ctx context.Context, attrs k8s.ResourceAttributes) (int, string) {
+	if hr.authorizer == nil {
+		return http.StatusOK, ""
+	}
+
+	user, ok := k8s.UserFrom(ctx)
+	if !ok {
+		return http.StatusUnauthorized, "missing bearer token"
+	}
+
+	allowed, err := hr.authorizer.Authorize(ctx, user, attrs)
+	if err != nil {
+		log.Printf("Failed to authorize request: %v", err)
+		return http.StatusInternalServerError, "An unexpected error occurred"
+	}
+	if !allowed {
+		return http.StatusForbidden, forbiddenMessage(user, attrs)
+	}
+
+	return http.StatusOK, ""
+}
+
+// checkAlertRuleAccess checks whether the user in ctx can perform every verb
+// on the PrometheusRule containing the alert rule
+func (hr *httpRouter) checkAlertRuleAccess(ctx context.Context, ruleId string, verbs ...string) (int, string) {
+	if hr.authorizer == nil {
+		return http.StatusOK, ""
+	}
+
+	prId, status, message := hr.resolveAlertRule(ctx, ruleId)
+	if status != http.StatusOK {
+		return status, message
+	}
+
+	for _, verb := range verbs {
+		if status, message := hr.checkAccess(ctx, prometheusRuleAttributes(verb, prId)); status != http.StatusOK {
+			return status, message
+		}
+	}
+
+	return http.StatusOK, ""
+}
+
+// resolveAlertRule returns the PrometheusRule containing the alert rule if the
+// user in ctx can get it. Rules the user cannot get are reported as not found,
+// and only the candidates the user can get are listed for ambiguous ids, so
+// that the response does not disclose the rules of other namespaces
+func (hr *httpRouter) resolveAlertRule(ctx context.Context, ruleId string) (types.NamespacedName, int, string) {
+	notFound := &management.NotFoundError{Resource: "AlertRule", Id: ruleId}
+
+	prId, err := hr.managementClient.FindPrometheusRuleByAlertRuleId(ctx, ruleId)
+	if err != nil {
+		var ambiguousErr *management.AmbiguousAlertRuleError
+		if !errors.As(err, &ambiguousErr) {
+			status, message := parseError(err)
+			return types.NamespacedName{}, status, message
+		}
+
+		candidates, status, message := hr.visibleAlertRules(ctx, ambiguousErr.Candidates)
+		if status != http.StatusOK {
+			return types.NamespacedName{}, status, message
+		}
+		if len(candidates) == 0 {
+			err = notFound
+		} else {
+			err = &management.AmbiguousAlertRuleError{Id: ruleId, Candidates: candidates}
+		}
+		status, message = parseError(err)
+		return types.NamespacedName{}, status, message
+	}
+
+	status, message := hr.checkAccess(ctx, prometheusRuleAttributes("get", prId))
+	if status == http.StatusForbidden {
+		status, message = parseError(notFound)
+	}
+	return prId, status, message
+}
+
+// visibleAlertRules returns the qualified alert rule ids whose PrometheusRule
+// the user in ctx can get
+func (hr *httpRouter) visibleAlertRules(ctx context.Context, ruleIds []string) ([]string, int, string) {
+	var visible []string
+	for _, ruleId := range ruleIds {
+		_, location := mapper.ParseAlertingRuleId(mapper.PrometheusAlertRuleId(ruleId))
+		if location == nil {
+			continue
+		}
+
+		status, message := hr.checkAccess(ctx, prometheusRuleAttributes("get", types.NamespacedName(location.PrometheusRuleId)))
+		switch status {
+		case http.StatusOK:
+			visible = append(visible, ruleId)
+		case http.StatusForbidden:
+		default:
+			return nil, status, message
+		}
+	}
+	return visible, http.StatusOK, ""
+}
+
+// authorizeAlertRule checks whether the request user can perform every verb on
+// the PrometheusRule containing the alert rule, writing an error response if not
+func (hr *httpRouter) authorizeAlertRule(w http.ResponseWriter, req *http.Request, ruleId string, verbs ...string) bool {
+	status, message := hr.checkAlertRuleAccess(req.Context(), ruleId, verbs...)
+	if status != http.StatusOK {
+		writeError(w, status, message)
+		return false
+	}
+	return true
+}
+
+func prometheusRuleAttributes(verb string, prId types.NamespacedName) k8s.ResourceAttributes {
+	return k8s.ResourceAttributes{
+		Namespace: prId.Namespace,
+		Verb:      verb,
+		Group:     monitoringCoreOSGroup,
+		Resource:  prometheusRulesResource,
+		Name:      prId.Name,
+	}
+}
+
+func platformAlertRelabelConfigAttributes(verb string) k8s.ResourceAttributes {
+	return k8s.ResourceAttributes{
+		Namespace: management.PlatformAlertRelabelConfigNamespace,
+		Verb:      verb,
+		Group:     monitoringOpenShiftGroup,
+		Resource:  alertRelabelConfigsResource,
+	}
+}
+
+func forbiddenMessage(user k8s.UserInfo, attrs k8s.ResourceAttributes) string {
+	resource := attrs.Resource
+	if attrs.Group != "" {
+		resource += "." + attrs.Group
+	}
+	if attrs.Subresource != "" {
+		resource += "/" + attrs.Subresource
+	}
+
+	message := "user " + user.Username + " cannot " + attrs.Verb + " " + resource
+	if attrs.Namespace != "" {
+		message += " in namespace " + attrs.Namespace
+	}
+	return message
+}
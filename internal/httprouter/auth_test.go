@@ -0,0 +1,262 @@
+package httprouter_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/machadovilaca/alerts-ui-management/internal/httprouter"
+	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management/mapper"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management/testutils"
+)
+
+var _ = Describe("Authentication and authorization", func() {
+	var (
+		router       http.Handler
+		authorizer   *testutils.FakeAuthorizer
+		mockK8sRules *testutils.MockPrometheusRuleInterface
+		mockMapper   *testutils.MockMapperClient
+	)
+
+	const (
+		token    = "alice-token"
+		username = "alice"
+	)
+
+	BeforeEach(func() {
+		mockK8sRules = &testutils.MockPrometheusRuleInterface{}
+
+		teamA := monitoringv1.PrometheusRule{}
+		teamA.Name = "rules"
+		teamA.Namespace = "team-a"
+		teamA.Spec.Groups = []monitoringv1.RuleGroup{
+			{Name: "g1", Rules: []monitoringv1.Rule{{Alert: "a1"}}},
+		}
+
+		teamB := monitoringv1.PrometheusRule{}
+		teamB.Name = "rules"
+		teamB.Namespace = "team-b"
+		teamB.Spec.Groups = []monitoringv1.RuleGroup{
+			{Name: "g1", Rules: []monitoringv1.Rule{{Alert: "b1"}}},
+		}
+
+		mockK8sRules.SetPrometheusRules(map[string]*monitoringv1.PrometheusRule{
+			"team-a/rules": &teamA,
+			"team-b/rules": &teamB,
+		})
+
+		mockMapper = &testutils.MockMapperClient{
+			GetAlertingRuleIdFunc: func(rule *monitoringv1.Rule) mapper.PrometheusAlertRuleId {
+				return mapper.PrometheusAlertRuleId(rule.Alert)
+			},
+			FindAlertRuleByIdFunc: func(alertRuleId mapper.PrometheusAlertRuleId) (*mapper.PrometheusRuleId, error) {
+				switch alertRuleId {
+				case "a1":
+					return &mapper.PrometheusRuleId{Namespace: "team-a", Name: "rules"}, nil
+				case "b1":
+					return &mapper.PrometheusRuleId{Namespace: "team-b", Name: "rules"}, nil
+				case "dup":
+					return nil, &mapper.AmbiguousAlertRuleIdError{Id: alertRuleId, Candidates: []mapper.PrometheusAlertRuleId{
+						"dup@team-a/rules/0/0",
+						"dup@team-b/rules/0/0",
+					}}
+				}
+				return nil, errors.New("alert rule not found")
+			},
+		}
+
+		mockK8s := &testutils.MockClient{
+			PrometheusRulesFunc: func() k8s.PrometheusRuleInterface {
+				return mockK8sRules
+			},
+		}
+
+		authorizer = testutils.NewFakeAuthorizer()
+		authorizer.AddUser(token, k8s.UserInfo{Username: username})
+
+		mockMapper.CachePrometheusRules(mockK8sRules)
+
+		mgmt := management.NewWithCustomMapper(context.Background(), mockK8s, mockMapper)
+		router = httprouter.NewWithAuthorizer(mgmt, authorizer)
+	})
+
+	allow := func(verb string, namespace string) {
+		authorizer.Allow(username, k8s.ResourceAttributes{
+			Namespace: namespace,
+			Verb:      verb,
+			Group:     "monitoring.coreos.com",
+			Resource:  "prometheusrules",
+		})
+	}
+
+	serve := func(method string, target string, body []byte, bearerToken string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, bytes.NewReader(body))
+		if bearerToken != "" {
+			req.Header.Set("Authorization", "Bearer "+bearerToken)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	Context("when authenticating", func() {
+		It("returns 401 without a bearer token", func() {
+			w := serve(http.MethodGet, "/api/v1/alerting/rules", nil, "")
+
+			Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			Expect(w.Body.String()).To(ContainSubstring("missing bearer token"))
+		})
+
+		It("returns 401 with an invalid bearer token", func() {
+			w := serve(http.MethodGet, "/api/v1/alerting/rules", nil, "unknown-token")
+
+			Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			Expect(w.Body.String()).To(ContainSubstring("invalid bearer token"))
+		})
+
+		It("does not authenticate health endpoints", func() {
+			w := serve(http.MethodGet, "/api/v1/alerting/health/live", nil, "")
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+		})
+	})
+
+	Context("when listing rules", func() {
+		It("returns 403 if the user cannot list rules in the namespace", func() {
+			allow("list", "team-b")
+
+			w := serve(http.MethodGet, "/api/v1/alerting/rules?namespace=team-a", nil, token)
+
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+			Expect(w.Body.String()).To(ContainSubstring("user alice cannot list prometheusrules.monitoring.coreos.com in namespace team-a"))
+		})
+
+		It("returns the rules if the user can list rules in the namespace", func() {
+			allow("list", "team-a")
+
+			w := serve(http.MethodGet, "/api/v1/alerting/rules?namespace=team-a", nil, token)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var resp httprouter.GetRulesResponse
+			Expect(json.NewDecoder(w.Body).Decode(&resp)).To(Succeed())
+			Expect(resp.Data.Rules).To(HaveLen(1))
+			Expect(resp.Data.Rules[0].Alert).To(Equal("a1"))
+		})
+
+		It("requires cluster-wide access without a namespace", func() {
+			allow("list", "team-a")
+
+			w := serve(http.MethodGet, "/api/v1/alerting/rules", nil, token)
+
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+		})
+	})
+
+	Context("when getting a rule by id", func() {
+		It("authorizes against the namespace of the rule PrometheusRule", func() {
+			allow("get", "team-a")
+
+			Expect(serve(http.MethodGet, "/api/v1/alerting/rules/a1", nil, token).Code).To(Equal(http.StatusOK))
+		})
+
+		It("reports the rules the user cannot get as not found", func() {
+			allow("get", "team-a")
+
+			forbidden := serve(http.MethodGet, "/api/v1/alerting/rules/b1", nil, token)
+			missing := serve(http.MethodGet, "/api/v1/alerting/rules/c1", nil, token)
+
+			Expect(forbidden.Code).To(Equal(http.StatusNotFound))
+			Expect(missing.Code).To(Equal(http.StatusNotFound))
+			Expect(forbidden.Body.String()).ToNot(ContainSubstring("team-b"))
+		})
+
+		It("lists only the ambiguous candidates the user can get", func() {
+			Expect(serve(http.MethodGet, "/api/v1/alerting/rules/dup", nil, token).Code).To(Equal(http.StatusNotFound))
+
+			allow("get", "team-a")
+			w := serve(http.MethodGet, "/api/v1/alerting/rules/dup", nil, token)
+
+			Expect(w.Code).To(Equal(http.StatusConflict))
+			Expect(w.Body.String()).To(ContainSubstring("dup@team-a/rules/0/0"))
+			Expect(w.Body.String()).ToNot(ContainSubstring("team-b"))
+		})
+	})
+
+	Context("when deleting a rule", func() {
+		It("requires update and delete access to the rule PrometheusRule", func() {
+			allow("get", "team-a")
+			allow("delete", "team-a")
+			Expect(serve(http.MethodDelete, "/api/v1/alerting/rules/a1", nil, token).Code).To(Equal(http.StatusForbidden))
+
+			allow("update", "team-a")
+			Expect(serve(http.MethodDelete, "/api/v1/alerting/rules/a1", nil, token).Code).To(Equal(http.StatusNoContent))
+		})
+	})
+
+	Context("when creating a rule", func() {
+		It("requires create and update access in the target namespace", func() {
+			body, _ := json.Marshal(httprouter.CreateUserDefinedAlertRuleRequest{
+				AlertingRule:   monitoringv1.Rule{Alert: "a2", Expr: intstr.FromString("up == 0")},
+				PrometheusRule: management.PrometheusRuleOptions{Name: "rules", Namespace: "team-a"},
+			})
+
+			allow("create", "team-a")
+			Expect(serve(http.MethodPost, "/api/v1/alerting/rules", body, token).Code).To(Equal(http.StatusForbidden))
+
+			allow("update", "team-a")
+			Expect(serve(http.MethodPost, "/api/v1/alerting/rules", body, token).Code).To(Equal(http.StatusCreated))
+		})
+	})
+
+	Context("when bulk deleting rules", func() {
+		It("authorizes each rule independently", func() {
+			allow("get", "team-a")
+			allow("update", "team-a")
+			allow("delete", "team-a")
+			allow("get", "team-b")
+			allow("delete", "team-b")
+
+			body, _ := json.Marshal(httprouter.BulkDeleteUserDefinedAlertRulesRequest{
+				RuleIds: []string{"a1", "b1"},
+			})
+			w := serve(http.MethodDelete, "/api/v1/alerting/rules", body, token)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var resp httprouter.BulkDeleteUserDefinedAlertRulesResponse
+			Expect(json.NewDecoder(w.Body).Decode(&resp)).To(Succeed())
+			Expect(resp.Rules).To(HaveLen(2))
+			Expect(resp.Rules[0].StatusCode).To(Equal(http.StatusNoContent))
+			Expect(resp.Rules[1].StatusCode).To(Equal(http.StatusForbidden))
+
+			_, found, _ := mockK8sRules.Get(context.Background(), "team-b", "rules")
+			Expect(found).To(BeTrue())
+		})
+	})
+
+	Context("when getting cluster-wide alerts", func() {
+		It("requires access to the platform Prometheus API", func() {
+			Expect(serve(http.MethodGet, "/api/v1/alerting/alerts", nil, token).Code).To(Equal(http.StatusForbidden))
+
+			authorizer.Allow(username, k8s.ResourceAttributes{
+				Namespace:   "openshift-monitoring",
+				Verb:        "get",
+				Group:       "monitoring.coreos.com",
+				Resource:    "prometheuses",
+				Subresource: "api",
+				Name:        "k8s",
+			})
+			Expect(serve(http.MethodGet, "/api/v1/alerting/alerts", nil, token).Code).To(Equal(http.StatusOK))
+		})
+	})
+})
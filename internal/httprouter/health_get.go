@@ -3,14 +3,43 @@ package httprouter
 import (
 	"encoding/json"
 	"net/http"
+
+	"github.com/machadovilaca/alerts-ui-management/pkg/management"
 )
 
 type GetHealthResponse struct {
 	Status string `json:"status"`
 }
 
+type GetReadinessResponse struct {
+	Status     string                       `json:"status"`
+	Components []management.ComponentStatus `json:"components"`
+}
+
+// GetHealth reports whether the process is alive. It does not depend on any
+// component so that Kubernetes does not restart replicas for transient errors
 func (hr *httpRouter) GetHealth(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	_ = json.NewEncoder(w).Encode(GetHealthResponse{Status: "ok"})
 }
+
+// GetReadiness reports whether the replica is able to serve requests,
+// returning 503 Service Unavailable when any component is not ready
+func (hr *httpRouter) GetReadiness(w http.ResponseWriter, r *http.Request) {
+	readiness := hr.managementClient.CheckReadiness(r.Context())
+
+	response := GetReadinessResponse{
+		Status:     "ok",
+		Components: readiness.Components,
+	}
+	statusCode := http.StatusOK
+	if !readiness.Ready {
+		response.Status = "unavailable"
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(response)
+}
@@ -1,6 +1,7 @@
 package httprouter_test
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -9,6 +10,9 @@ import (
 	. "github.com/onsi/gomega"
 
 	"github.com/machadovilaca/alerts-ui-management/internal/httprouter"
+	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management/testutils"
 )
 
 var _ = Describe("GetHealth", func() {
@@ -46,3 +50,69 @@ var _ = Describe("GetHealth", func() {
 		})
 	})
 })
+
+var _ = Describe("GetLiveness", func() {
+	It("should return 200 OK without depending on any component", func() {
+		router := httprouter.New(nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/alerting/health/live", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		Expect(w.Code).To(Equal(http.StatusOK))
+	})
+})
+
+var _ = Describe("GetReadiness", func() {
+	var (
+		router   http.Handler
+		prSynced bool
+	)
+
+	BeforeEach(func() {
+		prSynced = true
+
+		mockK8s := &testutils.MockClient{
+			PrometheusRuleInformerFunc: func() k8s.PrometheusRuleInformerInterface {
+				return &testutils.MockPrometheusRuleInformerInterface{
+					HasSyncedFunc: func() bool { return prSynced },
+				}
+			},
+		}
+
+		mgmt := management.NewWithCustomMapper(context.Background(), mockK8s, &testutils.MockMapperClient{})
+		router = httprouter.New(mgmt)
+	})
+
+	It("should return 200 OK with the status of each component when ready", func() {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/alerting/health/ready", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		Expect(w.Code).To(Equal(http.StatusOK))
+
+		var response httprouter.GetReadinessResponse
+		Expect(json.NewDecoder(w.Body).Decode(&response)).To(Succeed())
+		Expect(response.Status).To(Equal("ok"))
+		Expect(response.Components).To(HaveLen(4))
+	})
+
+	It("should return 503 Service Unavailable when a component is not ready", func() {
+		prSynced = false
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/alerting/health/ready", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		Expect(w.Code).To(Equal(http.StatusServiceUnavailable))
+
+		var response httprouter.GetReadinessResponse
+		Expect(json.NewDecoder(w.Body).Decode(&response)).To(Succeed())
+		Expect(response.Status).To(Equal("unavailable"))
+		Expect(response.Components).To(ContainElement(management.ComponentStatus{
+			Name:    management.ComponentPrometheusRuleInformer,
+			Ready:   false,
+			Message: "informer has not synced",
+		}))
+	})
+})
@@ -1,33 +1,80 @@
 package httprouter
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/go-chi/chi/v5"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 
+	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
 	"github.com/machadovilaca/alerts-ui-management/pkg/management"
 )
 
 type httpRouter struct {
 	managementClient management.Client
+	authorizer       k8s.AuthInterface
 }
 
+// New creates a router without authentication, where all operations are
+// performed with the privileges of the service
 func New(managementClient management.Client) *chi.Mux {
+	return NewWithAuthorizer(managementClient, nil)
+}
+
+// NewWithAuthorizer creates a router that authenticates requests with their
+// bearer token and authorizes each operation as the request user
+// Health endpoints are not authenticated
+func NewWithAuthorizer(managementClient management.Client, authorizer k8s.AuthInterface) *chi.Mux {
 	httpRouter := &httpRouter{
 		managementClient: managementClient,
+		authorizer:       authorizer,
 	}
 
 	r := chi.NewRouter()
 
 	r.Get("/api/v1/alerting/health", httpRouter.GetHealth)
-	r.Get("/api/v1/alerting/alerts", httpRouter.GetAlerts)
-	r.Delete("/api/v1/alerting/rules", httpRouter.BulkDeleteUserDefinedAlertRules)
-	r.Delete("/api/v1/alerting/rules/{ruleId}", httpRouter.DeleteUserDefinedAlertRuleById)
+	r.Get("/api/v1/alerting/health/live", httpRouter.GetHealth)
+	r.Get("/api/v1/alerting/health/ready", httpRouter.GetReadiness)
+
+	r.Group(func(r chi.Router) {
+		if authorizer != nil {
+			r.Use(httpRouter.authenticate)
+		}
+
+		r.Get("/api/v1/alerting/alerts", httpRouter.GetAlerts)
+		r.Get("/api/v1/alerting/alerts/history", httpRouter.GetAlertHistory)
+		r.Post("/api/v1/alerting/alerts/silence", httpRouter.SilenceAlert)
+		r.Get("/api/v1/alerting/rules", httpRouter.GetRules)
+		r.Get("/api/v1/alerting/rules/{ruleId}", httpRouter.GetRuleById)
+		r.Post("/api/v1/alerting/rules", httpRouter.CreateUserDefinedAlertRule)
+		r.Put("/api/v1/alerting/rules/{ruleId}", httpRouter.UpdateUserDefinedAlertRule)
+		r.Patch("/api/v1/alerting/rules/{ruleId}/labels", httpRouter.UpdateAlertRuleLabels)
+		r.Post("/api/v1/alerting/rules/{ruleId}/move", httpRouter.MoveUserDefinedAlertRule)
+		r.Post("/api/v1/alerting/rules/{ruleId}/copy", httpRouter.CopyAlertRule)
+		r.Post("/api/v1/alerting/rules/{ruleId}/silence", httpRouter.SilenceAlertRule)
+		r.Delete("/api/v1/alerting/rules", httpRouter.BulkDeleteUserDefinedAlertRules)
+		r.Delete("/api/v1/alerting/rules/{ruleId}", httpRouter.DeleteUserDefinedAlertRuleById)
+
+		r.Get("/api/v1/alerting/rulegroups", httpRouter.GetRuleGroups)
+		r.Post("/api/v1/alerting/rulegroups", httpRouter.CreateRuleGroup)
+		r.Get("/api/v1/alerting/rulegroups/{namespace}/{prometheusRuleName}/{groupName}", httpRouter.GetRuleGroup)
+		r.Put("/api/v1/alerting/rulegroups/{namespace}/{prometheusRuleName}/{groupName}", httpRouter.UpdateRuleGroup)
+		r.Delete("/api/v1/alerting/rulegroups/{namespace}/{prometheusRuleName}/{groupName}", httpRouter.DeleteRuleGroup)
+
+		r.Get("/api/v1/alerting/silences", httpRouter.GetSilences)
+		r.Post("/api/v1/alerting/silences", httpRouter.CreateSilence)
+		r.Get("/api/v1/alerting/silences/{silenceId}", httpRouter.GetSilence)
+		r.Put("/api/v1/alerting/silences/{silenceId}", httpRouter.UpdateSilence)
+		r.Delete("/api/v1/alerting/silences/{silenceId}", httpRouter.ExpireSilence)
+	})
 
 	return r
 }
@@ -35,15 +82,49 @@ func New(managementClient management.Client) *chi.Mux {
 func writeError(w http.ResponseWriter, statusCode int, message string) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
-	_, _ = w.Write([]byte(`{"error":"` + message + `"}`))
+	_ = json.NewEncoder(w).Encode(ErrorResponse{Error: message})
+}
+
+// ErrorResponse is the body of error responses
+type ErrorResponse struct {
+	Error string `json:"error"`
 }
 
 func handleError(w http.ResponseWriter, err error) {
+	var ve *management.ValidationError
+	if errors.As(err, &ve) {
+		writeValidationError(w, ve)
+		return
+	}
+
 	status, message := parseError(err)
 	writeError(w, status, message)
 }
 
+// ValidationErrorResponse is the body of 422 responses, listing each invalid field
+type ValidationErrorResponse struct {
+	Error  string                  `json:"error"`
+	Fields []management.FieldError `json:"fields"`
+}
+
+func writeValidationError(w http.ResponseWriter, ve *management.ValidationError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	_ = json.NewEncoder(w).Encode(ValidationErrorResponse{
+		Error:  ve.Error(),
+		Fields: ve.Errors,
+	})
+}
+
 func parseError(err error) (int, string) {
+	var ve *management.ValidationError
+	if errors.As(err, &ve) {
+		return http.StatusUnprocessableEntity, err.Error()
+	}
+	var ia *management.InvalidArgumentError
+	if errors.As(err, &ia) {
+		return http.StatusBadRequest, err.Error()
+	}
 	var nf *management.NotFoundError
 	if errors.As(err, &nf) {
 		return http.StatusNotFound, err.Error()
@@ -52,6 +133,21 @@ func parseError(err error) (int, string) {
 	if errors.As(err, &na) {
 		return http.StatusMethodNotAllowed, err.Error()
 	}
+	var ce *management.ConflictError
+	if errors.As(err, &ce) {
+		return http.StatusConflict, err.Error()
+	}
+	var ar *management.AmbiguousAlertRuleError
+	if errors.As(err, &ar) {
+		return http.StatusConflict, err.Error()
+	}
+	var pf *management.PreconditionFailedError
+	if errors.As(err, &pf) {
+		return http.StatusPreconditionFailed, err.Error()
+	}
+	if apierrors.IsConflict(err) {
+		return http.StatusConflict, "the PrometheusRule was modified concurrently, please retry"
+	}
 	log.Printf("An unexpected error occurred: %v", err)
 	return http.StatusInternalServerError, "An unexpected error occurred"
 }
@@ -72,3 +168,81 @@ func getParam(r *http.Request, name string) (string, error) {
 	raw := chi.URLParam(r, name)
 	return parseParam(raw, name)
 }
+
+func getBearerToken(r *http.Request) string {
+	token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !found {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// formatETag returns the ETag of a PrometheusRule resourceVersion
+func formatETag(resourceVersion string) string {
+	return `"` + resourceVersion + `"`
+}
+
+// withIfMatch returns the request context carrying the resourceVersion of the
+// If-Match header, so that edits fail if the PrometheusRule has been changed
+func withIfMatch(r *http.Request) (context.Context, error) {
+	ifMatch := strings.TrimSpace(r.Header.Get("If-Match"))
+	if ifMatch == "" || ifMatch == "*" {
+		return r.Context(), nil
+	}
+
+	if strings.Contains(ifMatch, ",") || strings.HasPrefix(ifMatch, "W/") ||
+		len(ifMatch) < 3 || !strings.HasPrefix(ifMatch, `"`) || !strings.HasSuffix(ifMatch, `"`) {
+		return nil, fmt.Errorf("If-Match must be a single strong ETag")
+	}
+
+	return management.WithResourceVersion(r.Context(), ifMatch[1:len(ifMatch)-1]), nil
+}
+
+// DryRunResponse is the body of edits requested with dryRun=true, with the
+// response the edit would have returned and the changes it would have made
+type DryRunResponse struct {
+	Result  any                       `json:"result,omitempty"`
+	Changes []management.DryRunChange `json:"changes"`
+}
+
+// withDryRun returns ctx with management.WithDryRun applied if the request has
+// the dryRun=true query parameter, and the DryRunResult collecting its changes
+func withDryRun(ctx context.Context, r *http.Request) (context.Context, *management.DryRunResult, error) {
+	raw := r.URL.Query().Get("dryRun")
+	if raw == "" {
+		return ctx, nil, nil
+	}
+
+	dryRun, err := strconv.ParseBool(raw)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid dryRun: %s", raw)
+	}
+	if !dryRun {
+		return ctx, nil, nil
+	}
+
+	ctx, result := management.WithDryRun(ctx)
+	return ctx, result, nil
+}
+
+// writeEditResponse writes the response of an edit, or, for dry runs, a
+// DryRunResponse with status 200 since nothing was persisted
+func writeEditResponse(w http.ResponseWriter, statusCode int, body any, dryRun *management.DryRunResult) {
+	if dryRun != nil {
+		changes, err := dryRun.Changes()
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+		statusCode, body = http.StatusOK, DryRunResponse{Result: body, Changes: changes}
+	}
+
+	if body == nil {
+		w.WriteHeader(statusCode)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(body)
+}
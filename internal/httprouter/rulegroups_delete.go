@@ -0,0 +1,38 @@
+package httprouter
+
+import (
+	"net/http"
+)
+
+func (hr *httpRouter) DeleteRuleGroup(w http.ResponseWriter, req *http.Request) {
+	prId, groupName, err := getRuleGroupParams(req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Deleting a group updates the PrometheusRule, or deletes it with its last group
+	if !hr.authorize(w, req, prometheusRuleAttributes("update", prId)) ||
+		!hr.authorize(w, req, prometheusRuleAttributes("delete", prId)) {
+		return
+	}
+
+	ctx, err := withIfMatch(req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, dryRun, err := withDryRun(ctx, req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := hr.managementClient.DeleteRuleGroup(ctx, prId, groupName); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeEditResponse(w, http.StatusNoContent, nil, dryRun)
+}
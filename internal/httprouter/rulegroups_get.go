@@ -0,0 +1,121 @@
+package httprouter
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-playground/form/v4"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management"
+)
+
+type GetRuleGroupsQueryParams struct {
+	Namespace          string `form:"namespace"`
+	PrometheusRuleName string `form:"prometheusRuleName"`
+	GroupName          string `form:"groupName"`
+}
+
+type GetRuleGroupsResponse struct {
+	Data   GetRuleGroupsResponseData `json:"data"`
+	Status string                    `json:"status"`
+}
+
+type GetRuleGroupsResponseData struct {
+	Groups []management.RuleGroup `json:"groups"`
+}
+
+type GetRuleGroupResponse struct {
+	Data   GetRuleGroupResponseData `json:"data"`
+	Status string                   `json:"status"`
+}
+
+type GetRuleGroupResponseData struct {
+	Group management.RuleGroup `json:"group"`
+}
+
+func (hr *httpRouter) GetRuleGroups(w http.ResponseWriter, req *http.Request) {
+	var params GetRuleGroupsQueryParams
+
+	if err := form.NewDecoder().Decode(&params, req.URL.Query()); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid query parameters: "+err.Error())
+		return
+	}
+
+	if !hr.authorize(w, req, k8s.ResourceAttributes{
+		Namespace: params.Namespace,
+		Verb:      "list",
+		Group:     monitoringCoreOSGroup,
+		Resource:  prometheusRulesResource,
+	}) {
+		return
+	}
+
+	groups, err := hr.managementClient.ListRuleGroups(req.Context(), management.PrometheusRuleOptions{
+		Name:      params.PrometheusRuleName,
+		Namespace: params.Namespace,
+		GroupName: params.GroupName,
+	})
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(GetRuleGroupsResponse{
+		Data: GetRuleGroupsResponseData{
+			Groups: groups,
+		},
+		Status: "success",
+	})
+}
+
+func (hr *httpRouter) GetRuleGroup(w http.ResponseWriter, req *http.Request) {
+	prId, groupName, err := getRuleGroupParams(req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !hr.authorize(w, req, prometheusRuleAttributes("get", prId)) {
+		return
+	}
+
+	group, resourceVersion, err := hr.managementClient.GetRuleGroup(req.Context(), prId, groupName)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", formatETag(resourceVersion))
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(GetRuleGroupResponse{
+		Data: GetRuleGroupResponseData{
+			Group: group,
+		},
+		Status: "success",
+	})
+}
+
+// getRuleGroupParams returns the PrometheusRule and the group name of a rule group path
+func getRuleGroupParams(req *http.Request) (types.NamespacedName, string, error) {
+	namespace, err := getParam(req, "namespace")
+	if err != nil {
+		return types.NamespacedName{}, "", err
+	}
+
+	name, err := getParam(req, "prometheusRuleName")
+	if err != nil {
+		return types.NamespacedName{}, "", err
+	}
+
+	groupName, err := getParam(req, "groupName")
+	if err != nil {
+		return types.NamespacedName{}, "", err
+	}
+
+	return types.NamespacedName{Namespace: namespace, Name: name}, groupName, nil
+}
@@ -0,0 +1,55 @@
+package httprouter
+
+import (
+	"encoding/json"
+	"net/http"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/machadovilaca/alerts-ui-management/pkg/management"
+)
+
+type CreateRuleGroupRequest struct {
+	Group          monitoringv1.RuleGroup           `json:"group"`
+	PrometheusRule management.PrometheusRuleOptions `json:"prometheusRule"`
+}
+
+type CreateRuleGroupResponse struct {
+	Group management.RuleGroup `json:"group"`
+}
+
+func (hr *httpRouter) CreateRuleGroup(w http.ResponseWriter, req *http.Request) {
+	var payload CreateRuleGroupRequest
+	if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if payload.Group.Name == "" {
+		writeError(w, http.StatusBadRequest, "group.name is required")
+		return
+	}
+
+	// CreateRuleGroup updates the PrometheusRule if it exists and creates it otherwise
+	prId := types.NamespacedName{Namespace: payload.PrometheusRule.Namespace, Name: payload.PrometheusRule.Name}
+	if !hr.authorize(w, req, prometheusRuleAttributes("create", prId)) ||
+		!hr.authorize(w, req, prometheusRuleAttributes("update", prId)) {
+		return
+	}
+
+	ctx, dryRun, err := withDryRun(req.Context(), req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	group, err := hr.managementClient.CreateRuleGroup(ctx, prId, payload.Group)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeEditResponse(w, http.StatusCreated, CreateRuleGroupResponse{
+		Group: group,
+	}, dryRun)
+}
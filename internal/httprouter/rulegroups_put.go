@@ -0,0 +1,62 @@
+package httprouter
+
+import (
+	"encoding/json"
+	"net/http"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+
+	"github.com/machadovilaca/alerts-ui-management/pkg/management"
+)
+
+type UpdateRuleGroupRequest struct {
+	Group monitoringv1.RuleGroup `json:"group"`
+}
+
+type UpdateRuleGroupResponse struct {
+	Group management.RuleGroup `json:"group"`
+}
+
+func (hr *httpRouter) UpdateRuleGroup(w http.ResponseWriter, req *http.Request) {
+	prId, groupName, err := getRuleGroupParams(req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var payload UpdateRuleGroupRequest
+	if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if payload.Group.Name == "" {
+		writeError(w, http.StatusBadRequest, "group.name is required")
+		return
+	}
+
+	if !hr.authorize(w, req, prometheusRuleAttributes("update", prId)) {
+		return
+	}
+
+	ctx, err := withIfMatch(req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, dryRun, err := withDryRun(ctx, req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	group, err := hr.managementClient.UpdateRuleGroup(ctx, prId, groupName, payload.Group)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeEditResponse(w, http.StatusOK, UpdateRuleGroupResponse{
+		Group: group,
+	}, dryRun)
+}
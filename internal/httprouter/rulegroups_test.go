@@ -0,0 +1,147 @@
+package httprouter_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+
+	"github.com/machadovilaca/alerts-ui-management/internal/httprouter"
+	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management/testutils"
+)
+
+var _ = Describe("Rule groups", func() {
+	var (
+		router       http.Handler
+		mockK8sRules *testutils.MockPrometheusRuleInterface
+	)
+
+	BeforeEach(func() {
+		mockK8sRules = &testutils.MockPrometheusRuleInterface{}
+
+		userPR := monitoringv1.PrometheusRule{}
+		userPR.Name = "user-pr"
+		userPR.Namespace = "default"
+		userPR.ResourceVersion = "7"
+		userPR.Spec.Groups = []monitoringv1.RuleGroup{
+			{Name: "g1", Rules: []monitoringv1.Rule{{Alert: "u1"}, {Alert: "u2"}}},
+		}
+
+		mockK8sRules.SetPrometheusRules(map[string]*monitoringv1.PrometheusRule{
+			"default/user-pr": &userPR,
+		})
+
+		mockK8s := &testutils.MockClient{
+			PrometheusRulesFunc: func() k8s.PrometheusRuleInterface {
+				return mockK8sRules
+			},
+		}
+
+		mgmt := management.NewWithCustomMapper(context.Background(), mockK8s, &testutils.MockMapperClient{})
+		router = httprouter.New(mgmt)
+	})
+
+	serve := func(method string, target string, body interface{}, header http.Header) *httptest.ResponseRecorder {
+		var buf []byte
+		if body != nil {
+			buf, _ = json.Marshal(body)
+		}
+		req := httptest.NewRequest(method, target, bytes.NewReader(buf))
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	It("lists the rule groups of a namespace", func() {
+		w := serve(http.MethodGet, "/api/v1/alerting/rulegroups?namespace=default", nil, nil)
+
+		Expect(w.Code).To(Equal(http.StatusOK))
+		var resp httprouter.GetRuleGroupsResponse
+		Expect(json.NewDecoder(w.Body).Decode(&resp)).To(Succeed())
+		Expect(resp.Data.Groups).To(HaveLen(1))
+		Expect(resp.Data.Groups[0].Name).To(Equal("g1"))
+		Expect(resp.Data.Groups[0].RuleCount).To(Equal(2))
+	})
+
+	It("gets a rule group with the ETag of its PrometheusRule", func() {
+		w := serve(http.MethodGet, "/api/v1/alerting/rulegroups/default/user-pr/g1", nil, nil)
+
+		Expect(w.Code).To(Equal(http.StatusOK))
+		Expect(w.Header().Get("ETag")).To(Equal(`"7"`))
+
+		w = serve(http.MethodGet, "/api/v1/alerting/rulegroups/default/user-pr/missing", nil, nil)
+		Expect(w.Code).To(Equal(http.StatusNotFound))
+	})
+
+	It("creates a rule group and returns 409 for duplicate names", func() {
+		body := httprouter.CreateRuleGroupRequest{
+			Group:          monitoringv1.RuleGroup{Name: "g2"},
+			PrometheusRule: management.PrometheusRuleOptions{Namespace: "default", Name: "user-pr"},
+		}
+
+		Expect(serve(http.MethodPost, "/api/v1/alerting/rulegroups", body, nil).Code).To(Equal(http.StatusCreated))
+		Expect(serve(http.MethodPost, "/api/v1/alerting/rulegroups", body, nil).Code).To(Equal(http.StatusConflict))
+	})
+
+	It("returns 400 without a group name", func() {
+		body := httprouter.CreateRuleGroupRequest{
+			PrometheusRule: management.PrometheusRuleOptions{Namespace: "default", Name: "user-pr"},
+		}
+
+		Expect(serve(http.MethodPost, "/api/v1/alerting/rulegroups", body, nil).Code).To(Equal(http.StatusBadRequest))
+	})
+
+	It("updates a rule group with If-Match", func() {
+		body := httprouter.UpdateRuleGroupRequest{Group: monitoringv1.RuleGroup{Name: "renamed"}}
+
+		w := serve(http.MethodPut, "/api/v1/alerting/rulegroups/default/user-pr/g1", body, http.Header{"If-Match": {`"6"`}})
+		Expect(w.Code).To(Equal(http.StatusPreconditionFailed))
+
+		w = serve(http.MethodPut, "/api/v1/alerting/rulegroups/default/user-pr/g1", body, http.Header{"If-Match": {`"7"`}})
+		Expect(w.Code).To(Equal(http.StatusOK))
+		var resp httprouter.UpdateRuleGroupResponse
+		Expect(json.NewDecoder(w.Body).Decode(&resp)).To(Succeed())
+		Expect(resp.Group.Name).To(Equal("renamed"))
+		Expect(resp.Group.RuleCount).To(Equal(2))
+	})
+
+	It("deletes the PrometheusRule with its last group", func() {
+		w := serve(http.MethodDelete, "/api/v1/alerting/rulegroups/default/user-pr/g1", nil, nil)
+
+		Expect(w.Code).To(Equal(http.StatusNoContent))
+		_, found, _ := mockK8sRules.Get(context.Background(), "default", "user-pr")
+		Expect(found).To(BeFalse())
+	})
+
+	It("returns the deletion of the PrometheusRule without deleting it with dryRun=true", func() {
+		w := serve(http.MethodDelete, "/api/v1/alerting/rulegroups/default/user-pr/g1?dryRun=true", nil, nil)
+
+		Expect(w.Code).To(Equal(http.StatusOK))
+		var resp struct {
+			Changes []struct {
+				Operation string          `json:"operation"`
+				Name      string          `json:"name"`
+				Object    json.RawMessage `json:"object"`
+			} `json:"changes"`
+		}
+		Expect(json.NewDecoder(w.Body).Decode(&resp)).To(Succeed())
+		Expect(resp.Changes).To(HaveLen(1))
+		Expect(resp.Changes[0].Operation).To(Equal("delete"))
+		Expect(resp.Changes[0].Name).To(Equal("user-pr"))
+		Expect(resp.Changes[0].Object).To(BeEmpty())
+
+		_, found, _ := mockK8sRules.Get(context.Background(), "default", "user-pr")
+		Expect(found).To(BeTrue())
+	})
+})
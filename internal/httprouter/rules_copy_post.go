@@ -0,0 +1,44 @@
+package httprouter
+
+import (
+	"net/http"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type CopyAlertRuleRequest = MoveAlertRuleRequest
+
+type CopyAlertRuleResponse struct {
+	Id string `json:"id"`
+}
+
+func (hr *httpRouter) CopyAlertRule(w http.ResponseWriter, req *http.Request) {
+	ruleId, payload, ok := parseMoveAlertRuleRequest(w, req)
+	if !ok {
+		return
+	}
+
+	// CopyAlertRule updates the target PrometheusRule if it exists and creates it otherwise
+	prId := types.NamespacedName{Namespace: payload.PrometheusRule.Namespace, Name: payload.PrometheusRule.Name}
+	if !hr.authorizeAlertRule(w, req, ruleId, "get") ||
+		!hr.authorize(w, req, prometheusRuleAttributes("create", prId)) ||
+		!hr.authorize(w, req, prometheusRuleAttributes("update", prId)) {
+		return
+	}
+
+	ctx, dryRun, err := withDryRun(req.Context(), req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	newRuleId, err := hr.managementClient.CopyAlertRule(ctx, ruleId, payload.PrometheusRule)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeEditResponse(w, http.StatusCreated, CopyAlertRuleResponse{
+		Id: newRuleId,
+	}, dryRun)
+}
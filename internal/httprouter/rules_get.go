@@ -0,0 +1,124 @@
+package httprouter
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-playground/form/v4"
+
+	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management"
+)
+
+type GetRulesQueryParams struct {
+	Namespace          string            `form:"namespace"`
+	PrometheusRuleName string            `form:"prometheusRuleName"`
+	GroupName          string            `form:"groupName"`
+	Name               string            `form:"name"`
+	Type               string            `form:"type"`
+	Source             string            `form:"source"`
+	Labels             map[string]string `form:"labels"`
+	Loaded             *bool             `form:"loaded"`
+}
+
+type GetRulesResponse struct {
+	Data   GetRulesResponseData `json:"data"`
+	Status string               `json:"status"`
+}
+
+type GetRulesResponseData struct {
+	Rules []management.Rule `json:"rules"`
+}
+
+type GetRuleResponse struct {
+	Data   GetRuleResponseData `json:"data"`
+	Status string              `json:"status"`
+}
+
+type GetRuleResponseData struct {
+	Rule management.Rule `json:"rule"`
+}
+
+func (hr *httpRouter) GetRules(w http.ResponseWriter, req *http.Request) {
+	var params GetRulesQueryParams
+
+	if err := form.NewDecoder().Decode(&params, req.URL.Query()); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid query parameters: "+err.Error())
+		return
+	}
+
+	if params.Source != "" && params.Source != management.SourcePlatform && params.Source != management.SourceUserDefined {
+		writeError(w, http.StatusBadRequest, "source must be one of: platform, user-defined")
+		return
+	}
+
+	if params.Type != "" && params.Type != management.RuleTypeAlerting && params.Type != management.RuleTypeRecording {
+		writeError(w, http.StatusBadRequest, "type must be one of: alerting, recording")
+		return
+	}
+
+	if !hr.authorize(w, req, k8s.ResourceAttributes{
+		Namespace: params.Namespace,
+		Verb:      "list",
+		Group:     monitoringCoreOSGroup,
+		Resource:  prometheusRulesResource,
+	}) {
+		return
+	}
+
+	rules, err := hr.managementClient.ListRules(req.Context(),
+		management.PrometheusRuleOptions{
+			Name:      params.PrometheusRuleName,
+			Namespace: params.Namespace,
+			GroupName: params.GroupName,
+		},
+		management.AlertRuleOptions{
+			Name:   params.Name,
+			Type:   params.Type,
+			Source: params.Source,
+			Labels: params.Labels,
+			Loaded: params.Loaded,
+		},
+	)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(GetRulesResponse{
+		Data: GetRulesResponseData{
+			Rules: rules,
+		},
+		Status: "success",
+	})
+}
+
+func (hr *httpRouter) GetRuleById(w http.ResponseWriter, req *http.Request) {
+	ruleId, err := getParam(req, "ruleId")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !hr.authorizeAlertRule(w, req, ruleId, "get") {
+		return
+	}
+
+	rule, resourceVersion, err := hr.managementClient.GetRuleByIdWithResourceVersion(req.Context(), ruleId)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", formatETag(resourceVersion))
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(GetRuleResponse{
+		Data: GetRuleResponseData{
+			Rule: rule,
+		},
+		Status: "success",
+	})
+}
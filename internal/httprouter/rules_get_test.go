@@ -0,0 +1,315 @@
+package httprouter_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/machadovilaca/alerts-ui-management/internal/httprouter"
+	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management/mapper"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management/testutils"
+)
+
+var _ = Describe("GetRules", func() {
+	var (
+		router       http.Handler
+		mockK8sRules *testutils.MockPrometheusRuleInterface
+		mockK8s      *testutils.MockClient
+		mockMapper   *testutils.MockMapperClient
+	)
+
+	BeforeEach(func() {
+		mockK8sRules = &testutils.MockPrometheusRuleInterface{}
+
+		userPR := monitoringv1.PrometheusRule{}
+		userPR.Name = "user-pr"
+		userPR.Namespace = "default"
+		userPR.Spec.Groups = []monitoringv1.RuleGroup{
+			{
+				Name: "g1",
+				Rules: []monitoringv1.Rule{
+					{Alert: "u1", Expr: intstr.FromString("up == 0"), Labels: map[string]string{"severity": "warning"}},
+					{Alert: "u2", Expr: intstr.FromString("up == 1"), Labels: map[string]string{"severity": "critical"}},
+				},
+			},
+		}
+
+		platformPR := monitoringv1.PrometheusRule{}
+		platformPR.Name = "platform-pr"
+		platformPR.Namespace = "openshift-monitoring"
+		platformPR.Spec.Groups = []monitoringv1.RuleGroup{
+			{
+				Name:  "pg1",
+				Rules: []monitoringv1.Rule{{Alert: "p1", Expr: intstr.FromString("vector(1)")}},
+			},
+		}
+
+		mockK8sRules.SetPrometheusRules(map[string]*monitoringv1.PrometheusRule{
+			"default/user-pr":                  &userPR,
+			"openshift-monitoring/platform-pr": &platformPR,
+		})
+
+		mockK8s = &testutils.MockClient{
+			PrometheusRulesFunc: func() k8s.PrometheusRuleInterface {
+				return mockK8sRules
+			},
+		}
+
+		mockMapper = &testutils.MockMapperClient{
+			GetAlertingRuleIdFunc: func(rule *monitoringv1.Rule) mapper.PrometheusAlertRuleId {
+				return mapper.PrometheusAlertRuleId(rule.Alert + rule.Record)
+			},
+			FindAlertRuleByIdFunc: func(alertRuleId mapper.PrometheusAlertRuleId) (*mapper.PrometheusRuleId, error) {
+				switch alertRuleId {
+				case "u1", "u2", "job:up:sum":
+					return &mapper.PrometheusRuleId{Namespace: "default", Name: "user-pr"}, nil
+				case "p1":
+					return &mapper.PrometheusRuleId{Namespace: "openshift-monitoring", Name: "platform-pr"}, nil
+				}
+				return nil, fmt.Errorf("alert rule not found")
+			},
+		}
+
+		mockMapper.CachePrometheusRules(mockK8sRules)
+
+		mgmt := management.NewWithCustomMapper(context.Background(), mockK8s, mockMapper)
+		router = httprouter.New(mgmt)
+	})
+
+	Context("when listing rules", func() {
+		It("returns all rules with their alert_rule_id label", func() {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/alerting/rules", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(w.Header().Get("Content-Type")).To(Equal("application/json"))
+
+			var resp httprouter.GetRulesResponse
+			Expect(json.NewDecoder(w.Body).Decode(&resp)).To(Succeed())
+			Expect(resp.Status).To(Equal("success"))
+			Expect(resp.Data.Rules).To(HaveLen(3))
+			for _, rule := range resp.Data.Rules {
+				Expect(rule.Labels).To(HaveKeyWithValue("alert_rule_id", rule.Alert))
+			}
+		})
+
+		It("filters rules by namespace and labels", func() {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/alerting/rules?namespace=default&labels[severity]=critical", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+
+			var resp httprouter.GetRulesResponse
+			Expect(json.NewDecoder(w.Body).Decode(&resp)).To(Succeed())
+			Expect(resp.Data.Rules).To(HaveLen(1))
+			Expect(resp.Data.Rules[0].Alert).To(Equal("u2"))
+		})
+
+		It("returns the evaluation status of the rules and filters the rules Prometheus did not load", func() {
+			mockK8sRules.PrometheusRules["default/user-pr"].UID = "uid"
+			mockK8s.PrometheusRulesAPIFunc = func() k8s.PrometheusRulesAPIInterface {
+				return &testutils.MockPrometheusRulesAPIInterface{
+					RuleGroups: []k8s.LoadedRuleGroup{{
+						Name:  "g1",
+						File:  "/etc/prometheus/rules/prometheus-k8s-rulefiles-0/default-user-pr-uid.yaml",
+						Rules: []k8s.LoadedRule{{Name: "u1", Type: "alerting", Health: k8s.RuleHealthOk}, {Name: "u2", Type: "alerting", Health: k8s.RuleHealthErr, LastError: "boom"}},
+					}},
+				}
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/alerting/rules?namespace=default", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var resp httprouter.GetRulesResponse
+			Expect(json.NewDecoder(w.Body).Decode(&resp)).To(Succeed())
+			Expect(resp.Data.Rules).To(HaveLen(2))
+			Expect(resp.Data.Rules[1].Status).To(Equal(&management.RuleStatus{Loaded: true, Health: k8s.RuleHealthErr, LastError: "boom"}))
+
+			req = httptest.NewRequest(http.MethodGet, "/api/v1/alerting/rules?loaded=false", nil)
+			w = httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			resp = httprouter.GetRulesResponse{}
+			Expect(json.NewDecoder(w.Body).Decode(&resp)).To(Succeed())
+			Expect(resp.Data.Rules).To(HaveLen(1))
+			Expect(resp.Data.Rules[0].Alert).To(Equal("p1"))
+			Expect(resp.Data.Rules[0].Status.Loaded).To(BeFalse())
+		})
+
+		It("filters rules by source", func() {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/alerting/rules?source=platform", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+
+			var resp httprouter.GetRulesResponse
+			Expect(json.NewDecoder(w.Body).Decode(&resp)).To(Succeed())
+			Expect(resp.Data.Rules).To(HaveLen(1))
+			Expect(resp.Data.Rules[0].Alert).To(Equal("p1"))
+		})
+
+		It("filters rules by type", func() {
+			userPR := mockK8sRules.PrometheusRules["default/user-pr"]
+			userPR.Spec.Groups[0].Rules = append(userPR.Spec.Groups[0].Rules, monitoringv1.Rule{
+				Record: "job:up:sum",
+				Expr:   intstr.FromString("sum by (job) (up)"),
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/alerting/rules?type=recording", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+
+			var resp httprouter.GetRulesResponse
+			Expect(json.NewDecoder(w.Body).Decode(&resp)).To(Succeed())
+			Expect(resp.Data.Rules).To(HaveLen(1))
+			Expect(resp.Data.Rules[0].Record).To(Equal("job:up:sum"))
+			Expect(resp.Data.Rules[0].Type).To(Equal(management.RuleTypeRecording))
+			Expect(resp.Data.Rules[0].Labels).To(Equal(map[string]string{"alert_rule_id": "job:up:sum"}))
+		})
+
+		It("returns 400 for an unknown type", func() {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/alerting/rules?type=unknown", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("lists rules from a specific PrometheusRule group", func() {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/alerting/rules?namespace=default&prometheusRuleName=user-pr&groupName=g1&name=u1", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+
+			var resp httprouter.GetRulesResponse
+			Expect(json.NewDecoder(w.Body).Decode(&resp)).To(Succeed())
+			Expect(resp.Data.Rules).To(HaveLen(1))
+			Expect(resp.Data.Rules[0].Alert).To(Equal("u1"))
+		})
+
+		It("returns 400 when prometheusRuleName is set without namespace", func() {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/alerting/rules?prometheusRuleName=user-pr", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+			Expect(w.Body.String()).To(ContainSubstring("Namespace must be specified"))
+		})
+
+		It("returns 400 for an unknown source", func() {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/alerting/rules?source=unknown", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("returns 404 when the PrometheusRule does not exist", func() {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/alerting/rules?namespace=default&prometheusRuleName=missing", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+})
+
+var _ = Describe("GetRuleById", func() {
+	var (
+		router       http.Handler
+		mockK8sRules *testutils.MockPrometheusRuleInterface
+		mockK8s      *testutils.MockClient
+		mockMapper   *testutils.MockMapperClient
+	)
+
+	BeforeEach(func() {
+		mockK8sRules = &testutils.MockPrometheusRuleInterface{}
+
+		userPR := monitoringv1.PrometheusRule{}
+		userPR.Name = "user-pr"
+		userPR.Namespace = "default"
+		userPR.Spec.Groups = []monitoringv1.RuleGroup{
+			{
+				Name:  "g1",
+				Rules: []monitoringv1.Rule{{Alert: "u1", Labels: map[string]string{"severity": "warning"}}},
+			},
+		}
+
+		mockK8sRules.SetPrometheusRules(map[string]*monitoringv1.PrometheusRule{
+			"default/user-pr": &userPR,
+		})
+
+		mockK8s = &testutils.MockClient{
+			PrometheusRulesFunc: func() k8s.PrometheusRuleInterface {
+				return mockK8sRules
+			},
+		}
+
+		mockMapper = &testutils.MockMapperClient{
+			GetAlertingRuleIdFunc: func(rule *monitoringv1.Rule) mapper.PrometheusAlertRuleId {
+				return mapper.PrometheusAlertRuleId(rule.Alert)
+			},
+			FindAlertRuleByIdFunc: func(alertRuleId mapper.PrometheusAlertRuleId) (*mapper.PrometheusRuleId, error) {
+				if alertRuleId == "u1" {
+					return &mapper.PrometheusRuleId{Namespace: "default", Name: "user-pr"}, nil
+				}
+				return nil, fmt.Errorf("alert rule not found")
+			},
+		}
+
+		mgmt := management.NewWithCustomMapper(context.Background(), mockK8s, mockMapper)
+		router = httprouter.New(mgmt)
+	})
+
+	It("returns the rule with its alert_rule_id label", func() {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/alerting/rules/u1", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		Expect(w.Code).To(Equal(http.StatusOK))
+
+		var resp httprouter.GetRuleResponse
+		Expect(json.NewDecoder(w.Body).Decode(&resp)).To(Succeed())
+		Expect(resp.Status).To(Equal("success"))
+		Expect(resp.Data.Rule.Alert).To(Equal("u1"))
+		Expect(resp.Data.Rule.Labels).To(HaveKeyWithValue("severity", "warning"))
+		Expect(resp.Data.Rule.Labels).To(HaveKeyWithValue("alert_rule_id", "u1"))
+	})
+
+	It("returns 404 when the rule does not exist", func() {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/alerting/rules/missing", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		Expect(w.Code).To(Equal(http.StatusNotFound))
+		Expect(w.Body.String()).To(ContainSubstring("AlertRule with id missing not found"))
+	})
+
+	It("returns 400 when ruleId is blank", func() {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/alerting/rules/%20", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		Expect(w.Code).To(Equal(http.StatusBadRequest))
+		Expect(w.Body.String()).To(ContainSubstring("missing ruleId"))
+	})
+})
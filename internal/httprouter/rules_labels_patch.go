@@ -0,0 +1,83 @@
+package httprouter
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/machadovilaca/alerts-ui-management/pkg/management"
+)
+
+type UpdateAlertRuleLabelsRequest struct {
+	Labels map[string]string `json:"labels"`
+}
+
+func (hr *httpRouter) UpdateAlertRuleLabels(w http.ResponseWriter, req *http.Request) {
+	ruleId, err := getParam(req, "ruleId")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var payload UpdateAlertRuleLabelsRequest
+	if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if payload.Labels == nil {
+		writeError(w, http.StatusBadRequest, "labels is required")
+		return
+	}
+
+	if !hr.authorizeAlertRuleLabels(w, req, ruleId) {
+		return
+	}
+
+	ctx, err := withIfMatch(req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, dryRun, err := withDryRun(ctx, req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := hr.managementClient.UpdateAlertRuleLabels(ctx, ruleId, payload.Labels)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeEditResponse(w, http.StatusOK, result, dryRun)
+}
+
+// authorizeAlertRuleLabels checks whether the request user can override the
+// labels of platform rules through AlertRelabelConfigs, which are deleted when
+// the override is reverted, or update the PrometheusRule of user-defined rules
+func (hr *httpRouter) authorizeAlertRuleLabels(w http.ResponseWriter, req *http.Request, ruleId string) bool {
+	if hr.authorizer == nil {
+		return true
+	}
+
+	prId, status, message := hr.resolveAlertRule(req.Context(), ruleId)
+	if status != http.StatusOK {
+		writeError(w, status, message)
+		return false
+	}
+
+	source, err := hr.managementClient.GetPrometheusRuleSource(req.Context(), prId)
+	if err != nil {
+		handleError(w, err)
+		return false
+	}
+
+	if source == management.SourcePlatform {
+		return hr.authorize(w, req, platformAlertRelabelConfigAttributes("create")) &&
+			hr.authorize(w, req, platformAlertRelabelConfigAttributes("update")) &&
+			hr.authorize(w, req, platformAlertRelabelConfigAttributes("delete"))
+	}
+
+	return hr.authorize(w, req, prometheusRuleAttributes("update", prId))
+}
@@ -0,0 +1,178 @@
+package httprouter_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/machadovilaca/alerts-ui-management/internal/httprouter"
+	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management/mapper"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management/testutils"
+)
+
+var _ = Describe("UpdateAlertRuleLabels", func() {
+	var (
+		router       http.Handler
+		mockK8sRules *testutils.MockPrometheusRuleInterface
+		mockARC      *testutils.MockAlertRelabelConfigInterface
+		mockK8s      *testutils.MockClient
+		mockMapper   *testutils.MockMapperClient
+	)
+
+	BeforeEach(func() {
+		mockK8sRules = &testutils.MockPrometheusRuleInterface{}
+		mockARC = &testutils.MockAlertRelabelConfigInterface{}
+
+		userPR := monitoringv1.PrometheusRule{}
+		userPR.Name = "user-pr"
+		userPR.Namespace = "default"
+		userPR.Spec.Groups = []monitoringv1.RuleGroup{
+			{
+				Name:  "g1",
+				Rules: []monitoringv1.Rule{{Alert: "u1", Expr: intstr.FromString("up == 0"), Labels: map[string]string{"severity": "warning"}}},
+			},
+		}
+
+		platformPR := monitoringv1.PrometheusRule{}
+		platformPR.Name = "platform-pr"
+		platformPR.Namespace = "openshift-monitoring"
+		platformPR.Spec.Groups = []monitoringv1.RuleGroup{
+			{
+				Name:  "pg1",
+				Rules: []monitoringv1.Rule{{Alert: "p1", Expr: intstr.FromString("vector(1)"), Labels: map[string]string{"severity": "warning"}}},
+			},
+		}
+
+		mockK8sRules.SetPrometheusRules(map[string]*monitoringv1.PrometheusRule{
+			"default/user-pr":                  &userPR,
+			"openshift-monitoring/platform-pr": &platformPR,
+		})
+
+		mockK8s = &testutils.MockClient{
+			PrometheusRulesFunc: func() k8s.PrometheusRuleInterface {
+				return mockK8sRules
+			},
+			AlertRelabelConfigsFunc: func() k8s.AlertRelabelConfigInterface {
+				return mockARC
+			},
+		}
+
+		mockMapper = &testutils.MockMapperClient{
+			GetAlertingRuleIdFunc: func(rule *monitoringv1.Rule) mapper.PrometheusAlertRuleId {
+				return mapper.PrometheusAlertRuleId(rule.Alert + "-" + rule.Labels["severity"])
+			},
+			FindAlertRuleByIdFunc: func(alertRuleId mapper.PrometheusAlertRuleId) (*mapper.PrometheusRuleId, error) {
+				switch alertRuleId {
+				case "u1-warning":
+					return &mapper.PrometheusRuleId{Namespace: "default", Name: "user-pr"}, nil
+				case "p1-warning":
+					return &mapper.PrometheusRuleId{Namespace: "openshift-monitoring", Name: "platform-pr"}, nil
+				}
+				return nil, fmt.Errorf("alert rule not found")
+			},
+		}
+
+		mgmt := management.NewWithCustomMapper(context.Background(), mockK8s, mockMapper)
+		router = httprouter.New(mgmt)
+	})
+
+	patchLabels := func(ruleId string, body interface{}) *httptest.ResponseRecorder {
+		buf, _ := json.Marshal(body)
+		req := httptest.NewRequest(http.MethodPatch, "/api/v1/alerting/rules/"+ruleId+"/labels", bytes.NewReader(buf))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	It("creates an AlertRelabelConfig for a platform rule", func() {
+		w := patchLabels("p1-warning", httprouter.UpdateAlertRuleLabelsRequest{
+			Labels: map[string]string{"severity": "critical"},
+		})
+
+		Expect(w.Code).To(Equal(http.StatusOK))
+
+		var resp management.UpdateAlertRuleLabelsResult
+		Expect(json.NewDecoder(w.Body).Decode(&resp)).To(Succeed())
+		Expect(resp.AlertRuleId).To(Equal("p1-warning"))
+		Expect(resp.Source).To(Equal("platform"))
+		Expect(resp.AlertRelabelConfig).To(Equal("openshift-monitoring/alertmanagement-p1-warning"))
+		Expect(resp.RelabelConfigs).To(HaveLen(1))
+		Expect(resp.RelabelConfigs[0].TargetLabel).To(Equal("severity"))
+		Expect(resp.RelabelConfigs[0].Replacement).To(Equal("critical"))
+		Expect(resp.LabelChanges).To(ConsistOf(management.LabelChange{
+			Operation: "update", Label: "severity", OldValue: "warning", NewValue: "critical",
+		}))
+
+		_, found, err := mockARC.Get(context.Background(), "openshift-monitoring", "alertmanagement-p1-warning")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(BeTrue())
+	})
+
+	It("updates the PrometheusRule for a user-defined rule", func() {
+		w := patchLabels("u1-warning", httprouter.UpdateAlertRuleLabelsRequest{
+			Labels: map[string]string{"severity": "critical", "team": "app"},
+		})
+
+		Expect(w.Code).To(Equal(http.StatusOK))
+
+		var resp management.UpdateAlertRuleLabelsResult
+		Expect(json.NewDecoder(w.Body).Decode(&resp)).To(Succeed())
+		Expect(resp.AlertRuleId).To(Equal("u1-critical"))
+		Expect(resp.Source).To(Equal("user-defined"))
+		Expect(resp.PrometheusRule).To(Equal("default/user-pr"))
+		Expect(resp.RelabelConfigs).To(BeEmpty())
+		Expect(resp.LabelChanges).To(HaveLen(2))
+
+		pr, _, err := mockK8sRules.Get(context.Background(), "default", "user-pr")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pr.Spec.Groups[0].Rules[0].Labels).To(Equal(map[string]string{"severity": "critical", "team": "app"}))
+	})
+
+	It("returns 400 when labels are missing", func() {
+		w := patchLabels("u1-warning", map[string]interface{}{})
+
+		Expect(w.Code).To(Equal(http.StatusBadRequest))
+		Expect(w.Body.String()).To(ContainSubstring("labels is required"))
+	})
+
+	It("deletes the AlertRelabelConfig when the labels of a platform rule are reverted", func() {
+		Expect(patchLabels("p1-warning", httprouter.UpdateAlertRuleLabelsRequest{
+			Labels: map[string]string{"severity": "critical"},
+		}).Code).To(Equal(http.StatusOK))
+
+		w := patchLabels("p1-warning", httprouter.UpdateAlertRuleLabelsRequest{
+			Labels: map[string]string{"severity": "warning"},
+		})
+
+		Expect(w.Code).To(Equal(http.StatusOK))
+		var resp management.UpdateAlertRuleLabelsResult
+		Expect(json.NewDecoder(w.Body).Decode(&resp)).To(Succeed())
+		Expect(resp.AlertRelabelConfigDeleted).To(BeTrue())
+		Expect(resp.LabelChanges).To(ConsistOf(management.LabelChange{
+			Operation: "update", Label: "severity", OldValue: "critical", NewValue: "warning",
+		}))
+
+		_, found, err := mockARC.Get(context.Background(), "openshift-monitoring", "alertmanagement-p1-warning")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(BeFalse())
+	})
+
+	It("returns 404 when the rule does not exist", func() {
+		w := patchLabels("missing", httprouter.UpdateAlertRuleLabelsRequest{
+			Labels: map[string]string{"severity": "critical"},
+		})
+
+		Expect(w.Code).To(Equal(http.StatusNotFound))
+	})
+})
@@ -0,0 +1,76 @@
+package httprouter
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/machadovilaca/alerts-ui-management/pkg/management"
+)
+
+type MoveAlertRuleRequest struct {
+	PrometheusRule management.PrometheusRuleOptions `json:"prometheusRule"`
+}
+
+type MoveAlertRuleResponse struct {
+	Id string `json:"id"`
+}
+
+func (hr *httpRouter) MoveUserDefinedAlertRule(w http.ResponseWriter, req *http.Request) {
+	ruleId, payload, ok := parseMoveAlertRuleRequest(w, req)
+	if !ok {
+		return
+	}
+
+	// Moving removes the rule from its PrometheusRule, which is deleted with its
+	// last rule, and adds it to the target
+	prId := types.NamespacedName{Namespace: payload.PrometheusRule.Namespace, Name: payload.PrometheusRule.Name}
+	if !hr.authorizeAlertRule(w, req, ruleId, "update", "delete") ||
+		!hr.authorize(w, req, prometheusRuleAttributes("create", prId)) ||
+		!hr.authorize(w, req, prometheusRuleAttributes("update", prId)) {
+		return
+	}
+
+	ctx, err := withIfMatch(req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, dryRun, err := withDryRun(ctx, req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	newRuleId, err := hr.managementClient.MoveUserDefinedAlertRule(ctx, ruleId, payload.PrometheusRule)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeEditResponse(w, http.StatusOK, MoveAlertRuleResponse{
+		Id: newRuleId,
+	}, dryRun)
+}
+
+func parseMoveAlertRuleRequest(w http.ResponseWriter, req *http.Request) (string, MoveAlertRuleRequest, bool) {
+	ruleId, err := getParam(req, "ruleId")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return "", MoveAlertRuleRequest{}, false
+	}
+
+	var payload MoveAlertRuleRequest
+	if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return "", MoveAlertRuleRequest{}, false
+	}
+	if payload.PrometheusRule.Name == "" || payload.PrometheusRule.Namespace == "" {
+		writeError(w, http.StatusBadRequest, "prometheusRule.prometheusRuleName and prometheusRule.prometheusRuleNamespace are required")
+		return "", MoveAlertRuleRequest{}, false
+	}
+
+	return ruleId, payload, true
+}
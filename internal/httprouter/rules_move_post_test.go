@@ -0,0 +1,134 @@
+package httprouter_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+
+	"github.com/machadovilaca/alerts-ui-management/internal/httprouter"
+	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management/mapper"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management/testutils"
+)
+
+var _ = Describe("Move and copy alert rules", func() {
+	var (
+		router       http.Handler
+		mockK8sRules *testutils.MockPrometheusRuleInterface
+	)
+
+	BeforeEach(func() {
+		mockK8sRules = &testutils.MockPrometheusRuleInterface{}
+
+		userPR := monitoringv1.PrometheusRule{}
+		userPR.Name = "user-pr"
+		userPR.Namespace = "default"
+		userPR.ResourceVersion = "7"
+		userPR.Spec.Groups = []monitoringv1.RuleGroup{
+			{Name: "g1", Rules: []monitoringv1.Rule{{Alert: "u1"}, {Alert: "u2"}}},
+		}
+
+		platformPR := monitoringv1.PrometheusRule{}
+		platformPR.Name = "platform-pr"
+		platformPR.Namespace = "openshift-monitoring"
+		platformPR.Spec.Groups = []monitoringv1.RuleGroup{
+			{Name: "pg1", Rules: []monitoringv1.Rule{{Alert: "p1"}}},
+		}
+
+		mockK8sRules.SetPrometheusRules(map[string]*monitoringv1.PrometheusRule{
+			"default/user-pr":                  &userPR,
+			"openshift-monitoring/platform-pr": &platformPR,
+		})
+
+		mockK8s := &testutils.MockClient{
+			PrometheusRulesFunc: func() k8s.PrometheusRuleInterface {
+				return mockK8sRules
+			},
+		}
+
+		mockMapper := &testutils.MockMapperClient{
+			GetAlertingRuleIdFunc: func(rule *monitoringv1.Rule) mapper.PrometheusAlertRuleId {
+				return mapper.PrometheusAlertRuleId(rule.Alert)
+			},
+			FindAlertRuleByIdFunc: func(alertRuleId mapper.PrometheusAlertRuleId) (*mapper.PrometheusRuleId, error) {
+				switch alertRuleId {
+				case "u1", "u2":
+					return &mapper.PrometheusRuleId{Namespace: "default", Name: "user-pr"}, nil
+				case "p1":
+					return &mapper.PrometheusRuleId{Namespace: "openshift-monitoring", Name: "platform-pr"}, nil
+				}
+				return nil, fmt.Errorf("alert rule not found")
+			},
+		}
+
+		mgmt := management.NewWithCustomMapper(context.Background(), mockK8s, mockMapper)
+		router = httprouter.New(mgmt)
+	})
+
+	post := func(target string, body interface{}, header http.Header) *httptest.ResponseRecorder {
+		buf, _ := json.Marshal(body)
+		req := httptest.NewRequest(http.MethodPost, target, bytes.NewReader(buf))
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	targetBody := httprouter.MoveAlertRuleRequest{
+		PrometheusRule: management.PrometheusRuleOptions{Namespace: "team-a", Name: "rules", GroupName: "moved"},
+	}
+
+	It("moves a user-defined rule to another PrometheusRule", func() {
+		w := post("/api/v1/alerting/rules/u1/move", targetBody, nil)
+
+		Expect(w.Code).To(Equal(http.StatusOK))
+		var resp httprouter.MoveAlertRuleResponse
+		Expect(json.NewDecoder(w.Body).Decode(&resp)).To(Succeed())
+		Expect(resp.Id).To(Equal("u1"))
+
+		source, _, _ := mockK8sRules.Get(context.Background(), "default", "user-pr")
+		Expect(source.Spec.Groups[0].Rules).To(HaveLen(1))
+		target, found, _ := mockK8sRules.Get(context.Background(), "team-a", "rules")
+		Expect(found).To(BeTrue())
+		Expect(target.Spec.Groups[0].Rules[0].Alert).To(Equal("u1"))
+	})
+
+	It("returns 412 if the source PrometheusRule has changed", func() {
+		w := post("/api/v1/alerting/rules/u1/move", targetBody, http.Header{"If-Match": {`"6"`}})
+
+		Expect(w.Code).To(Equal(http.StatusPreconditionFailed))
+		_, found, _ := mockK8sRules.Get(context.Background(), "team-a", "rules")
+		Expect(found).To(BeFalse())
+	})
+
+	It("returns 405 when moving a platform rule", func() {
+		Expect(post("/api/v1/alerting/rules/p1/move", targetBody, nil).Code).To(Equal(http.StatusMethodNotAllowed))
+	})
+
+	It("returns 400 without a target PrometheusRule", func() {
+		Expect(post("/api/v1/alerting/rules/u1/move", httprouter.MoveAlertRuleRequest{}, nil).Code).To(Equal(http.StatusBadRequest))
+	})
+
+	It("copies a platform rule to a user-defined PrometheusRule", func() {
+		w := post("/api/v1/alerting/rules/p1/copy", targetBody, nil)
+
+		Expect(w.Code).To(Equal(http.StatusCreated))
+		target, found, _ := mockK8sRules.Get(context.Background(), "team-a", "rules")
+		Expect(found).To(BeTrue())
+		Expect(target.Spec.Groups[0].Rules[0].Alert).To(Equal("p1"))
+
+		platform, _, _ := mockK8sRules.Get(context.Background(), "openshift-monitoring", "platform-pr")
+		Expect(platform.Spec.Groups[0].Rules).To(HaveLen(1))
+	})
+})
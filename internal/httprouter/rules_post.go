@@ -0,0 +1,55 @@
+package httprouter
+
+import (
+	"encoding/json"
+	"net/http"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/machadovilaca/alerts-ui-management/pkg/management"
+)
+
+type CreateUserDefinedAlertRuleRequest struct {
+	AlertingRule   monitoringv1.Rule                `json:"alertingRule"`
+	PrometheusRule management.PrometheusRuleOptions `json:"prometheusRule"`
+}
+
+type CreateUserDefinedAlertRuleResponse struct {
+	Id string `json:"id"`
+}
+
+func (hr *httpRouter) CreateUserDefinedAlertRule(w http.ResponseWriter, req *http.Request) {
+	var payload CreateUserDefinedAlertRuleRequest
+	if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if payload.AlertingRule.Alert == "" && payload.AlertingRule.Record == "" {
+		writeError(w, http.StatusBadRequest, "alertingRule.alert or alertingRule.record is required")
+		return
+	}
+
+	// AddRule updates the PrometheusRule if it exists and creates it otherwise
+	prId := types.NamespacedName{Namespace: payload.PrometheusRule.Namespace, Name: payload.PrometheusRule.Name}
+	if !hr.authorize(w, req, prometheusRuleAttributes("create", prId)) ||
+		!hr.authorize(w, req, prometheusRuleAttributes("update", prId)) {
+		return
+	}
+
+	ctx, dryRun, err := withDryRun(req.Context(), req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	id, err := hr.managementClient.CreateUserDefinedAlertRule(ctx, payload.AlertingRule, payload.PrometheusRule)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeEditResponse(w, http.StatusCreated, CreateUserDefinedAlertRuleResponse{
+		Id: id,
+	}, dryRun)
+}
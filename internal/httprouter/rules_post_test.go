@@ -0,0 +1,229 @@
+package httprouter_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/machadovilaca/alerts-ui-management/internal/httprouter"
+	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management/mapper"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management/testutils"
+)
+
+var _ = Describe("CreateUserDefinedAlertRule", func() {
+	var (
+		router       http.Handler
+		mockK8sRules *testutils.MockPrometheusRuleInterface
+		mockK8s      *testutils.MockClient
+		mockMapper   *testutils.MockMapperClient
+	)
+
+	BeforeEach(func() {
+		mockK8sRules = &testutils.MockPrometheusRuleInterface{}
+		mockK8s = &testutils.MockClient{
+			PrometheusRulesFunc: func() k8s.PrometheusRuleInterface {
+				return mockK8sRules
+			},
+		}
+
+		mockMapper = &testutils.MockMapperClient{
+			GetAlertingRuleIdFunc: func(rule *monitoringv1.Rule) mapper.PrometheusAlertRuleId {
+				return mapper.PrometheusAlertRuleId(rule.Alert)
+			},
+			FindAlertRuleByIdFunc: func(alertRuleId mapper.PrometheusAlertRuleId) (*mapper.PrometheusRuleId, error) {
+				if alertRuleId == "existing" {
+					return &mapper.PrometheusRuleId{Namespace: "default", Name: "user-pr"}, nil
+				}
+				return nil, fmt.Errorf("alert rule not found")
+			},
+		}
+
+		mgmt := management.NewWithCustomMapper(context.Background(), mockK8s, mockMapper)
+		router = httprouter.New(mgmt)
+	})
+
+	postRule := func(body interface{}) *httptest.ResponseRecorder {
+		buf, _ := json.Marshal(body)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/alerting/rules", bytes.NewReader(buf))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	It("creates the rule and returns its id", func() {
+		w := postRule(map[string]interface{}{
+			"alertingRule": map[string]interface{}{
+				"alert": "NewAlert",
+				"expr":  "up == 0",
+			},
+			"prometheusRule": map[string]interface{}{
+				"prometheusRuleName":      "user-pr",
+				"prometheusRuleNamespace": "default",
+				"groupName":               "custom",
+			},
+		})
+
+		Expect(w.Code).To(Equal(http.StatusCreated))
+		var resp httprouter.CreateUserDefinedAlertRuleResponse
+		Expect(json.NewDecoder(w.Body).Decode(&resp)).To(Succeed())
+		Expect(resp.Id).To(Equal("NewAlert"))
+
+		pr, found, err := mockK8sRules.Get(context.Background(), "default", "user-pr")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeTrue())
+		Expect(pr.Spec.Groups).To(HaveLen(1))
+		Expect(pr.Spec.Groups[0].Name).To(Equal("custom"))
+		Expect(pr.Spec.Groups[0].Rules[0].Alert).To(Equal("NewAlert"))
+		Expect(pr.Spec.Groups[0].Rules[0].Expr.String()).To(Equal("up == 0"))
+	})
+
+	It("returns the changes without creating the rule with dryRun=true", func() {
+		buf, _ := json.Marshal(map[string]interface{}{
+			"alertingRule":   map[string]interface{}{"alert": "NewAlert", "expr": "up == 0"},
+			"prometheusRule": map[string]interface{}{"prometheusRuleName": "user-pr", "prometheusRuleNamespace": "default"},
+		})
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/alerting/rules?dryRun=true", bytes.NewReader(buf))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		Expect(w.Code).To(Equal(http.StatusOK))
+		var resp struct {
+			Result  httprouter.CreateUserDefinedAlertRuleResponse `json:"result"`
+			Changes []struct {
+				Operation string                      `json:"operation"`
+				Kind      string                      `json:"kind"`
+				Object    monitoringv1.PrometheusRule `json:"object"`
+				Diff      string                      `json:"diff"`
+			} `json:"changes"`
+		}
+		Expect(json.NewDecoder(w.Body).Decode(&resp)).To(Succeed())
+		Expect(resp.Result.Id).To(Equal("NewAlert"))
+		Expect(resp.Changes).To(HaveLen(1))
+		Expect(resp.Changes[0].Operation).To(Equal("create"))
+		Expect(resp.Changes[0].Kind).To(Equal("PrometheusRule"))
+		Expect(resp.Changes[0].Object.Spec.Groups[0].Rules[0].Alert).To(Equal("NewAlert"))
+		Expect(resp.Changes[0].Diff).To(ContainSubstring("+    - alert: NewAlert"))
+
+		_, found, err := mockK8sRules.Get(context.Background(), "default", "user-pr")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeFalse())
+	})
+
+	It("returns 400 for an invalid dryRun", func() {
+		buf, _ := json.Marshal(map[string]interface{}{
+			"alertingRule":   map[string]interface{}{"alert": "NewAlert", "expr": "up == 0"},
+			"prometheusRule": map[string]interface{}{"prometheusRuleName": "user-pr", "prometheusRuleNamespace": "default"},
+		})
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/alerting/rules?dryRun=maybe", bytes.NewReader(buf))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		Expect(w.Code).To(Equal(http.StatusBadRequest))
+		Expect(mockK8sRules.PrometheusRules).To(BeEmpty())
+	})
+
+	It("escapes the request values echoed in error messages", func() {
+		buf, _ := json.Marshal(map[string]interface{}{
+			"alertingRule":   map[string]interface{}{"alert": "NewAlert", "expr": "up == 0"},
+			"prometheusRule": map[string]interface{}{"prometheusRuleName": "user-pr", "prometheusRuleNamespace": "default"},
+		})
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/alerting/rules?dryRun=a%22b%5C", bytes.NewReader(buf))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		Expect(w.Code).To(Equal(http.StatusBadRequest))
+		var response httprouter.ErrorResponse
+		Expect(json.NewDecoder(w.Body).Decode(&response)).To(Succeed())
+		Expect(response.Error).To(Equal(`invalid dryRun: a"b\`))
+	})
+
+	It("returns 400 for an invalid body", func() {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/alerting/rules", bytes.NewBufferString("{"))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		Expect(w.Code).To(Equal(http.StatusBadRequest))
+		Expect(w.Body.String()).To(ContainSubstring("invalid request body"))
+	})
+
+	It("returns 400 when the PrometheusRule is not specified", func() {
+		w := postRule(map[string]interface{}{
+			"alertingRule": map[string]interface{}{"alert": "NewAlert", "expr": "up == 0"},
+		})
+
+		Expect(w.Code).To(Equal(http.StatusBadRequest))
+		Expect(w.Body.String()).To(ContainSubstring("PrometheusRule Name and Namespace must be specified"))
+	})
+
+	It("returns 405 when targeting a platform PrometheusRule", func() {
+		w := postRule(map[string]interface{}{
+			"alertingRule": map[string]interface{}{"alert": "NewAlert", "expr": "up == 0"},
+			"prometheusRule": map[string]interface{}{
+				"prometheusRuleName":      "platform-pr",
+				"prometheusRuleNamespace": "openshift-monitoring",
+			},
+		})
+
+		Expect(w.Code).To(Equal(http.StatusMethodNotAllowed))
+		Expect(w.Body.String()).To(ContainSubstring("platform-managed"))
+	})
+
+	It("returns 409 when a rule with the same config already exists in the PrometheusRule", func() {
+		mockK8sRules.SetPrometheusRules(map[string]*monitoringv1.PrometheusRule{
+			"default/user-pr": {
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "user-pr"},
+				Spec: monitoringv1.PrometheusRuleSpec{
+					Groups: []monitoringv1.RuleGroup{{Name: "g1", Rules: []monitoringv1.Rule{{Alert: "existing", Expr: intstr.FromString("up == 0")}}}},
+				},
+			},
+		})
+		mockMapper.CachePrometheusRules(mockK8sRules)
+
+		w := postRule(map[string]interface{}{
+			"alertingRule": map[string]interface{}{"alert": "existing", "expr": "up == 0"},
+			"prometheusRule": map[string]interface{}{
+				"prometheusRuleName":      "user-pr",
+				"prometheusRuleNamespace": "default",
+			},
+		})
+
+		Expect(w.Code).To(Equal(http.StatusConflict))
+		Expect(w.Body.String()).To(ContainSubstring("alert rule with exact config already exists"))
+	})
+
+	It("returns 422 with the invalid fields when the rule would fail to load", func() {
+		w := postRule(map[string]interface{}{
+			"alertingRule": map[string]interface{}{
+				"alert":  "NewAlert",
+				"expr":   "sum(up",
+				"for":    "5 minutes",
+				"labels": map[string]string{"severity": "warning"},
+			},
+			"prometheusRule": map[string]interface{}{
+				"prometheusRuleName":      "user-pr",
+				"prometheusRuleNamespace": "default",
+			},
+		})
+
+		Expect(w.Code).To(Equal(http.StatusUnprocessableEntity))
+		var resp httprouter.ValidationErrorResponse
+		Expect(json.NewDecoder(w.Body).Decode(&resp)).To(Succeed())
+		Expect(resp.Fields).To(HaveLen(2))
+		Expect(resp.Fields[0].Field).To(Equal("expr"))
+		Expect(resp.Fields[0].Message).To(ContainSubstring("invalid PromQL expression"))
+		Expect(resp.Fields[1].Field).To(Equal("for"))
+		Expect(resp.Fields[1].Message).To(ContainSubstring(`invalid duration "5 minutes"`))
+	})
+})
@@ -0,0 +1,60 @@
+package httprouter
+
+import (
+	"encoding/json"
+	"net/http"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+)
+
+type UpdateUserDefinedAlertRuleRequest struct {
+	AlertingRule monitoringv1.Rule `json:"alertingRule"`
+}
+
+type UpdateUserDefinedAlertRuleResponse struct {
+	Id string `json:"id"`
+}
+
+func (hr *httpRouter) UpdateUserDefinedAlertRule(w http.ResponseWriter, req *http.Request) {
+	ruleId, err := getParam(req, "ruleId")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var payload UpdateUserDefinedAlertRuleRequest
+	if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if payload.AlertingRule.Alert == "" && payload.AlertingRule.Record == "" {
+		writeError(w, http.StatusBadRequest, "alertingRule.alert or alertingRule.record is required")
+		return
+	}
+
+	if !hr.authorizeAlertRule(w, req, ruleId, "update") {
+		return
+	}
+
+	ctx, err := withIfMatch(req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, dryRun, err := withDryRun(ctx, req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	newRuleId, err := hr.managementClient.UpdateUserDefinedAlertRule(ctx, ruleId, payload.AlertingRule)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeEditResponse(w, http.StatusOK, UpdateUserDefinedAlertRuleResponse{
+		Id: newRuleId,
+	}, dryRun)
+}
@@ -0,0 +1,157 @@
+package httprouter_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+
+	"github.com/machadovilaca/alerts-ui-management/internal/httprouter"
+	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management/mapper"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management/testutils"
+)
+
+var _ = Describe("UpdateUserDefinedAlertRule", func() {
+	var (
+		router       http.Handler
+		mockK8sRules *testutils.MockPrometheusRuleInterface
+		mockK8s      *testutils.MockClient
+		mockMapper   *testutils.MockMapperClient
+	)
+
+	BeforeEach(func() {
+		mockK8sRules = &testutils.MockPrometheusRuleInterface{}
+
+		userPR := monitoringv1.PrometheusRule{}
+		userPR.Name = "user-pr"
+		userPR.Namespace = "default"
+		userPR.ResourceVersion = "7"
+		userPR.Spec.Groups = []monitoringv1.RuleGroup{
+			{
+				Name:  "g1",
+				Rules: []monitoringv1.Rule{{Alert: "u1"}, {Alert: "u2"}},
+			},
+		}
+
+		mockK8sRules.SetPrometheusRules(map[string]*monitoringv1.PrometheusRule{
+			"default/user-pr": &userPR,
+		})
+
+		mockK8s = &testutils.MockClient{
+			PrometheusRulesFunc: func() k8s.PrometheusRuleInterface {
+				return mockK8sRules
+			},
+		}
+
+		mockMapper = &testutils.MockMapperClient{
+			GetAlertingRuleIdFunc: func(rule *monitoringv1.Rule) mapper.PrometheusAlertRuleId {
+				return mapper.PrometheusAlertRuleId(rule.Alert)
+			},
+			FindAlertRuleByIdFunc: func(alertRuleId mapper.PrometheusAlertRuleId) (*mapper.PrometheusRuleId, error) {
+				switch alertRuleId {
+				case "u1", "u2":
+					return &mapper.PrometheusRuleId{Namespace: "default", Name: "user-pr"}, nil
+				case "p1":
+					return &mapper.PrometheusRuleId{Namespace: "openshift-monitoring", Name: "platform-pr"}, nil
+				}
+				return nil, fmt.Errorf("alert rule not found")
+			},
+		}
+
+		mgmt := management.NewWithCustomMapper(context.Background(), mockK8s, mockMapper)
+		router = httprouter.New(mgmt)
+	})
+
+	putRule := func(ruleId string, body interface{}) *httptest.ResponseRecorder {
+		buf, _ := json.Marshal(body)
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/alerting/rules/"+ruleId, bytes.NewReader(buf))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	It("updates the rule and returns its new id", func() {
+		w := putRule("u1", map[string]interface{}{
+			"alertingRule": map[string]interface{}{"alert": "u1-renamed", "expr": "up == 1"},
+		})
+
+		Expect(w.Code).To(Equal(http.StatusOK))
+		var resp httprouter.UpdateUserDefinedAlertRuleResponse
+		Expect(json.NewDecoder(w.Body).Decode(&resp)).To(Succeed())
+		Expect(resp.Id).To(Equal("u1-renamed"))
+
+		pr, found, err := mockK8sRules.Get(context.Background(), "default", "user-pr")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeTrue())
+		Expect(pr.Spec.Groups[0].Rules[0].Alert).To(Equal("u1-renamed"))
+		Expect(pr.Spec.Groups[0].Rules[1].Alert).To(Equal("u2"))
+	})
+
+	It("returns 400 for an invalid body", func() {
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/alerting/rules/u1", bytes.NewBufferString("{"))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		Expect(w.Code).To(Equal(http.StatusBadRequest))
+	})
+
+	It("returns 404 when the rule does not exist", func() {
+		w := putRule("missing", map[string]interface{}{
+			"alertingRule": map[string]interface{}{"alert": "missing", "expr": "up == 1"},
+		})
+
+		Expect(w.Code).To(Equal(http.StatusNotFound))
+		Expect(w.Body.String()).To(ContainSubstring("AlertRule with id missing not found"))
+	})
+
+	It("returns 405 for platform rules", func() {
+		w := putRule("p1", map[string]interface{}{
+			"alertingRule": map[string]interface{}{"alert": "p1", "expr": "up == 1"},
+		})
+
+		Expect(w.Code).To(Equal(http.StatusMethodNotAllowed))
+		Expect(w.Body.String()).To(ContainSubstring("platform-managed"))
+	})
+
+	Context("with an If-Match header", func() {
+		putRuleIfMatch := func(ifMatch string) *httptest.ResponseRecorder {
+			buf, _ := json.Marshal(map[string]interface{}{
+				"alertingRule": map[string]interface{}{"alert": "u1-renamed", "expr": "up == 1"},
+			})
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/alerting/rules/u1", bytes.NewReader(buf))
+			req.Header.Set("If-Match", ifMatch)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			return w
+		}
+
+		It("updates the rule when the ETag returned by GET matches", func() {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/alerting/rules/u1", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			Expect(w.Header().Get("ETag")).To(Equal(`"7"`))
+
+			Expect(putRuleIfMatch(w.Header().Get("ETag")).Code).To(Equal(http.StatusOK))
+		})
+
+		It("returns 412 when the PrometheusRule has changed", func() {
+			w := putRuleIfMatch(`"6"`)
+
+			Expect(w.Code).To(Equal(http.StatusPreconditionFailed))
+			Expect(w.Body.String()).To(ContainSubstring("expected 6"))
+		})
+
+		It("returns 400 for a weak ETag", func() {
+			Expect(putRuleIfMatch(`W/"7"`).Code).To(Equal(http.StatusBadRequest))
+		})
+	})
+})
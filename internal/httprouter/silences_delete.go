@@ -0,0 +1,24 @@
+package httprouter
+
+import (
+	"net/http"
+)
+
+func (hr *httpRouter) ExpireSilence(w http.ResponseWriter, req *http.Request) {
+	silenceId, err := getParam(req, "silenceId")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !hr.authorize(w, req, alertmanagerAPIAttributes("delete")) {
+		return
+	}
+
+	if err := hr.managementClient.ExpireSilence(req.Context(), silenceId); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
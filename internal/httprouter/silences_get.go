@@ -0,0 +1,96 @@
+package httprouter
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-playground/form/v4"
+
+	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
+)
+
+type GetSilencesQueryParams struct {
+	Labels map[string]string `form:"labels"`
+	State  string            `form:"state"`
+}
+
+type GetSilencesResponse struct {
+	Data   GetSilencesResponseData `json:"data"`
+	Status string                  `json:"status"`
+}
+
+type GetSilencesResponseData struct {
+	Silences []k8s.Silence `json:"silences"`
+}
+
+type GetSilenceResponse struct {
+	Data   GetSilenceResponseData `json:"data"`
+	Status string                 `json:"status"`
+}
+
+type GetSilenceResponseData struct {
+	Silence k8s.Silence `json:"silence"`
+}
+
+func (hr *httpRouter) GetSilences(w http.ResponseWriter, req *http.Request) {
+	var params GetSilencesQueryParams
+
+	if err := form.NewDecoder().Decode(&params, req.URL.Query()); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid query parameters: "+err.Error())
+		return
+	}
+
+	if params.State != "" && params.State != k8s.SilenceStateActive && params.State != k8s.SilenceStatePending && params.State != k8s.SilenceStateExpired {
+		writeError(w, http.StatusBadRequest, "state must be one of: active, pending, expired")
+		return
+	}
+
+	if !hr.authorize(w, req, alertmanagerAPIAttributes("get")) {
+		return
+	}
+
+	silences, err := hr.managementClient.ListSilences(req.Context(), k8s.ListSilencesRequest{
+		Labels: params.Labels,
+		State:  params.State,
+	})
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(GetSilencesResponse{
+		Data: GetSilencesResponseData{
+			Silences: silences,
+		},
+		Status: "success",
+	})
+}
+
+func (hr *httpRouter) GetSilence(w http.ResponseWriter, req *http.Request) {
+	silenceId, err := getParam(req, "silenceId")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !hr.authorize(w, req, alertmanagerAPIAttributes("get")) {
+		return
+	}
+
+	silence, err := hr.managementClient.GetSilence(req.Context(), silenceId)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(GetSilenceResponse{
+		Data: GetSilenceResponseData{
+			Silence: silence,
+		},
+		Status: "success",
+	})
+}
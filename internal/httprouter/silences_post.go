@@ -0,0 +1,40 @@
+package httprouter
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
+)
+
+type CreateSilenceRequest struct {
+	Silence k8s.Silence `json:"silence"`
+}
+
+type CreateSilenceResponse struct {
+	Id string `json:"id"`
+}
+
+func (hr *httpRouter) CreateSilence(w http.ResponseWriter, req *http.Request) {
+	var payload CreateSilenceRequest
+	if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if !hr.authorize(w, req, alertmanagerAPIAttributes("create")) {
+		return
+	}
+
+	id, err := hr.managementClient.CreateSilence(req.Context(), payload.Silence)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(CreateSilenceResponse{
+		Id: id,
+	})
+}
@@ -0,0 +1,47 @@
+package httprouter
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
+)
+
+type UpdateSilenceRequest struct {
+	Silence k8s.Silence `json:"silence"`
+}
+
+type UpdateSilenceResponse struct {
+	Id string `json:"id"`
+}
+
+func (hr *httpRouter) UpdateSilence(w http.ResponseWriter, req *http.Request) {
+	silenceId, err := getParam(req, "silenceId")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var payload UpdateSilenceRequest
+	if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	// Alertmanager updates silences through the same API as it creates them
+	if !hr.authorize(w, req, alertmanagerAPIAttributes("create")) {
+		return
+	}
+
+	newSilenceId, err := hr.managementClient.UpdateSilence(req.Context(), silenceId, payload.Silence)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(UpdateSilenceResponse{
+		Id: newSilenceId,
+	})
+}
@@ -0,0 +1,188 @@
+package httprouter_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/machadovilaca/alerts-ui-management/internal/httprouter"
+	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management/testutils"
+)
+
+var _ = Describe("Silences", func() {
+	var (
+		mgmt         management.Client
+		router       http.Handler
+		mockSilences *testutils.MockAlertmanagerSilencesInterface
+		silence      k8s.Silence
+	)
+
+	BeforeEach(func() {
+		mockSilences = &testutils.MockAlertmanagerSilencesInterface{}
+		mockK8s := &testutils.MockClient{
+			AlertmanagerSilencesFunc: func() k8s.AlertmanagerSilencesInterface {
+				return mockSilences
+			},
+		}
+
+		mgmt = management.NewWithCustomMapper(context.Background(), mockK8s, &testutils.MockMapperClient{})
+		router = httprouter.New(mgmt)
+
+		silence = k8s.Silence{
+			Matchers:  []k8s.SilenceMatcher{{Name: "alertname", Value: "Foo"}},
+			EndsAt:    time.Now().Add(time.Hour),
+			CreatedBy: "admin",
+			Comment:   "maintenance",
+		}
+	})
+
+	serve := func(method string, target string, body interface{}) *httptest.ResponseRecorder {
+		var buf []byte
+		if body != nil {
+			buf, _ = json.Marshal(body)
+		}
+		req := httptest.NewRequest(method, target, bytes.NewReader(buf))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	create := func() string {
+		w := serve(http.MethodPost, "/api/v1/alerting/silences", httprouter.CreateSilenceRequest{Silence: silence})
+		Expect(w.Code).To(Equal(http.StatusCreated))
+
+		var resp httprouter.CreateSilenceResponse
+		Expect(json.NewDecoder(w.Body).Decode(&resp)).To(Succeed())
+		return resp.Id
+	}
+
+	It("creates, gets and lists silences", func() {
+		id := create()
+
+		w := serve(http.MethodGet, "/api/v1/alerting/silences/"+id, nil)
+		Expect(w.Code).To(Equal(http.StatusOK))
+		var getResp httprouter.GetSilenceResponse
+		Expect(json.NewDecoder(w.Body).Decode(&getResp)).To(Succeed())
+		Expect(getResp.Status).To(Equal("success"))
+		Expect(getResp.Data.Silence.Matchers).To(Equal(silence.Matchers))
+
+		w = serve(http.MethodGet, "/api/v1/alerting/silences?state=active", nil)
+		Expect(w.Code).To(Equal(http.StatusOK))
+		var listResp httprouter.GetSilencesResponse
+		Expect(json.NewDecoder(w.Body).Decode(&listResp)).To(Succeed())
+		Expect(listResp.Data.Silences).To(HaveLen(1))
+		Expect(listResp.Data.Silences[0].Id).To(Equal(id))
+	})
+
+	It("returns 400 for invalid states and silences", func() {
+		Expect(serve(http.MethodGet, "/api/v1/alerting/silences?state=muted", nil).Code).To(Equal(http.StatusBadRequest))
+
+		silence.Comment = ""
+		w := serve(http.MethodPost, "/api/v1/alerting/silences", httprouter.CreateSilenceRequest{Silence: silence})
+		Expect(w.Code).To(Equal(http.StatusBadRequest))
+		Expect(w.Body.String()).To(ContainSubstring("comment is required"))
+	})
+
+	It("returns 404 for missing silences", func() {
+		Expect(serve(http.MethodGet, "/api/v1/alerting/silences/missing", nil).Code).To(Equal(http.StatusNotFound))
+		Expect(serve(http.MethodDelete, "/api/v1/alerting/silences/missing", nil).Code).To(Equal(http.StatusNotFound))
+	})
+
+	It("updates a silence and returns 405 once it is expired", func() {
+		id := create()
+
+		silence.Comment = "extended"
+		w := serve(http.MethodPut, "/api/v1/alerting/silences/"+id, httprouter.UpdateSilenceRequest{Silence: silence})
+		Expect(w.Code).To(Equal(http.StatusOK))
+		var resp httprouter.UpdateSilenceResponse
+		Expect(json.NewDecoder(w.Body).Decode(&resp)).To(Succeed())
+		Expect(resp.Id).To(Equal(id))
+		Expect(mockSilences.Silences[id].Comment).To(Equal("extended"))
+
+		Expect(serve(http.MethodDelete, "/api/v1/alerting/silences/"+id, nil).Code).To(Equal(http.StatusNoContent))
+		Expect(mockSilences.Silences[id].Status.State).To(Equal(k8s.SilenceStateExpired))
+
+		w = serve(http.MethodPut, "/api/v1/alerting/silences/"+id, httprouter.UpdateSilenceRequest{Silence: silence})
+		Expect(w.Code).To(Equal(http.StatusMethodNotAllowed))
+	})
+
+	It("silences an alert by its labels", func() {
+		w := serve(http.MethodPost, "/api/v1/alerting/alerts/silence", httprouter.SilenceAlertRequest{
+			Labels: map[string]string{"alertname": "Foo", "namespace": "team-a"},
+			SilenceOptions: management.SilenceOptions{
+				EndsAt:    time.Now().Add(time.Hour),
+				CreatedBy: "admin",
+				Comment:   "maintenance",
+			},
+		})
+		Expect(w.Code).To(Equal(http.StatusCreated))
+
+		var resp httprouter.SilenceAlertResponse
+		Expect(json.NewDecoder(w.Body).Decode(&resp)).To(Succeed())
+		Expect(mockSilences.Silences[resp.Id].Matchers).To(Equal([]k8s.SilenceMatcher{
+			{Name: "alertname", Value: "Foo"},
+			{Name: "namespace", Value: "team-a"},
+		}))
+
+		Expect(serve(http.MethodPost, "/api/v1/alerting/alerts/silence", httprouter.SilenceAlertRequest{}).Code).To(Equal(http.StatusBadRequest))
+	})
+
+	Context("when authorizing", func() {
+		const token = "alice-token"
+
+		var authorizer *testutils.FakeAuthorizer
+
+		BeforeEach(func() {
+			authorizer = testutils.NewFakeAuthorizer()
+			authorizer.AddUser(token, k8s.UserInfo{Username: "alice"})
+			router = httprouter.NewWithAuthorizer(mgmt, authorizer)
+		})
+
+		serveAs := func(method string, target string, body interface{}) *httptest.ResponseRecorder {
+			buf, _ := json.Marshal(body)
+			req := httptest.NewRequest(method, target, bytes.NewReader(buf))
+			req.Header.Set("Authorization", "Bearer "+token)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			return w
+		}
+
+		allow := func(verb string) {
+			authorizer.Allow("alice", k8s.ResourceAttributes{
+				Namespace:   "openshift-monitoring",
+				Verb:        verb,
+				Group:       "monitoring.coreos.com",
+				Resource:    "alertmanagers",
+				Subresource: "api",
+				Name:        "main",
+			})
+		}
+
+		It("requires access to the Alertmanager API", func() {
+			Expect(serveAs(http.MethodGet, "/api/v1/alerting/silences", nil).Code).To(Equal(http.StatusForbidden))
+
+			allow("get")
+			Expect(serveAs(http.MethodGet, "/api/v1/alerting/silences", nil).Code).To(Equal(http.StatusOK))
+			Expect(serveAs(http.MethodPost, "/api/v1/alerting/silences", httprouter.CreateSilenceRequest{Silence: silence}).Code).To(Equal(http.StatusForbidden))
+
+			allow("create")
+			Expect(serveAs(http.MethodPost, "/api/v1/alerting/silences", httprouter.CreateSilenceRequest{Silence: silence}).Code).To(Equal(http.StatusCreated))
+		})
+
+		It("sets the author of the silence to the user, ignoring the one of the request", func() {
+			allow("create")
+			Expect(silence.CreatedBy).To(Equal("admin"))
+
+			Expect(serveAs(http.MethodPost, "/api/v1/alerting/silences", httprouter.CreateSilenceRequest{Silence: silence}).Code).To(Equal(http.StatusCreated))
+			Expect(mockSilences.Silences["silence-1"].CreatedBy).To(Equal("alice"))
+		})
+	})
+})
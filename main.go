@@ -2,8 +2,12 @@ package main
 
 import (
 	"context"
+	"flag"
 	"log"
 	"net/http"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/labels"
 
 	"github.com/machadovilaca/alerts-ui-management/internal/httprouter"
 	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
@@ -15,9 +19,38 @@ const (
 )
 
 func main() {
+	var prometheusOpts k8s.PrometheusOptions
+	var alertmanagerOpts k8s.PrometheusOptions
+	var disableAuth bool
+	var platformNamespaceSelector string
+	var platformNamespacePrefixes string
+	flag.StringVar(&prometheusOpts.URL, "prometheus-url", "", "Base URL of the Prometheus compatible API, e.g. https://thanos-querier.openshift-monitoring.svc:9091. Defaults to the openshift-monitoring/prometheus-k8s Route")
+	flag.StringVar(&prometheusOpts.CAFile, "prometheus-ca-file", "", "Path to a PEM encoded CA bundle used to verify the Prometheus API certificate")
+	flag.StringVar(&alertmanagerOpts.URL, "alertmanager-url", "", "Base URL of the Alertmanager API, e.g. https://alertmanager-main.openshift-monitoring.svc:9094. Defaults to the openshift-monitoring/alertmanager-main Route")
+	flag.StringVar(&alertmanagerOpts.CAFile, "alertmanager-ca-file", "", "Path to a PEM encoded CA bundle used to verify the Alertmanager API certificate")
+	flag.BoolVar(&disableAuth, "disable-auth", false, "Serve the API without authentication, performing all operations with the service privileges. Only intended for local development")
+	flag.StringVar(&platformNamespaceSelector, "platform-namespace-selector", "", "Label selector of the namespaces whose PrometheusRules are platform-managed, in addition to the namespaces matching -platform-namespace-prefixes. Defaults to openshift.io/cluster-monitoring=true")
+	flag.StringVar(&platformNamespacePrefixes, "platform-namespace-prefixes", "openshift-", "Comma-separated prefixes of the namespaces whose PrometheusRules are platform-managed. Empty to only classify namespaces by -platform-namespace-selector")
+	flag.Parse()
+
+	classification := management.DefaultClassificationOptions()
+	classification.NamespacePrefixes = nil
+	for _, prefix := range strings.Split(platformNamespacePrefixes, ",") {
+		if prefix = strings.TrimSpace(prefix); prefix != "" {
+			classification.NamespacePrefixes = append(classification.NamespacePrefixes, prefix)
+		}
+	}
+	if platformNamespaceSelector != "" {
+		selector, err := labels.Parse(platformNamespaceSelector)
+		if err != nil {
+			log.Fatalf("Invalid platform namespace selector: %v", err)
+		}
+		classification.NamespaceSelector = selector
+	}
+
 	ctx := context.Background()
 
-	client, err := k8s.NewClient(ctx, k8s.ClientOptions{})
+	client, err := k8s.NewClient(ctx, k8s.ClientOptions{Prometheus: prometheusOpts, Alertmanager: alertmanagerOpts})
 	if err != nil {
 		log.Fatalf("Failed to create Kubernetes client: %v", err)
 	}
@@ -26,9 +59,16 @@ func main() {
 		log.Fatalf("Failed to connect to cluster: %v", err)
 	}
 
-	mgmClient := management.New(ctx, client)
+	mgmClient, err := management.New(ctx, client, management.WithClassification(classification))
+	if err != nil {
+		log.Fatalf("Failed to create management client: %v", err)
+	}
 
-	r := httprouter.New(mgmClient)
+	r := httprouter.NewWithAuthorizer(mgmClient, client.Auth())
+	if disableAuth {
+		log.Println("authentication is disabled")
+		r = httprouter.New(mgmClient)
+	}
 
 	log.Println("listening on", listenAddr)
 	if err := http.ListenAndServe(listenAddr, r); err != nil {
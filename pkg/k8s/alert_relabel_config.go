@@ -43,28 +43,62 @@ func (arcm *alertRelabelConfigManager) Get(ctx context.Context, namespace string
 }
 
 func (arcm *alertRelabelConfigManager) Create(ctx context.Context, arc osmv1.AlertRelabelConfig) (*osmv1.AlertRelabelConfig, error) {
-	created, err := arcm.clientset.MonitoringV1().AlertRelabelConfigs(arc.Namespace).Create(ctx, &arc, metav1.CreateOptions{})
+	created, err := arcm.clientset.MonitoringV1().AlertRelabelConfigs(arc.Namespace).Create(ctx, &arc, metav1.CreateOptions{DryRun: dryRunOption(ctx)})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create AlertRelabelConfig %s/%s: %w", arc.Namespace, arc.Name, err)
 	}
 
+	recordDryRun(ctx, alertRelabelConfigWrite(DryRunCreate, nil, created))
 	return created, nil
 }
 
 func (arcm *alertRelabelConfigManager) Update(ctx context.Context, arc osmv1.AlertRelabelConfig) error {
-	_, err := arcm.clientset.MonitoringV1().AlertRelabelConfigs(arc.Namespace).Update(ctx, &arc, metav1.UpdateOptions{})
+	before := arcm.dryRunBefore(ctx, arc.Namespace, arc.Name)
+
+	updated, err := arcm.clientset.MonitoringV1().AlertRelabelConfigs(arc.Namespace).Update(ctx, &arc, metav1.UpdateOptions{DryRun: dryRunOption(ctx)})
 	if err != nil {
 		return fmt.Errorf("failed to update AlertRelabelConfig %s/%s: %w", arc.Namespace, arc.Name, err)
 	}
 
+	recordDryRun(ctx, alertRelabelConfigWrite(DryRunUpdate, before, updated))
 	return nil
 }
 
 func (arcm *alertRelabelConfigManager) Delete(ctx context.Context, namespace string, name string) error {
-	err := arcm.clientset.MonitoringV1().AlertRelabelConfigs(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	before := arcm.dryRunBefore(ctx, namespace, name)
+
+	err := arcm.clientset.MonitoringV1().AlertRelabelConfigs(namespace).Delete(ctx, name, metav1.DeleteOptions{DryRun: dryRunOption(ctx)})
 	if err != nil {
 		return fmt.Errorf("failed to delete AlertRelabelConfig %s: %w", name, err)
 	}
 
+	recordDryRun(ctx, alertRelabelConfigWrite(DryRunDelete, before, nil))
 	return nil
 }
+
+// dryRunBefore returns the AlertRelabelConfig before a dry-run write, or nil if
+// ctx is not a dry run
+func (arcm *alertRelabelConfigManager) dryRunBefore(ctx context.Context, namespace string, name string) *osmv1.AlertRelabelConfig {
+	if _, ok := DryRunFrom(ctx); !ok {
+		return nil
+	}
+
+	arc, found, err := arcm.Get(ctx, namespace, name)
+	if err != nil || !found {
+		return nil
+	}
+	return arc
+}
+
+func alertRelabelConfigWrite(operation string, before *osmv1.AlertRelabelConfig, after *osmv1.AlertRelabelConfig) DryRunWrite {
+	write := DryRunWrite{Operation: operation, Kind: "AlertRelabelConfig"}
+	if before != nil {
+		write.Namespace, write.Name = before.Namespace, before.Name
+		write.Before = before
+	}
+	if after != nil {
+		write.Namespace, write.Name = after.Namespace, after.Name
+		write.After = after
+	}
+	return write
+}
@@ -3,60 +3,98 @@ package k8s
 import (
 	"context"
 	"log"
+	"sync"
 
 	osmv1 "github.com/openshift/api/monitoring/v1"
 	osmv1client "github.com/openshift/client-go/monitoring/clientset/versioned"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
 )
 
 type alertRelabelConfigInformer struct {
-	clientset *osmv1client.Clientset
+	informer cache.SharedIndexInformer
+
+	mu           sync.RWMutex
+	registration cache.ResourceEventHandlerRegistration
 }
 
 func newAlertRelabelConfigInformer(clientset *osmv1client.Clientset) AlertRelabelConfigInformerInterface {
+	listWatch := &cache.ListWatch{
+		ListWithContextFunc: func(ctx context.Context, options metav1.ListOptions) (runtime.Object, error) {
+			return clientset.MonitoringV1().AlertRelabelConfigs("").List(ctx, options)
+		},
+		WatchFuncWithContext: func(ctx context.Context, options metav1.ListOptions) (watch.Interface, error) {
+			return clientset.MonitoringV1().AlertRelabelConfigs("").Watch(ctx, options)
+		},
+	}
+
 	return &alertRelabelConfigInformer{
-		clientset: clientset,
+		informer: cache.NewSharedIndexInformer(listWatch, &osmv1.AlertRelabelConfig{}, informerResyncPeriod, cache.Indexers{}),
 	}
 }
 
 func (arci *alertRelabelConfigInformer) Run(ctx context.Context, callbacks AlertRelabelConfigInformerCallback) error {
-	options := metav1.ListOptions{
-		Watch: true,
-	}
-
-	watcher, err := arci.clientset.MonitoringV1().AlertRelabelConfigs("").Watch(ctx, options)
-	if err != nil {
-		return err
-	}
-	defer watcher.Stop()
-
-	ch := watcher.ResultChan()
-	for event := range ch {
-		arc, ok := event.Object.(*osmv1.AlertRelabelConfig)
-		if !ok {
-			log.Printf("Unexpected type: %v", event.Object)
-			continue
-		}
-
-		switch event.Type {
-		case watch.Added:
+	registration, err := arci.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			arc, ok := obj.(*osmv1.AlertRelabelConfig)
+			if !ok {
+				log.Printf("Unexpected type: %T", obj)
+				return
+			}
 			if callbacks.OnAdd != nil {
 				callbacks.OnAdd(arc)
 			}
-		case watch.Modified:
+		},
+		UpdateFunc: func(_, newObj interface{}) {
+			arc, ok := newObj.(*osmv1.AlertRelabelConfig)
+			if !ok {
+				log.Printf("Unexpected type: %T", newObj)
+				return
+			}
 			if callbacks.OnUpdate != nil {
 				callbacks.OnUpdate(arc)
 			}
-		case watch.Deleted:
+		},
+		DeleteFunc: func(obj interface{}) {
+			// The final state of the object is unknown if the delete event was
+			// missed while the watch was disconnected
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			arc, ok := obj.(*osmv1.AlertRelabelConfig)
+			if !ok {
+				log.Printf("Unexpected type: %T", obj)
+				return
+			}
 			if callbacks.OnDelete != nil {
 				callbacks.OnDelete(arc)
 			}
-		case watch.Error:
-			log.Printf("Error occurred while watching AlertRelabelConfig: %s\n", event.Object)
-		}
+		},
+	})
+	if err != nil {
+		return err
 	}
 
-	log.Fatalf("AlertRelabelConfig watcher channel closed unexpectedly")
+	arci.mu.Lock()
+	arci.registration = registration
+	arci.mu.Unlock()
+
+	// The informer lists the AlertRelabelConfigs before watching them, resumes the
+	// watch from the last seen resourceVersion and reconnects with backoff
+	// until the context is cancelled
+	arci.informer.RunWithContext(ctx)
 	return nil
 }
+
+func (arci *alertRelabelConfigInformer) HasSynced() bool {
+	arci.mu.RLock()
+	defer arci.mu.RUnlock()
+
+	return arci.registration != nil && arci.registration.HasSynced()
+}
+
+func (arci *alertRelabelConfigInformer) WaitForCacheSync(ctx context.Context) bool {
+	return cache.WaitForCacheSync(ctx.Done(), arci.HasSynced)
+}
@@ -0,0 +1,81 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	alertmanagerAlertsPath = "/api/v2/alerts"
+)
+
+// AlertmanagerAlert is an alert as received and processed by Alertmanager
+type AlertmanagerAlert struct {
+	// Labels are the labels of the alert, after alert relabeling and with the external labels of Prometheus
+	Labels map[string]string `json:"labels"`
+
+	// Annotations are the annotations of the alert
+	Annotations map[string]string `json:"annotations"`
+
+	// Fingerprint identifies the alert by its labels
+	Fingerprint string `json:"fingerprint"`
+
+	// Receivers are the receivers the alert is routed to
+	Receivers []AlertmanagerReceiver `json:"receivers"`
+
+	// Status is the state of the alert in Alertmanager
+	Status AlertmanagerAlertStatus `json:"status"`
+
+	// StartsAt is the time the alert started firing
+	StartsAt time.Time `json:"startsAt"`
+
+	// EndsAt is the time the alert resolves if Prometheus stops sending it
+	EndsAt time.Time `json:"endsAt"`
+
+	// UpdatedAt is the time Prometheus last sent the alert
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// AlertmanagerReceiver is a receiver of the Alertmanager configuration
+type AlertmanagerReceiver struct {
+	// Name of the receiver
+	Name string `json:"name"`
+}
+
+// AlertmanagerAlertStatus is the state of an alert in Alertmanager
+type AlertmanagerAlertStatus struct {
+	// State is unprocessed, active or suppressed
+	State string `json:"state"`
+
+	// SilencedBy are the IDs of the silences muting the alert
+	SilencedBy []string `json:"silencedBy"`
+
+	// InhibitedBy are the fingerprints of the alerts inhibiting the alert
+	InhibitedBy []string `json:"inhibitedBy"`
+}
+
+type alertmanagerAlerts struct {
+	endpoint *prometheusEndpoint
+}
+
+func newAlertmanagerAlerts(endpoint *prometheusEndpoint) AlertmanagerAlertsInterface {
+	return &alertmanagerAlerts{
+		endpoint: endpoint,
+	}
+}
+
+func (aa *alertmanagerAlerts) GetAlerts(ctx context.Context) ([]AlertmanagerAlert, error) {
+	// Silenced, inhibited and unprocessed alerts are all listed by default
+	raw, err := aa.endpoint.get(ctx, alertmanagerAlertsPath, nil, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alertmanager alerts: %w", err)
+	}
+
+	var alerts []AlertmanagerAlert
+	if err := json.Unmarshal(raw, &alerts); err != nil {
+		return nil, fmt.Errorf("decode alertmanager response: %w", err)
+	}
+	return alerts, nil
+}
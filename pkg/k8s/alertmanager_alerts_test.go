@@ -0,0 +1,32 @@
+package k8s
+
+import (
+	"context"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/rest"
+)
+
+var _ = Describe("AlertmanagerAlerts", func() {
+	It("should return the alerts with their status and receivers", func() {
+		fake := &fakeAlertmanager{
+			silences: map[string]Silence{},
+			alerts: []AlertmanagerAlert{{
+				Labels:      map[string]string{"alertname": "Foo"},
+				Fingerprint: "fp1",
+				Receivers:   []AlertmanagerReceiver{{Name: "pager"}},
+				Status:      AlertmanagerAlertStatus{State: "suppressed", SilencedBy: []string{"s1"}, InhibitedBy: []string{}},
+			}},
+		}
+		srv := httptest.NewServer(fake)
+		DeferCleanup(srv.Close)
+
+		alerts := newAlertmanagerAlerts(newAlertmanagerEndpoint(nil, &rest.Config{}, PrometheusOptions{URL: srv.URL}))
+
+		got, err := alerts.GetAlerts(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(got).To(Equal(fake.alerts))
+	})
+})
@@ -0,0 +1,204 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	alertmanagerSilencesPath = "/api/v2/silences"
+	alertmanagerSilencePath  = "/api/v2/silence/"
+)
+
+const (
+	// SilenceStateActive is the state of silences muting alerts
+	SilenceStateActive = "active"
+
+	// SilenceStatePending is the state of silences starting in the future
+	SilenceStatePending = "pending"
+
+	// SilenceStateExpired is the state of silences that ended or were expired
+	SilenceStateExpired = "expired"
+)
+
+// Silence is an Alertmanager silence, muting the alerts matching all its matchers between StartsAt and EndsAt
+type Silence struct {
+	// Id is the ID assigned by Alertmanager, empty for new silences
+	Id string `json:"id,omitempty"`
+
+	// Matchers select the silenced alerts by their labels
+	Matchers []SilenceMatcher `json:"matchers"`
+
+	// StartsAt is the time the silence starts
+	StartsAt time.Time `json:"startsAt"`
+
+	// EndsAt is the time the silence ends
+	EndsAt time.Time `json:"endsAt"`
+
+	// CreatedBy is the author of the silence
+	CreatedBy string `json:"createdBy"`
+
+	// Comment explains why the alerts are silenced
+	Comment string `json:"comment"`
+
+	// Status is the state of the silence, set by Alertmanager
+	Status *SilenceStatus `json:"status,omitempty"`
+
+	// UpdatedAt is the time of the last change of the silence, set by Alertmanager
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+}
+
+// SilenceMatcher matches the value of an alert label
+type SilenceMatcher struct {
+	// Name of the label
+	Name string `json:"name"`
+
+	// Value is the value or regex the label is compared to
+	Value string `json:"value"`
+
+	// IsRegex is true if Value is a regex
+	IsRegex bool `json:"isRegex"`
+
+	// IsEqual is false for negative matchers, defaults to true
+	IsEqual *bool `json:"isEqual,omitempty"`
+}
+
+// SilenceStatus is the state of a silence
+type SilenceStatus struct {
+	// State is active, pending or expired
+	State string `json:"state"`
+}
+
+// ListSilencesRequest holds parameters for filtering silences
+type ListSilencesRequest struct {
+	// Labels filters silences by their matchers, only silences with an equality matcher for each label are listed
+	Labels map[string]string
+
+	// State filters silences by state: "active", "pending", "expired", or "" for all states
+	State string
+}
+
+type alertmanagerSilences struct {
+	endpoint *prometheusEndpoint
+}
+
+func newAlertmanagerSilences(endpoint *prometheusEndpoint) AlertmanagerSilencesInterface {
+	return &alertmanagerSilences{
+		endpoint: endpoint,
+	}
+}
+
+func (as *alertmanagerSilences) List(ctx context.Context, req ListSilencesRequest) ([]Silence, error) {
+	query := url.Values{}
+	for _, name := range sortedKeys(req.Labels) {
+		query.Add("filter", fmt.Sprintf("%s=%q", name, req.Labels[name]))
+	}
+
+	raw, err := as.endpoint.get(ctx, alertmanagerSilencesPath, query, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list silences: %w", err)
+	}
+
+	var silences []Silence
+	if err := json.Unmarshal(raw, &silences); err != nil {
+		return nil, fmt.Errorf("decode alertmanager response: %w", err)
+	}
+
+	out := make([]Silence, 0, len(silences))
+	for _, silence := range silences {
+		if req.State != "" && (silence.Status == nil || silence.Status.State != req.State) {
+			continue
+		}
+		out = append(out, silence)
+	}
+	return out, nil
+}
+
+func (as *alertmanagerSilences) Get(ctx context.Context, id string) (*Silence, bool, error) {
+	raw, err := as.endpoint.get(ctx, alertmanagerSilencePath+url.PathEscape(id), nil, "")
+	if err != nil {
+		if isEndpointNotFound(err) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to get silence %s: %w", id, err)
+	}
+
+	var silence Silence
+	if err := json.Unmarshal(raw, &silence); err != nil {
+		return nil, false, fmt.Errorf("decode alertmanager response: %w", err)
+	}
+	return &silence, true, nil
+}
+
+func (as *alertmanagerSilences) Create(ctx context.Context, silence Silence) (string, error) {
+	silence.Id = ""
+	return as.post(ctx, silence)
+}
+
+func (as *alertmanagerSilences) Update(ctx context.Context, silence Silence) (string, error) {
+	if silence.Id == "" {
+		return "", fmt.Errorf("cannot update a silence without id")
+	}
+	return as.post(ctx, silence)
+}
+
+func (as *alertmanagerSilences) Expire(ctx context.Context, id string) error {
+	_, err := as.endpoint.do(ctx, http.MethodDelete, alertmanagerSilencePath+url.PathEscape(id), nil, nil, "")
+	if err != nil {
+		return fmt.Errorf("failed to expire silence %s: %w", id, err)
+	}
+	return nil
+}
+
+// post creates the silence, or updates it if it has an id. Alertmanager expires
+// the silence and creates a new one if the update cannot be applied in place,
+// so the returned id may differ from the one of the silence
+func (as *alertmanagerSilences) post(ctx context.Context, silence Silence) (string, error) {
+	// Status and UpdatedAt are set by Alertmanager and rejected in requests
+	silence.Status = nil
+	silence.UpdatedAt = nil
+
+	body, err := json.Marshal(silence)
+	if err != nil {
+		return "", fmt.Errorf("encode silence: %w", err)
+	}
+
+	raw, err := as.endpoint.do(ctx, http.MethodPost, alertmanagerSilencesPath, nil, body, "")
+	if err != nil {
+		return "", fmt.Errorf("failed to post silence: %w", err)
+	}
+
+	var resp struct {
+		SilenceID string `json:"silenceID"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return "", fmt.Errorf("decode alertmanager response: %w", err)
+	}
+	return resp.SilenceID, nil
+}
+
+// isEndpointNotFound returns true if the API, or the service proxy in front of it, responded with 404
+func isEndpointNotFound(err error) bool {
+	var endpointErr *EndpointError
+	if errors.As(err, &endpointErr) {
+		return endpointErr.StatusCode == http.StatusNotFound
+	}
+	return apierrors.IsNotFound(err)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
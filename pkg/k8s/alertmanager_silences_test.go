@@ -0,0 +1,200 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/rest"
+)
+
+// fakeAlertmanager serves the silences of the Alertmanager v2 API from memory
+type fakeAlertmanager struct {
+	mu       sync.Mutex
+	silences map[string]Silence
+	alerts   []AlertmanagerAlert
+	nextId   int
+	requests []*http.Request
+}
+
+func (f *fakeAlertmanager) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests = append(f.requests, req)
+
+	switch {
+	case req.Method == http.MethodGet && req.URL.Path == alertmanagerAlertsPath:
+		_ = json.NewEncoder(w).Encode(f.alerts)
+
+	case req.Method == http.MethodGet && req.URL.Path == alertmanagerSilencesPath:
+		out := []Silence{}
+		for _, silence := range f.silences {
+			if f.matchesFilters(silence, req.URL.Query()["filter"]) {
+				out = append(out, silence)
+			}
+		}
+		_ = json.NewEncoder(w).Encode(out)
+
+	case req.Method == http.MethodPost && req.URL.Path == alertmanagerSilencesPath:
+		var silence Silence
+		if err := json.NewDecoder(req.Body).Decode(&silence); err != nil || silence.Status != nil {
+			http.Error(w, "invalid silence", http.StatusBadRequest)
+			return
+		}
+		if silence.Id != "" {
+			if _, ok := f.silences[silence.Id]; !ok {
+				http.Error(w, "silence not found", http.StatusNotFound)
+				return
+			}
+		} else {
+			f.nextId++
+			silence.Id = fmt.Sprintf("id-%d", f.nextId)
+		}
+		silence.Status = &SilenceStatus{State: SilenceStateActive}
+		f.silences[silence.Id] = silence
+		_ = json.NewEncoder(w).Encode(map[string]string{"silenceID": silence.Id})
+
+	case strings.HasPrefix(req.URL.Path, alertmanagerSilencePath):
+		id := strings.TrimPrefix(req.URL.Path, alertmanagerSilencePath)
+		silence, ok := f.silences[id]
+		if !ok {
+			http.Error(w, "silence not found", http.StatusNotFound)
+			return
+		}
+
+		if req.Method == http.MethodDelete {
+			silence.Status = &SilenceStatus{State: SilenceStateExpired}
+			f.silences[id] = silence
+			return
+		}
+		_ = json.NewEncoder(w).Encode(silence)
+
+	default:
+		http.NotFound(w, req)
+	}
+}
+
+func (f *fakeAlertmanager) matchesFilters(silence Silence, filters []string) bool {
+	for _, filter := range filters {
+		found := false
+		for _, matcher := range silence.Matchers {
+			if filter == fmt.Sprintf("%s=%q", matcher.Name, matcher.Value) {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+var _ = Describe("AlertmanagerSilences", func() {
+	var (
+		ctx      context.Context
+		fake     *fakeAlertmanager
+		silences AlertmanagerSilencesInterface
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		fake = &fakeAlertmanager{silences: map[string]Silence{}}
+		srv := httptest.NewServer(fake)
+		DeferCleanup(srv.Close)
+
+		endpoint := newAlertmanagerEndpoint(nil, &rest.Config{BearerToken: "token"}, PrometheusOptions{URL: srv.URL})
+		silences = newAlertmanagerSilences(endpoint)
+	})
+
+	newSilence := func(alertname string) Silence {
+		now := time.Now().UTC().Truncate(time.Second)
+		return Silence{
+			Matchers:  []SilenceMatcher{{Name: "alertname", Value: alertname}},
+			StartsAt:  now,
+			EndsAt:    now.Add(time.Hour),
+			CreatedBy: "user",
+			Comment:   "maintenance",
+		}
+	}
+
+	It("should create a silence and get it by id", func() {
+		silence := newSilence("Foo")
+		silence.Id = "ignored"
+
+		id, err := silences.Create(ctx, silence)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(id).To(Equal("id-1"))
+		Expect(fake.requests[0].Header.Get("Authorization")).To(Equal("Bearer token"))
+		Expect(fake.requests[0].Header.Get("Content-Type")).To(Equal("application/json"))
+
+		got, found, err := silences.Get(ctx, id)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeTrue())
+		Expect(got.Matchers).To(Equal(silence.Matchers))
+		Expect(got.Status.State).To(Equal(SilenceStateActive))
+	})
+
+	It("should report missing silences as not found", func() {
+		_, found, err := silences.Get(ctx, "missing")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeFalse())
+	})
+
+	It("should filter silences by label on the server and by state on the client", func() {
+		fooId, err := silences.Create(ctx, newSilence("Foo"))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = silences.Create(ctx, newSilence("Bar"))
+		Expect(err).NotTo(HaveOccurred())
+
+		list, err := silences.List(ctx, ListSilencesRequest{Labels: map[string]string{"alertname": "Foo"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(list).To(HaveLen(1))
+		Expect(list[0].Id).To(Equal(fooId))
+
+		Expect(silences.Expire(ctx, fooId)).To(Succeed())
+
+		list, err = silences.List(ctx, ListSilencesRequest{State: SilenceStateActive})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(list).To(HaveLen(1))
+		Expect(list[0].Matchers[0].Value).To(Equal("Bar"))
+	})
+
+	It("should update a silence without sending the fields set by Alertmanager", func() {
+		id, err := silences.Create(ctx, newSilence("Foo"))
+		Expect(err).NotTo(HaveOccurred())
+
+		silence, _, err := silences.Get(ctx, id)
+		Expect(err).NotTo(HaveOccurred())
+		silence.Comment = "extended"
+
+		newId, err := silences.Update(ctx, *silence)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(newId).To(Equal(id))
+
+		updated, _, err := silences.Get(ctx, id)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated.Comment).To(Equal("extended"))
+
+		_, err = silences.Update(ctx, newSilence("Foo"))
+		Expect(err).To(MatchError(ContainSubstring("without id")))
+	})
+
+	It("should return the status and body of failed requests", func() {
+		err := silences.Expire(ctx, "missing")
+
+		var endpointErr *EndpointError
+		Expect(errors.As(err, &endpointErr)).To(BeTrue())
+		Expect(endpointErr.StatusCode).To(Equal(http.StatusNotFound))
+		Expect(endpointErr.Body).To(ContainSubstring("silence not found"))
+	})
+})
@@ -0,0 +1,88 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+type auth struct {
+	clientset *kubernetes.Clientset
+}
+
+func newAuth(clientset *kubernetes.Clientset) AuthInterface {
+	return &auth{
+		clientset: clientset,
+	}
+}
+
+func (a *auth) Authenticate(ctx context.Context, token string) (*UserInfo, error) {
+	review, err := a.clientset.AuthenticationV1().TokenReviews().Create(ctx, &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: token},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create TokenReview: %w", err)
+	}
+
+	if !review.Status.Authenticated {
+		return nil, nil
+	}
+
+	extra := make(map[string][]string, len(review.Status.User.Extra))
+	for key, values := range review.Status.User.Extra {
+		extra[key] = values
+	}
+
+	return &UserInfo{
+		Username: review.Status.User.Username,
+		UID:      review.Status.User.UID,
+		Groups:   review.Status.User.Groups,
+		Extra:    extra,
+	}, nil
+}
+
+func (a *auth) Authorize(ctx context.Context, user UserInfo, attrs ResourceAttributes) (bool, error) {
+	extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+	for key, values := range user.Extra {
+		extra[key] = values
+	}
+
+	review, err := a.clientset.AuthorizationV1().SubjectAccessReviews().Create(ctx, &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   user.Username,
+			UID:    user.UID,
+			Groups: user.Groups,
+			Extra:  extra,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace:   attrs.Namespace,
+				Verb:        attrs.Verb,
+				Group:       attrs.Group,
+				Resource:    attrs.Resource,
+				Subresource: attrs.Subresource,
+				Name:        attrs.Name,
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return false, fmt.Errorf("failed to create SubjectAccessReview: %w", err)
+	}
+
+	return review.Status.Allowed, nil
+}
+
+type userContextKey struct{}
+
+// WithUser returns a copy of ctx carrying the authenticated user
+func WithUser(ctx context.Context, user UserInfo) context.Context {
+	return context.WithValue(ctx, userContextKey{}, user)
+}
+
+// UserFrom returns the authenticated user carried by ctx, if any
+func UserFrom(ctx context.Context) (UserInfo, bool) {
+	user, ok := ctx.Value(userContextKey{}).(UserInfo)
+	return user, ok
+}
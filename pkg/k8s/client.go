@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"time"
 
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -16,19 +17,32 @@ import (
 
 var _ Client = (*client)(nil)
 
+// informerResyncPeriod is the interval at which informers replay their cached
+// objects to the callbacks as update events
+const informerResyncPeriod = 10 * time.Minute
+
 type client struct {
 	clientset             *kubernetes.Clientset
 	monitoringv1clientset *monitoringv1client.Clientset
 	osmv1clientset        *osmv1client.Clientset
 	config                *rest.Config
 
-	prometheusAlerts PrometheusAlertsInterface
+	prometheusAlerts   PrometheusAlertsInterface
+	prometheusRulesAPI PrometheusRulesAPIInterface
+	prometheusQuery    PrometheusQueryInterface
+
+	alertmanagerSilences AlertmanagerSilencesInterface
+	alertmanagerAlerts   AlertmanagerAlertsInterface
 
 	prometheusRuleManager  PrometheusRuleInterface
 	prometheusRuleInformer PrometheusRuleInformerInterface
 
 	alertRelabelConfigManager  AlertRelabelConfigInterface
 	alertRelabelConfigInformer AlertRelabelConfigInformerInterface
+
+	namespaceManager NamespaceInterface
+
+	auth AuthInterface
 }
 
 func newClient(_ context.Context, opts ClientOptions) (Client, error) {
@@ -72,7 +86,17 @@ func newClient(_ context.Context, opts ClientOptions) (Client, error) {
 		config:                config,
 	}
 
-	c.prometheusAlerts = newPrometheusAlerts(clientset, config)
+	prometheusEndpoint := newPrometheusEndpoint(clientset, config, opts.Prometheus)
+	c.prometheusAlerts = newPrometheusAlerts(
+		prometheusEndpoint,
+		newPrometheusEndpoint(clientset, config, opts.Prometheus.tenancyOptions()),
+	)
+	c.prometheusRulesAPI = newPrometheusRulesAPI(prometheusEndpoint)
+	c.prometheusQuery = newPrometheusQuery(prometheusEndpoint)
+
+	alertmanagerEndpoint := newAlertmanagerEndpoint(clientset, config, opts.Alertmanager)
+	c.alertmanagerSilences = newAlertmanagerSilences(alertmanagerEndpoint)
+	c.alertmanagerAlerts = newAlertmanagerAlerts(alertmanagerEndpoint)
 
 	c.prometheusRuleManager = newPrometheusRuleManager(monitoringv1clientset)
 	c.prometheusRuleInformer = newPrometheusRuleInformer(monitoringv1clientset)
@@ -80,6 +104,10 @@ func newClient(_ context.Context, opts ClientOptions) (Client, error) {
 	c.alertRelabelConfigManager = newAlertRelabelConfigManager(osmv1clientset)
 	c.alertRelabelConfigInformer = newAlertRelabelConfigInformer(osmv1clientset)
 
+	c.namespaceManager = newNamespaceManager(clientset)
+
+	c.auth = newAuth(clientset)
+
 	return c, nil
 }
 
@@ -95,6 +123,22 @@ func (c *client) PrometheusAlerts() PrometheusAlertsInterface {
 	return c.prometheusAlerts
 }
 
+func (c *client) PrometheusRulesAPI() PrometheusRulesAPIInterface {
+	return c.prometheusRulesAPI
+}
+
+func (c *client) PrometheusQuery() PrometheusQueryInterface {
+	return c.prometheusQuery
+}
+
+func (c *client) AlertmanagerSilences() AlertmanagerSilencesInterface {
+	return c.alertmanagerSilences
+}
+
+func (c *client) AlertmanagerAlerts() AlertmanagerAlertsInterface {
+	return c.alertmanagerAlerts
+}
+
 func (c *client) PrometheusRules() PrometheusRuleInterface {
 	return c.prometheusRuleManager
 }
@@ -107,6 +151,14 @@ func (c *client) AlertRelabelConfigs() AlertRelabelConfigInterface {
 	return c.alertRelabelConfigManager
 }
 
+func (c *client) Namespaces() NamespaceInterface {
+	return c.namespaceManager
+}
+
 func (c *client) AlertRelabelConfigInformer() AlertRelabelConfigInformerInterface {
 	return c.alertRelabelConfigInformer
 }
+
+func (c *client) Auth() AuthInterface {
+	return c.auth
+}
@@ -0,0 +1,94 @@
+package k8s
+
+import (
+	"context"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// DryRunCreate is the operation of a dry-run write creating an object
+	DryRunCreate = "create"
+
+	// DryRunUpdate is the operation of a dry-run write updating an object
+	DryRunUpdate = "update"
+
+	// DryRunDelete is the operation of a dry-run write deleting an object
+	DryRunDelete = "delete"
+)
+
+// DryRunWrite is a write validated by the API server without being persisted
+type DryRunWrite struct {
+	// Operation is the type of write: create, update or delete
+	Operation string
+
+	// Kind is the kind of the written object, e.g. PrometheusRule
+	Kind string
+
+	// Namespace of the written object
+	Namespace string
+
+	// Name of the written object
+	Name string
+
+	// Before is the object before the write, nil for creates
+	Before runtime.Object
+
+	// After is the object returned by the API server, nil for deletes
+	After runtime.Object
+}
+
+// DryRun collects the writes performed with a context returned by WithDryRun
+type DryRun struct {
+	mu     sync.Mutex
+	writes []DryRunWrite
+}
+
+// Record adds a write to the dry run
+func (d *DryRun) Record(write DryRunWrite) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.writes = append(d.writes, write)
+}
+
+// Writes returns the writes recorded so far, in the order they were performed
+func (d *DryRun) Writes() []DryRunWrite {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return append([]DryRunWrite(nil), d.writes...)
+}
+
+type dryRunContextKey struct{}
+
+// WithDryRun returns a copy of ctx whose PrometheusRule and AlertRelabelConfig writes are sent with
+// DryRun: All, so that they are validated by the API server and its admission webhooks without being
+// persisted, and the DryRun recording them
+func WithDryRun(ctx context.Context) (context.Context, *DryRun) {
+	dryRun := &DryRun{}
+	return context.WithValue(ctx, dryRunContextKey{}, dryRun), dryRun
+}
+
+// DryRunFrom returns the DryRun carried by ctx, if any
+func DryRunFrom(ctx context.Context) (*DryRun, bool) {
+	dryRun, ok := ctx.Value(dryRunContextKey{}).(*DryRun)
+	return dryRun, ok
+}
+
+// dryRunOption returns the DryRun option of the writes performed with ctx
+func dryRunOption(ctx context.Context) []string {
+	if _, ok := DryRunFrom(ctx); ok {
+		return []string{metav1.DryRunAll}
+	}
+	return nil
+}
+
+// recordDryRun records the write if ctx is a dry run
+func recordDryRun(ctx context.Context, write DryRunWrite) {
+	if dryRun, ok := DryRunFrom(ctx); ok {
+		dryRun.Record(write)
+	}
+}
@@ -0,0 +1,60 @@
+package k8s
+
+import (
+	"fmt"
+
+	osmv1client "github.com/openshift/client-go/monitoring/clientset/versioned"
+	monitoringv1client "github.com/prometheus-operator/prometheus-operator/pkg/client/versioned"
+	"k8s.io/client-go/rest"
+)
+
+// Impersonate returns a client whose PrometheusRule and AlertRelabelConfig
+// requests impersonate the user, so that they are authorized and audited as
+// the user by the API server. Informers, alerts, loaded rules, queries, silences, namespaces and auth are shared with c
+func (c *client) Impersonate(user UserInfo) (Client, error) {
+	if user.Username == "" {
+		return nil, fmt.Errorf("cannot impersonate a user without username")
+	}
+
+	config := rest.CopyConfig(c.config)
+	config.Impersonate = rest.ImpersonationConfig{
+		UserName: user.Username,
+		UID:      user.UID,
+		Groups:   user.Groups,
+		Extra:    user.Extra,
+	}
+
+	monitoringv1clientset, err := monitoringv1client.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create impersonating monitoringv1 clientset: %w", err)
+	}
+
+	osmv1clientset, err := osmv1client.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create impersonating osmv1 clientset: %w", err)
+	}
+
+	return &client{
+		clientset:             c.clientset,
+		monitoringv1clientset: monitoringv1clientset,
+		osmv1clientset:        osmv1clientset,
+		config:                config,
+
+		prometheusAlerts:   c.prometheusAlerts,
+		prometheusRulesAPI: c.prometheusRulesAPI,
+		prometheusQuery:    c.prometheusQuery,
+
+		alertmanagerSilences: c.alertmanagerSilences,
+		alertmanagerAlerts:   c.alertmanagerAlerts,
+
+		prometheusRuleManager:  newPrometheusRuleManager(monitoringv1clientset),
+		prometheusRuleInformer: c.prometheusRuleInformer,
+
+		alertRelabelConfigManager:  newAlertRelabelConfigManager(osmv1clientset),
+		alertRelabelConfigInformer: c.alertRelabelConfigInformer,
+
+		namespaceManager: c.namespaceManager,
+
+		auth: c.auth,
+	}, nil
+}
@@ -0,0 +1,34 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+type namespaceManager struct {
+	clientset *kubernetes.Clientset
+}
+
+func newNamespaceManager(clientset *kubernetes.Clientset) NamespaceInterface {
+	return &namespaceManager{
+		clientset: clientset,
+	}
+}
+
+func (nm *namespaceManager) Get(ctx context.Context, name string) (*corev1.Namespace, bool, error) {
+	ns, err := nm.clientset.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, false, nil
+		}
+
+		return nil, false, fmt.Errorf("failed to get Namespace %s: %w", name, err)
+	}
+
+	return ns, true, nil
+}
@@ -2,31 +2,19 @@ package k8s
 
 import (
 	"context"
-	"crypto/tls"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
-	"os"
+	"net/url"
 	"time"
-
-	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/rest"
 )
 
 const (
-	prometheusRouteNamespace = "openshift-monitoring"
-	prometheusRouteName      = "prometheus-k8s"
-	prometheusAPIPath        = "/v1/alerts"
-)
-
-var (
-	prometheusRoutePath = fmt.Sprintf("/apis/route.openshift.io/v1/namespaces/%s/routes/%s", prometheusRouteNamespace, prometheusRouteName)
+	prometheusAlertsPath = "/api/v1/alerts"
 )
 
 type prometheusAlerts struct {
-	clientset *kubernetes.Clientset
-	config    *rest.Config
+	endpoint        *prometheusEndpoint
+	tenancyEndpoint *prometheusEndpoint
 }
 
 // GetAlertsRequest holds parameters for filtering alerts
@@ -35,6 +23,22 @@ type GetAlertsRequest struct {
 	Labels map[string]string
 	// State filters alerts by state: "firing", "pending", or "" for all states
 	State string
+	// Namespace restricts alerts to a namespace through the thanos-querier tenancy endpoint
+	Namespace string
+	// BearerToken is the token of the caller, required when Namespace is set
+	BearerToken string
+	// AlertRuleId filters alerts by the ID of the rule that produced them.
+	// It is applied by the management client
+	AlertRuleId string
+	// IncludeAlertmanager merges the status of the alerts in Alertmanager into the alerts.
+	// It is applied by the management client
+	IncludeAlertmanager bool
+	// Silenced filters alerts by whether Alertmanager silences them, nil for all alerts.
+	// It is applied by the management client and implies IncludeAlertmanager
+	Silenced *bool
+	// Inhibited filters alerts by whether Alertmanager inhibits them, nil for all alerts.
+	// It is applied by the management client and implies IncludeAlertmanager
+	Inhibited *bool
 }
 
 type PrometheusAlert struct {
@@ -43,6 +47,29 @@ type PrometheusAlert struct {
 	State       string            `json:"state"`
 	ActiveAt    time.Time         `json:"activeAt"`
 	Value       string            `json:"value"`
+
+	// The following fields are set by the management client for the alerts whose rule is found
+
+	// AlertRuleId is the ID of the rule that produced the alert
+	AlertRuleId string `json:"alertRuleId,omitempty"`
+	// PrometheusRuleNamespace is the namespace of the PrometheusRule of the rule
+	PrometheusRuleNamespace string `json:"prometheusRuleNamespace,omitempty"`
+	// PrometheusRuleName is the name of the PrometheusRule of the rule
+	PrometheusRuleName string `json:"prometheusRuleName,omitempty"`
+	// Source is the source of the rule: platform or user-defined
+	Source string `json:"source,omitempty"`
+
+	// The following fields are set from Alertmanager when requested with GetAlertsRequest.IncludeAlertmanager,
+	// for the alerts Alertmanager received. Pending alerts are not sent to Alertmanager
+
+	// Fingerprint identifies the alert in Alertmanager
+	Fingerprint string `json:"fingerprint,omitempty"`
+	// SilencedBy are the IDs of the silences muting the alert
+	SilencedBy []string `json:"silencedBy,omitempty"`
+	// InhibitedBy are the fingerprints of the alerts inhibiting the alert
+	InhibitedBy []string `json:"inhibitedBy,omitempty"`
+	// Receivers are the names of the receivers the alert is routed to
+	Receivers []string `json:"receivers,omitempty"`
 }
 
 type prometheusAlertsResponse struct {
@@ -52,15 +79,15 @@ type prometheusAlertsResponse struct {
 	} `json:"data"`
 }
 
-func newPrometheusAlerts(clientset *kubernetes.Clientset, config *rest.Config) PrometheusAlertsInterface {
+func newPrometheusAlerts(endpoint *prometheusEndpoint, tenancyEndpoint *prometheusEndpoint) PrometheusAlertsInterface {
 	return &prometheusAlerts{
-		clientset: clientset,
-		config:    config,
+		endpoint:        endpoint,
+		tenancyEndpoint: tenancyEndpoint,
 	}
 }
 
 func (pa prometheusAlerts) GetAlerts(ctx context.Context, req GetAlertsRequest) ([]PrometheusAlert, error) {
-	raw, err := pa.getAlertsViaProxy(ctx)
+	raw, err := pa.getAlerts(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -91,63 +118,22 @@ func (pa prometheusAlerts) GetAlerts(ctx context.Context, req GetAlertsRequest)
 	return out, nil
 }
 
-func (pa prometheusAlerts) getAlertsViaProxy(ctx context.Context) ([]byte, error) {
-	route, err := pa.clientset.CoreV1().RESTClient().
-		Get().
-		AbsPath(prometheusRoutePath).
-		DoRaw(ctx)
-
-	if err != nil {
-		return nil, fmt.Errorf("failed to get prometheus route: %w", err)
-	}
-
-	var routeObj struct {
-		Spec struct {
-			Host string `json:"host"`
-			Path string `json:"path"`
-		} `json:"spec"`
-	}
-	if err := json.Unmarshal(route, &routeObj); err != nil {
-		return nil, fmt.Errorf("failed to parse route: %w", err)
-	}
-
-	url := fmt.Sprintf("https://%s%s%s", routeObj.Spec.Host, routeObj.Spec.Path, prometheusAPIPath)
-
-	transport := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-	}
-	client := &http.Client{Transport: transport}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
+// getAlerts queries the cluster-wide endpoint with the client credentials, or
+// the tenancy endpoint with the caller token for namespace-scoped requests,
+// where the caller permissions on the namespace are enforced by thanos-querier
+func (pa prometheusAlerts) getAlerts(ctx context.Context, req GetAlertsRequest) ([]byte, error) {
+	if req.Namespace == "" {
+		return pa.endpoint.get(ctx, prometheusAlertsPath, nil, "")
 	}
 
-	token := pa.config.BearerToken
-	if token == "" && pa.config.BearerTokenFile != "" {
-		tokenBytes, err := os.ReadFile(pa.config.BearerTokenFile)
-		if err != nil {
-			return nil, fmt.Errorf("load bearer token file: %w", err)
-		}
-		token = string(tokenBytes)
+	if req.BearerToken == "" {
+		return nil, fmt.Errorf("a bearer token is required to get alerts for namespace %s", req.Namespace)
 	}
 
-	if token != "" {
-		req.Header.Set("Authorization", "Bearer "+token)
-	}
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("execute request: %w", err)
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(body))
-	}
+	query := url.Values{}
+	query.Set("namespace", req.Namespace)
 
-	return io.ReadAll(resp.Body)
+	return pa.tenancyEndpoint.get(ctx, prometheusAlertsPath, query, req.BearerToken)
 }
 
 func labelsMatch(req *GetAlertsRequest, alert *PrometheusAlert) bool {
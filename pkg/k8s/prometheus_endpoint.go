@@ -0,0 +1,310 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	defaultPrometheusRouteNamespace = "openshift-monitoring"
+	defaultPrometheusRouteName      = "prometheus-k8s"
+
+	defaultAlertmanagerRouteNamespace = "openshift-monitoring"
+	defaultAlertmanagerRouteName      = "alertmanager-main"
+
+	defaultTenancyURL = "https://thanos-querier.openshift-monitoring.svc:9093"
+
+	serviceCAConfigMapKey = "service-ca.crt"
+
+	// inClusterServiceCAFile is the service CA bundle OpenShift mounts in every pod
+	inClusterServiceCAFile = "/var/run/secrets/kubernetes.io/serviceaccount/service-ca.crt"
+)
+
+// prometheusEndpoint performs requests against a Prometheus compatible API,
+// resolved from a direct URL, a service proxy or a Route
+type prometheusEndpoint struct {
+	clientset *kubernetes.Clientset
+	config    *rest.Config
+	opts      PrometheusOptions
+
+	mu         sync.Mutex
+	httpClient *http.Client
+}
+
+func newPrometheusEndpoint(clientset *kubernetes.Clientset, config *rest.Config, opts PrometheusOptions) *prometheusEndpoint {
+	return newEndpoint(clientset, config, opts, types.NamespacedName{Namespace: defaultPrometheusRouteNamespace, Name: defaultPrometheusRouteName})
+}
+
+// newAlertmanagerEndpoint returns the endpoint of the Alertmanager API, which
+// is resolved like the Prometheus API
+func newAlertmanagerEndpoint(clientset *kubernetes.Clientset, config *rest.Config, opts PrometheusOptions) *prometheusEndpoint {
+	return newEndpoint(clientset, config, opts, types.NamespacedName{Namespace: defaultAlertmanagerRouteNamespace, Name: defaultAlertmanagerRouteName})
+}
+
+func newEndpoint(clientset *kubernetes.Clientset, config *rest.Config, opts PrometheusOptions, defaultRoute types.NamespacedName) *prometheusEndpoint {
+	if opts.URL == "" && opts.Service == nil && opts.Route.Name == "" {
+		opts.Route = defaultRoute
+	}
+
+	return &prometheusEndpoint{
+		clientset: clientset,
+		config:    config,
+		opts:      opts,
+	}
+}
+
+// tenancyOptions returns the options for the thanos-querier tenancy endpoint,
+// which shares the TLS configuration of the main endpoint
+func (opts PrometheusOptions) tenancyOptions() PrometheusOptions {
+	tenancyURL := opts.TenancyURL
+	if tenancyURL == "" {
+		tenancyURL = defaultTenancyURL
+	}
+
+	return PrometheusOptions{
+		URL:                tenancyURL,
+		CAFile:             opts.CAFile,
+		ServiceCAConfigMap: opts.ServiceCAConfigMap,
+		InsecureSkipVerify: opts.InsecureSkipVerify,
+	}
+}
+
+// EndpointError is returned when the Prometheus or Alertmanager API responds
+// with an error status
+type EndpointError struct {
+	// StatusCode is the HTTP status of the response
+	StatusCode int
+
+	// Body is the body of the response
+	Body string
+}
+
+func (e *EndpointError) Error() string {
+	return fmt.Sprintf("unexpected status %d: %s", e.StatusCode, e.Body)
+}
+
+// get performs a GET request to the given API path, e.g. /api/v1/alerts, and
+// returns the response body. If bearerToken is set, it is used instead of the
+// client credentials
+func (pe *prometheusEndpoint) get(ctx context.Context, path string, query url.Values, bearerToken string) ([]byte, error) {
+	return pe.do(ctx, http.MethodGet, path, query, nil, bearerToken)
+}
+
+// do performs a request to the given API path with a JSON body, if any, and
+// returns the response body
+func (pe *prometheusEndpoint) do(ctx context.Context, method string, path string, query url.Values, body []byte, bearerToken string) ([]byte, error) {
+	if pe.opts.URL == "" && pe.opts.Service != nil {
+		if bearerToken != "" {
+			return nil, fmt.Errorf("bearer tokens cannot be forwarded through the service proxy")
+		}
+		return pe.doViaServiceProxy(ctx, method, path, query, body)
+	}
+
+	baseURL, err := pe.baseURL(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := pe.getHTTPClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reqURL := strings.TrimSuffix(baseURL, "/") + path
+	if len(query) > 0 {
+		reqURL += "?" + query.Encode()
+	}
+
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, bodyReader)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	token := bearerToken
+	if token == "" {
+		token, err = pe.bearerToken()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("execute request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, &EndpointError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+func (pe *prometheusEndpoint) doViaServiceProxy(ctx context.Context, method string, path string, query url.Values, body []byte) ([]byte, error) {
+	svc := pe.opts.Service
+
+	scheme := svc.Scheme
+	if scheme == "" {
+		scheme = "https"
+	}
+
+	req := pe.clientset.CoreV1().RESTClient().
+		Verb(method).
+		AbsPath("/api/v1/namespaces", svc.Namespace, "services", fmt.Sprintf("%s:%s:%s", scheme, svc.Name, svc.Port), "proxy", path)
+	for key, values := range query {
+		for _, value := range values {
+			req = req.Param(key, value)
+		}
+	}
+	if body != nil {
+		req = req.SetHeader("Content-Type", "application/json").Body(body)
+	}
+
+	raw, err := req.DoRaw(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to %s %s via service proxy %s/%s: %w", strings.ToLower(method), path, svc.Namespace, svc.Name, err)
+	}
+
+	return raw, nil
+}
+
+func (pe *prometheusEndpoint) baseURL(ctx context.Context) (string, error) {
+	if pe.opts.URL != "" {
+		return pe.opts.URL, nil
+	}
+
+	routePath := fmt.Sprintf("/apis/route.openshift.io/v1/namespaces/%s/routes/%s", pe.opts.Route.Namespace, pe.opts.Route.Name)
+	route, err := pe.clientset.CoreV1().RESTClient().
+		Get().
+		AbsPath(routePath).
+		DoRaw(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get route %s/%s: %w", pe.opts.Route.Namespace, pe.opts.Route.Name, err)
+	}
+
+	var routeObj struct {
+		Spec struct {
+			Host string `json:"host"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(route, &routeObj); err != nil {
+		return "", fmt.Errorf("failed to parse route: %w", err)
+	}
+
+	return fmt.Sprintf("https://%s", routeObj.Spec.Host), nil
+}
+
+// getHTTPClient returns the HTTP client used for direct and Route requests,
+// building it on first use so the transport and its connections are reused
+func (pe *prometheusEndpoint) getHTTPClient(ctx context.Context) (*http.Client, error) {
+	pe.mu.Lock()
+	defer pe.mu.Unlock()
+
+	if pe.httpClient != nil {
+		return pe.httpClient, nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if pe.opts.InsecureSkipVerify {
+		tlsConfig.InsecureSkipVerify = true // #nosec G402 -- explicitly requested through PrometheusOptions
+	} else {
+		rootCAs, err := pe.loadRootCAs(ctx)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = rootCAs
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	pe.httpClient = &http.Client{Transport: transport}
+	return pe.httpClient, nil
+}
+
+// loadRootCAs returns the system CAs extended with the CAs of the kubeconfig,
+// the in-cluster service CA and the configured CA file or service CA ConfigMap
+func (pe *prometheusEndpoint) loadRootCAs(ctx context.Context) (*x509.CertPool, error) {
+	rootCAs, err := x509.SystemCertPool()
+	if err != nil {
+		rootCAs = x509.NewCertPool()
+	}
+
+	if len(pe.config.CAData) > 0 {
+		rootCAs.AppendCertsFromPEM(pe.config.CAData)
+	} else if pe.config.CAFile != "" {
+		if caData, err := os.ReadFile(pe.config.CAFile); err == nil {
+			rootCAs.AppendCertsFromPEM(caData)
+		}
+	}
+
+	if caData, err := os.ReadFile(inClusterServiceCAFile); err == nil {
+		rootCAs.AppendCertsFromPEM(caData)
+	}
+
+	if pe.opts.CAFile != "" {
+		caData, err := os.ReadFile(pe.opts.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("load CA file: %w", err)
+		}
+		if !rootCAs.AppendCertsFromPEM(caData) {
+			return nil, fmt.Errorf("no certificates found in CA file %s", pe.opts.CAFile)
+		}
+	}
+
+	if cm := pe.opts.ServiceCAConfigMap; cm.Name != "" {
+		configMap, err := pe.clientset.CoreV1().ConfigMaps(cm.Namespace).Get(ctx, cm.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get service CA ConfigMap %s: %w", cm, err)
+		}
+		if !rootCAs.AppendCertsFromPEM([]byte(configMap.Data[serviceCAConfigMapKey])) {
+			return nil, fmt.Errorf("no certificates found in key %s of ConfigMap %s", serviceCAConfigMapKey, cm)
+		}
+	}
+
+	return rootCAs, nil
+}
+
+func (pe *prometheusEndpoint) bearerToken() (string, error) {
+	token := pe.config.BearerToken
+	if token == "" && pe.config.BearerTokenFile != "" {
+		tokenBytes, err := os.ReadFile(pe.config.BearerTokenFile)
+		if err != nil {
+			return "", fmt.Errorf("load bearer token file: %w", err)
+		}
+		token = strings.TrimSpace(string(tokenBytes))
+	}
+
+	return token, nil
+}
@@ -0,0 +1,131 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	prometheusQueryRangePath = "/api/v1/query_range"
+)
+
+// QueryRangeRequest holds the parameters of a range query
+type QueryRangeRequest struct {
+	// Query is the PromQL expression to evaluate
+	Query string
+
+	// Start is the first evaluation time
+	Start time.Time
+
+	// End is the last evaluation time
+	End time.Time
+
+	// Step is the interval between evaluations
+	Step time.Duration
+}
+
+// RangeSeries is a series returned by a range query
+type RangeSeries struct {
+	// Metric holds the labels of the series
+	Metric map[string]string `json:"metric"`
+
+	// Samples are the values of the series, ordered by time. Evaluations without a value are omitted
+	Samples []Sample `json:"values"`
+}
+
+// Sample is the value of a series at an evaluation time
+type Sample struct {
+	// Time is the evaluation time
+	Time time.Time
+
+	// Value is the value of the series
+	Value float64
+}
+
+// UnmarshalJSON decodes the [<unix time>, "<value>"] pairs of the Prometheus API
+func (s *Sample) UnmarshalJSON(data []byte) error {
+	var pair [2]json.RawMessage
+	if err := json.Unmarshal(data, &pair); err != nil {
+		return fmt.Errorf("decode sample: %w", err)
+	}
+
+	var timestamp float64
+	if err := json.Unmarshal(pair[0], &timestamp); err != nil {
+		return fmt.Errorf("decode sample time: %w", err)
+	}
+
+	var value string
+	if err := json.Unmarshal(pair[1], &value); err != nil {
+		return fmt.Errorf("decode sample value: %w", err)
+	}
+
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fmt.Errorf("decode sample value: %w", err)
+	}
+
+	seconds, fraction := math.Modf(timestamp)
+	s.Time = time.Unix(int64(seconds), int64(math.Round(fraction*1e3))*int64(time.Millisecond)).UTC()
+	s.Value = parsed
+	return nil
+}
+
+type prometheusQueryResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		ResultType string        `json:"resultType"`
+		Result     []RangeSeries `json:"result"`
+	} `json:"data"`
+}
+
+type prometheusQuery struct {
+	endpoint *prometheusEndpoint
+}
+
+func newPrometheusQuery(endpoint *prometheusEndpoint) PrometheusQueryInterface {
+	return &prometheusQuery{
+		endpoint: endpoint,
+	}
+}
+
+func (pq *prometheusQuery) QueryRange(ctx context.Context, req QueryRangeRequest) ([]RangeSeries, error) {
+	if req.Step <= 0 {
+		return nil, fmt.Errorf("range query step must be positive")
+	}
+
+	query := url.Values{}
+	query.Set("query", req.Query)
+	query.Set("start", formatPrometheusTime(req.Start))
+	query.Set("end", formatPrometheusTime(req.End))
+	query.Set("step", strconv.FormatFloat(req.Step.Seconds(), 'f', -1, 64))
+
+	raw, err := pq.endpoint.get(ctx, prometheusQueryRangePath, query, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query range: %w", err)
+	}
+
+	var queryResp prometheusQueryResponse
+	if err := json.Unmarshal(raw, &queryResp); err != nil {
+		return nil, fmt.Errorf("decode prometheus response: %w", err)
+	}
+
+	if queryResp.Status != "success" {
+		return nil, fmt.Errorf("prometheus API returned non-success status: %s", queryResp.Status)
+	}
+
+	if queryResp.Data.ResultType != "matrix" {
+		return nil, fmt.Errorf("prometheus API returned %s result for a range query", queryResp.Data.ResultType)
+	}
+
+	return queryResp.Data.Result, nil
+}
+
+// formatPrometheusTime formats t as the Unix time in seconds accepted by the Prometheus API
+func formatPrometheusTime(t time.Time) string {
+	return strconv.FormatFloat(float64(t.UnixMilli())/1e3, 'f', -1, 64)
+}
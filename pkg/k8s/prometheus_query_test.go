@@ -0,0 +1,79 @@
+package k8s
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/rest"
+)
+
+var _ = Describe("PrometheusQuery", func() {
+	var (
+		ctx      context.Context
+		response string
+		query    url.Values
+		promql   PrometheusQueryInterface
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if req.URL.Path != prometheusQueryRangePath {
+				http.NotFound(w, req)
+				return
+			}
+			query = req.URL.Query()
+			_, _ = w.Write([]byte(response))
+		}))
+		DeferCleanup(srv.Close)
+
+		promql = newPrometheusQuery(newPrometheusEndpoint(nil, &rest.Config{}, PrometheusOptions{URL: srv.URL}))
+	})
+
+	It("should send the range and return the series with their samples", func() {
+		response = `{"status": "success", "data": {"resultType": "matrix", "result": [{
+			"metric": {"__name__": "ALERTS", "alertname": "Foo"},
+			"values": [[1704110400, "1"], [1704110430.5, "1"]]
+		}]}}`
+
+		start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+		series, err := promql.QueryRange(ctx, QueryRangeRequest{
+			Query: `ALERTS{alertname="Foo"}`,
+			Start: start,
+			End:   start.Add(time.Hour),
+			Step:  30 * time.Second,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(query.Get("query")).To(Equal(`ALERTS{alertname="Foo"}`))
+		Expect(query.Get("start")).To(Equal("1704110400"))
+		Expect(query.Get("end")).To(Equal("1704114000"))
+		Expect(query.Get("step")).To(Equal("30"))
+
+		Expect(series).To(Equal([]RangeSeries{{
+			Metric: map[string]string{"__name__": "ALERTS", "alertname": "Foo"},
+			Samples: []Sample{
+				{Time: start, Value: 1},
+				{Time: start.Add(30*time.Second + 500*time.Millisecond), Value: 1},
+			},
+		}}))
+	})
+
+	It("should return an error for non-matrix results", func() {
+		response = `{"status": "success", "data": {"resultType": "vector", "result": []}}`
+
+		_, err := promql.QueryRange(ctx, QueryRangeRequest{Query: "up", Start: time.Now().Add(-time.Hour), End: time.Now(), Step: time.Minute})
+		Expect(err).To(MatchError(ContainSubstring("vector result")))
+	})
+
+	It("should reject requests without a step", func() {
+		_, err := promql.QueryRange(ctx, QueryRangeRequest{Query: "up", Start: time.Now().Add(-time.Hour), End: time.Now()})
+		Expect(err).To(MatchError(ContainSubstring("step must be positive")))
+	})
+})
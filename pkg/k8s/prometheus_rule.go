@@ -9,6 +9,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
 )
 
 type prometheusRuleManager struct {
@@ -43,30 +44,74 @@ func (prm *prometheusRuleManager) Get(ctx context.Context, namespace string, nam
 	return pr, true, nil
 }
 
+func (prm *prometheusRuleManager) Create(ctx context.Context, pr monitoringv1.PrometheusRule) error {
+	created, err := prm.clientset.MonitoringV1().PrometheusRules(pr.Namespace).Create(ctx, &pr, metav1.CreateOptions{DryRun: dryRunOption(ctx)})
+	if err != nil {
+		return fmt.Errorf("failed to create PrometheusRule %s/%s: %w", pr.Namespace, pr.Name, err)
+	}
+
+	recordDryRun(ctx, prometheusRuleWrite(DryRunCreate, nil, created))
+	return nil
+}
+
 func (prm *prometheusRuleManager) Update(ctx context.Context, pr monitoringv1.PrometheusRule) error {
-	_, err := prm.clientset.MonitoringV1().PrometheusRules(pr.Namespace).Update(ctx, &pr, metav1.UpdateOptions{})
+	before := prm.dryRunBefore(ctx, pr.Namespace, pr.Name)
+
+	updated, err := prm.clientset.MonitoringV1().PrometheusRules(pr.Namespace).Update(ctx, &pr, metav1.UpdateOptions{DryRun: dryRunOption(ctx)})
 	if err != nil {
 		return fmt.Errorf("failed to update PrometheusRule %s/%s: %w", pr.Namespace, pr.Name, err)
 	}
 
+	recordDryRun(ctx, prometheusRuleWrite(DryRunUpdate, before, updated))
 	return nil
 }
 
 func (prm *prometheusRuleManager) Delete(ctx context.Context, namespace string, name string) error {
-	err := prm.clientset.MonitoringV1().PrometheusRules(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	before := prm.dryRunBefore(ctx, namespace, name)
+
+	err := prm.clientset.MonitoringV1().PrometheusRules(namespace).Delete(ctx, name, metav1.DeleteOptions{DryRun: dryRunOption(ctx)})
+	if err != nil {
+		return fmt.Errorf("failed to delete PrometheusRule %s: %w", name, err)
+	}
+
+	recordDryRun(ctx, prometheusRuleWrite(DryRunDelete, before, nil))
+	return nil
+}
+
+func (prm *prometheusRuleManager) DeleteWithResourceVersion(ctx context.Context, namespace string, name string, resourceVersion string) error {
+	before := prm.dryRunBefore(ctx, namespace, name)
+
+	err := prm.clientset.MonitoringV1().PrometheusRules(namespace).Delete(ctx, name, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{ResourceVersion: &resourceVersion},
+		DryRun:        dryRunOption(ctx),
+	})
 	if err != nil {
 		return fmt.Errorf("failed to delete PrometheusRule %s: %w", name, err)
 	}
 
+	recordDryRun(ctx, prometheusRuleWrite(DryRunDelete, before, nil))
 	return nil
 }
 
 func (prm *prometheusRuleManager) AddRule(ctx context.Context, namespacedName types.NamespacedName, groupName string, rule monitoringv1.Rule) error {
-	pr, err := prm.getOrCreatePrometheusRule(ctx, namespacedName)
+	// The PrometheusRule is re-read on conflicts, so that rules added or
+	// edited concurrently are preserved
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		return prm.addRule(ctx, namespacedName, groupName, rule)
+	})
+}
+
+func (prm *prometheusRuleManager) addRule(ctx context.Context, namespacedName types.NamespacedName, groupName string, rule monitoringv1.Rule) error {
+	pr, found, err := prm.Get(ctx, namespacedName.Namespace, namespacedName.Name)
 	if err != nil {
 		return err
 	}
 
+	if !found {
+		return prm.createWithRule(ctx, namespacedName, groupName, rule)
+	}
+	before := pr.DeepCopy()
+
 	// Find or create the group
 	var group *monitoringv1.RuleGroup
 	for i := range pr.Spec.Groups {
@@ -86,42 +131,64 @@ func (prm *prometheusRuleManager) AddRule(ctx context.Context, namespacedName ty
 	// Add the new rule to the group
 	group.Rules = append(group.Rules, rule)
 
-	_, err = prm.clientset.MonitoringV1().PrometheusRules(namespacedName.Namespace).Update(ctx, pr, metav1.UpdateOptions{})
+	updated, err := prm.clientset.MonitoringV1().PrometheusRules(namespacedName.Namespace).Update(ctx, pr, metav1.UpdateOptions{DryRun: dryRunOption(ctx)})
 	if err != nil {
 		return fmt.Errorf("failed to update PrometheusRule %s/%s: %w", namespacedName.Namespace, namespacedName.Name, err)
 	}
 
+	recordDryRun(ctx, prometheusRuleWrite(DryRunUpdate, before, updated))
 	return nil
 }
 
-func (prm *prometheusRuleManager) getOrCreatePrometheusRule(ctx context.Context, namespacedName types.NamespacedName) (*monitoringv1.PrometheusRule, error) {
-	pr, err := prm.clientset.MonitoringV1().PrometheusRules(namespacedName.Namespace).Get(ctx, namespacedName.Name, metav1.GetOptions{})
-	if err != nil {
-		if errors.IsNotFound(err) {
-			return prm.createPrometheusRule(ctx, namespacedName)
-		}
-
-		return nil, fmt.Errorf("failed to get PrometheusRule %s/%s: %w", namespacedName.Namespace, namespacedName.Name, err)
-	}
-
-	return pr, nil
-}
-
-func (prm *prometheusRuleManager) createPrometheusRule(ctx context.Context, namespacedName types.NamespacedName) (*monitoringv1.PrometheusRule, error) {
+// createWithRule creates a PrometheusRule holding the rule in a single write,
+// so that dry runs show the PrometheusRule that would be created
+func (prm *prometheusRuleManager) createWithRule(ctx context.Context, namespacedName types.NamespacedName, groupName string, rule monitoringv1.Rule) error {
 	pr := &monitoringv1.PrometheusRule{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      namespacedName.Name,
 			Namespace: namespacedName.Namespace,
 		},
 		Spec: monitoringv1.PrometheusRuleSpec{
-			Groups: []monitoringv1.RuleGroup{},
+			Groups: []monitoringv1.RuleGroup{{Name: groupName, Rules: []monitoringv1.Rule{rule}}},
 		},
 	}
 
-	pr, err := prm.clientset.MonitoringV1().PrometheusRules(namespacedName.Namespace).Create(ctx, pr, metav1.CreateOptions{})
+	created, err := prm.clientset.MonitoringV1().PrometheusRules(namespacedName.Namespace).Create(ctx, pr, metav1.CreateOptions{DryRun: dryRunOption(ctx)})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create PrometheusRule %s/%s: %w", namespacedName.Namespace, namespacedName.Name, err)
+		// The PrometheusRule was created concurrently, the rule is added to it on retry
+		if errors.IsAlreadyExists(err) {
+			return errors.NewConflict(monitoringv1.SchemeGroupVersion.WithResource(monitoringv1.PrometheusRuleName).GroupResource(), namespacedName.Name, err)
+		}
+		return fmt.Errorf("failed to create PrometheusRule %s/%s: %w", namespacedName.Namespace, namespacedName.Name, err)
 	}
 
-	return pr, nil
+	recordDryRun(ctx, prometheusRuleWrite(DryRunCreate, nil, created))
+	return nil
+}
+
+// dryRunBefore returns the PrometheusRule before a dry-run write, or nil if ctx
+// is not a dry run
+func (prm *prometheusRuleManager) dryRunBefore(ctx context.Context, namespace string, name string) *monitoringv1.PrometheusRule {
+	if _, ok := DryRunFrom(ctx); !ok {
+		return nil
+	}
+
+	pr, found, err := prm.Get(ctx, namespace, name)
+	if err != nil || !found {
+		return nil
+	}
+	return pr
+}
+
+func prometheusRuleWrite(operation string, before *monitoringv1.PrometheusRule, after *monitoringv1.PrometheusRule) DryRunWrite {
+	write := DryRunWrite{Operation: operation, Kind: monitoringv1.PrometheusRuleKind}
+	if before != nil {
+		write.Namespace, write.Name = before.Namespace, before.Name
+		write.Before = before
+	}
+	if after != nil {
+		write.Namespace, write.Name = after.Namespace, after.Name
+		write.After = after
+	}
+	return write
 }
@@ -3,60 +3,98 @@ package k8s
 import (
 	"context"
 	"log"
+	"sync"
 
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	monitoringv1client "github.com/prometheus-operator/prometheus-operator/pkg/client/versioned"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
 )
 
 type prometheusRuleInformer struct {
-	clientset *monitoringv1client.Clientset
+	informer cache.SharedIndexInformer
+
+	mu           sync.RWMutex
+	registration cache.ResourceEventHandlerRegistration
 }
 
 func newPrometheusRuleInformer(clientset *monitoringv1client.Clientset) PrometheusRuleInformerInterface {
+	listWatch := &cache.ListWatch{
+		ListWithContextFunc: func(ctx context.Context, options metav1.ListOptions) (runtime.Object, error) {
+			return clientset.MonitoringV1().PrometheusRules("").List(ctx, options)
+		},
+		WatchFuncWithContext: func(ctx context.Context, options metav1.ListOptions) (watch.Interface, error) {
+			return clientset.MonitoringV1().PrometheusRules("").Watch(ctx, options)
+		},
+	}
+
 	return &prometheusRuleInformer{
-		clientset: clientset,
+		informer: cache.NewSharedIndexInformer(listWatch, &monitoringv1.PrometheusRule{}, informerResyncPeriod, cache.Indexers{}),
 	}
 }
 
 func (pri *prometheusRuleInformer) Run(ctx context.Context, callbacks PrometheusRuleInformerCallback) error {
-	options := metav1.ListOptions{
-		Watch: true,
-	}
-
-	watcher, err := pri.clientset.MonitoringV1().PrometheusRules("").Watch(ctx, options)
-	if err != nil {
-		return err
-	}
-	defer watcher.Stop()
-
-	ch := watcher.ResultChan()
-	for event := range ch {
-		pr, ok := event.Object.(*monitoringv1.PrometheusRule)
-		if !ok {
-			log.Printf("Unexpected type: %v", event.Object)
-			continue
-		}
-
-		switch event.Type {
-		case watch.Added:
+	registration, err := pri.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			pr, ok := obj.(*monitoringv1.PrometheusRule)
+			if !ok {
+				log.Printf("Unexpected type: %T", obj)
+				return
+			}
 			if callbacks.OnAdd != nil {
 				callbacks.OnAdd(pr)
 			}
-		case watch.Modified:
+		},
+		UpdateFunc: func(_, newObj interface{}) {
+			pr, ok := newObj.(*monitoringv1.PrometheusRule)
+			if !ok {
+				log.Printf("Unexpected type: %T", newObj)
+				return
+			}
 			if callbacks.OnUpdate != nil {
 				callbacks.OnUpdate(pr)
 			}
-		case watch.Deleted:
+		},
+		DeleteFunc: func(obj interface{}) {
+			// The final state of the object is unknown if the delete event was
+			// missed while the watch was disconnected
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			pr, ok := obj.(*monitoringv1.PrometheusRule)
+			if !ok {
+				log.Printf("Unexpected type: %T", obj)
+				return
+			}
 			if callbacks.OnDelete != nil {
 				callbacks.OnDelete(pr)
 			}
-		case watch.Error:
-			log.Printf("Error occurred while watching PrometheusRule: %s\n", event.Object)
-		}
+		},
+	})
+	if err != nil {
+		return err
 	}
 
-	log.Fatalf("PrometheusRule watcher channel closed unexpectedly")
+	pri.mu.Lock()
+	pri.registration = registration
+	pri.mu.Unlock()
+
+	// The informer lists the PrometheusRules before watching them, resumes the
+	// watch from the last seen resourceVersion and reconnects with backoff
+	// until the context is cancelled
+	pri.informer.RunWithContext(ctx)
 	return nil
 }
+
+func (pri *prometheusRuleInformer) HasSynced() bool {
+	pri.mu.RLock()
+	defer pri.mu.RUnlock()
+
+	return pri.registration != nil && pri.registration.HasSynced()
+}
+
+func (pri *prometheusRuleInformer) WaitForCacheSync(ctx context.Context) bool {
+	return cache.WaitForCacheSync(ctx.Done(), pri.HasSynced)
+}
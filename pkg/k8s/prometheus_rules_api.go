@@ -0,0 +1,110 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	prometheusRulesPath = "/api/v1/rules"
+)
+
+const (
+	// RuleHealthOk is the health of rules whose last evaluation succeeded
+	RuleHealthOk = "ok"
+
+	// RuleHealthErr is the health of rules whose last evaluation failed
+	RuleHealthErr = "err"
+
+	// RuleHealthUnknown is the health of rules not evaluated yet
+	RuleHealthUnknown = "unknown"
+)
+
+// LoadedRuleGroup is a rule group as loaded and evaluated by Prometheus
+type LoadedRuleGroup struct {
+	// Name of the group
+	Name string `json:"name"`
+
+	// File is the rule file of the group. The prometheus-operator writes the rules
+	// of each PrometheusRule to <namespace>-<name>-<uid>.yaml
+	File string `json:"file"`
+
+	// Rules are the rules of the group, in the order of the rule file
+	Rules []LoadedRule `json:"rules"`
+
+	// Interval is the evaluation interval of the group in seconds
+	Interval float64 `json:"interval"`
+
+	// LastEvaluation is the time the group was last evaluated
+	LastEvaluation time.Time `json:"lastEvaluation"`
+
+	// EvaluationTime is the duration of the last evaluation of the group in seconds
+	EvaluationTime float64 `json:"evaluationTime"`
+}
+
+// LoadedRule is an alerting or recording rule as loaded and evaluated by Prometheus
+type LoadedRule struct {
+	// Name is the alert name or the recorded metric name
+	Name string `json:"name"`
+
+	// Type is alerting or recording
+	Type string `json:"type"`
+
+	// Query is the expression of the rule, as formatted by Prometheus
+	Query string `json:"query"`
+
+	// Labels are the labels of the rule
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Health is the health of the last evaluation: ok, err or unknown
+	Health string `json:"health"`
+
+	// LastError is the error of the last evaluation, if it failed
+	LastError string `json:"lastError,omitempty"`
+
+	// LastEvaluation is the time the rule was last evaluated
+	LastEvaluation time.Time `json:"lastEvaluation"`
+
+	// EvaluationTime is the duration of the last evaluation in seconds
+	EvaluationTime float64 `json:"evaluationTime"`
+
+	// State is the state of alerting rules: inactive, pending or firing
+	State string `json:"state,omitempty"`
+}
+
+type prometheusRulesResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		Groups []LoadedRuleGroup `json:"groups"`
+	} `json:"data"`
+}
+
+type prometheusRulesAPI struct {
+	endpoint *prometheusEndpoint
+}
+
+func newPrometheusRulesAPI(endpoint *prometheusEndpoint) PrometheusRulesAPIInterface {
+	return &prometheusRulesAPI{
+		endpoint: endpoint,
+	}
+}
+
+func (pr *prometheusRulesAPI) GetRuleGroups(ctx context.Context) ([]LoadedRuleGroup, error) {
+	raw, err := pr.endpoint.get(ctx, prometheusRulesPath, nil, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rules: %w", err)
+	}
+
+	var rulesResp prometheusRulesResponse
+	if err := json.Unmarshal(raw, &rulesResp); err != nil {
+		return nil, fmt.Errorf("decode prometheus response: %w", err)
+	}
+
+	if rulesResp.Status != "success" {
+		return nil, fmt.Errorf("prometheus API returned non-success status: %s", rulesResp.Status)
+	}
+
+	return rulesResp.Data.Groups, nil
+}
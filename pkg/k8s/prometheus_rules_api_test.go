@@ -0,0 +1,81 @@
+package k8s
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/rest"
+)
+
+var _ = Describe("PrometheusRulesAPI", func() {
+	var (
+		ctx      context.Context
+		response string
+		rulesAPI PrometheusRulesAPIInterface
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if req.URL.Path != prometheusRulesPath {
+				http.NotFound(w, req)
+				return
+			}
+			_, _ = w.Write([]byte(response))
+		}))
+		DeferCleanup(srv.Close)
+
+		rulesAPI = newPrometheusRulesAPI(newPrometheusEndpoint(nil, &rest.Config{}, PrometheusOptions{URL: srv.URL}))
+	})
+
+	It("should return the rule groups with the status of their rules", func() {
+		response = `{"status": "success", "data": {"groups": [{
+			"name": "g1",
+			"file": "/etc/prometheus/rules/prometheus-k8s-rulefiles-0/team-a-rules-uid.yaml",
+			"interval": 30,
+			"evaluationTime": 0.002,
+			"lastEvaluation": "2024-01-01T12:00:00Z",
+			"rules": [{
+				"name": "Foo",
+				"type": "alerting",
+				"query": "up == 0",
+				"labels": {"severity": "warning"},
+				"health": "err",
+				"lastError": "query timed out",
+				"lastEvaluation": "2024-01-01T12:00:00Z",
+				"evaluationTime": 0.001,
+				"state": "inactive",
+				"alerts": []
+			}]
+		}]}}`
+
+		groups, err := rulesAPI.GetRuleGroups(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(groups).To(HaveLen(1))
+		Expect(groups[0].File).To(HaveSuffix("team-a-rules-uid.yaml"))
+		Expect(groups[0].Interval).To(Equal(30.0))
+		Expect(groups[0].Rules).To(Equal([]LoadedRule{{
+			Name:           "Foo",
+			Type:           "alerting",
+			Query:          "up == 0",
+			Labels:         map[string]string{"severity": "warning"},
+			Health:         RuleHealthErr,
+			LastError:      "query timed out",
+			LastEvaluation: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+			EvaluationTime: 0.001,
+			State:          "inactive",
+		}}))
+	})
+
+	It("should return an error for non-success responses", func() {
+		response = `{"status": "error", "data": {}}`
+
+		_, err := rulesAPI.GetRuleGroups(ctx)
+		Expect(err).To(MatchError(ContainSubstring("non-success status")))
+	})
+})
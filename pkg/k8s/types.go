@@ -5,6 +5,7 @@ import (
 
 	osmv1 "github.com/openshift/api/monitoring/v1"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
 
@@ -13,6 +14,57 @@ type ClientOptions struct {
 	// KubeconfigPath specifies the path to the kubeconfig file for remote connections
 	// If empty, will try default locations or in-cluster config
 	KubeconfigPath string
+
+	// Prometheus configures the Prometheus compatible API used to retrieve alerts
+	Prometheus PrometheusOptions
+
+	// Alertmanager configures the Alertmanager API used to manage silences. It is resolved like the
+	// Prometheus API, defaulting to the openshift-monitoring/alertmanager-main Route. TenancyURL is not used
+	Alertmanager PrometheusOptions
+}
+
+// PrometheusOptions configures how the Prometheus compatible API is reached.
+// URL takes precedence over Service, which takes precedence over Route
+type PrometheusOptions struct {
+	// URL is the base URL of the API, e.g. https://thanos-querier.openshift-monitoring.svc:9091
+	URL string
+
+	// Service reaches the API through the Kubernetes API server service proxy
+	Service *ServiceReference
+
+	// Route is the OpenShift Route exposing the API
+	// If URL, Service and Route are empty, defaults to the openshift-monitoring/prometheus-k8s Route
+	Route types.NamespacedName
+
+	// TenancyURL is the base URL of the thanos-querier tenancy endpoint used for namespace-scoped requests
+	// Defaults to https://thanos-querier.openshift-monitoring.svc:9093
+	TenancyURL string
+
+	// CAFile is the path to a PEM encoded CA bundle used to verify the API certificate
+	CAFile string
+
+	// ServiceCAConfigMap is a ConfigMap holding the service CA bundle in its service-ca.crt key,
+	// e.g. a ConfigMap annotated with service.beta.openshift.io/inject-cabundle
+	ServiceCAConfigMap types.NamespacedName
+
+	// InsecureSkipVerify disables the verification of the API certificate
+	// It should only be used for development
+	InsecureSkipVerify bool
+}
+
+// ServiceReference identifies a port of a Kubernetes Service
+type ServiceReference struct {
+	// Namespace of the Service
+	Namespace string
+
+	// Name of the Service
+	Name string
+
+	// Port is the name or number of the Service port
+	Port string
+
+	// Scheme is the scheme used by the Service port, defaults to https
+	Scheme string
 }
 
 // Client defines the contract for Kubernetes client operations
@@ -23,6 +75,18 @@ type Client interface {
 	// PrometheusAlerts retrieves active Prometheus alerts
 	PrometheusAlerts() PrometheusAlertsInterface
 
+	// PrometheusRulesAPI retrieves the rules loaded by Prometheus
+	PrometheusRulesAPI() PrometheusRulesAPIInterface
+
+	// PrometheusQuery evaluates PromQL queries
+	PrometheusQuery() PrometheusQueryInterface
+
+	// AlertmanagerSilences returns the AlertmanagerSilences interface
+	AlertmanagerSilences() AlertmanagerSilencesInterface
+
+	// AlertmanagerAlerts retrieves the alerts received by Alertmanager
+	AlertmanagerAlerts() AlertmanagerAlertsInterface
+
 	// PrometheusRules returns the PrometheusRule interface
 	PrometheusRules() PrometheusRuleInterface
 
@@ -34,6 +98,67 @@ type Client interface {
 
 	// AlertRelabelConfigInformer returns the AlertRelabelConfigInformer interface
 	AlertRelabelConfigInformer() AlertRelabelConfigInformerInterface
+
+	// Namespaces returns the Namespace interface
+	Namespaces() NamespaceInterface
+
+	// Auth returns the Auth interface
+	Auth() AuthInterface
+
+	// Impersonate returns a client performing PrometheusRule and AlertRelabelConfig requests as the user
+	Impersonate(user UserInfo) (Client, error)
+}
+
+// NamespaceInterface defines operations for reading Namespaces
+type NamespaceInterface interface {
+	// Get retrieves a Namespace by name
+	Get(ctx context.Context, name string) (*corev1.Namespace, bool, error)
+}
+
+// AuthInterface defines operations for authenticating and authorizing users
+type AuthInterface interface {
+	// Authenticate validates a bearer token with a TokenReview
+	// Returns nil if the token is not authenticated
+	Authenticate(ctx context.Context, token string) (*UserInfo, error)
+
+	// Authorize checks with a SubjectAccessReview whether the user can perform an action on a resource
+	Authorize(ctx context.Context, user UserInfo, attrs ResourceAttributes) (bool, error)
+}
+
+// UserInfo holds the information of an authenticated user
+type UserInfo struct {
+	// Username is the name of the user
+	Username string
+
+	// UID is the unique identifier of the user
+	UID string
+
+	// Groups are the groups the user belongs to
+	Groups []string
+
+	// Extra holds additional information provided by the authenticator
+	Extra map[string][]string
+}
+
+// ResourceAttributes describes an action on a resource to be authorized
+type ResourceAttributes struct {
+	// Namespace of the resource, empty for cluster-wide actions
+	Namespace string
+
+	// Verb is the action, e.g. get, list, create, update or delete
+	Verb string
+
+	// Group is the API group of the resource
+	Group string
+
+	// Resource is the plural name of the resource, e.g. prometheusrules
+	Resource string
+
+	// Subresource of the resource, if any
+	Subresource string
+
+	// Name of the resource, empty for list and create actions
+	Name string
 }
 
 // PrometheusAlertsInterface defines operations for managing PrometheusAlerts
@@ -42,6 +167,43 @@ type PrometheusAlertsInterface interface {
 	GetAlerts(ctx context.Context, req GetAlertsRequest) ([]PrometheusAlert, error)
 }
 
+// PrometheusRulesAPIInterface defines operations for reading the rules loaded by Prometheus
+type PrometheusRulesAPIInterface interface {
+	// GetRuleGroups retrieves the rule groups loaded by Prometheus with the evaluation status of their rules
+	GetRuleGroups(ctx context.Context) ([]LoadedRuleGroup, error)
+}
+
+// PrometheusQueryInterface defines operations for evaluating PromQL queries
+type PrometheusQueryInterface interface {
+	// QueryRange evaluates a query at each step between the start and end of the request
+	QueryRange(ctx context.Context, req QueryRangeRequest) ([]RangeSeries, error)
+}
+
+// AlertmanagerAlertsInterface defines operations for reading alerts through the Alertmanager v2 API
+type AlertmanagerAlertsInterface interface {
+	// GetAlerts retrieves the alerts received by Alertmanager, including silenced and inhibited alerts
+	GetAlerts(ctx context.Context) ([]AlertmanagerAlert, error)
+}
+
+// AlertmanagerSilencesInterface defines operations for managing silences through the Alertmanager v2 API
+type AlertmanagerSilencesInterface interface {
+	// List lists silences with optional matcher and state filtering
+	List(ctx context.Context, req ListSilencesRequest) ([]Silence, error)
+
+	// Get retrieves a silence by ID
+	Get(ctx context.Context, id string) (*Silence, bool, error)
+
+	// Create creates a new silence and returns its ID
+	Create(ctx context.Context, silence Silence) (string, error)
+
+	// Update replaces the silence with the ID of silence and returns its new ID, which differs from the
+	// previous one if Alertmanager expired the silence and created a new one
+	Update(ctx context.Context, silence Silence) (string, error)
+
+	// Expire ends a silence by ID
+	Expire(ctx context.Context, id string) error
+}
+
 // PrometheusRuleInterface defines operations for managing PrometheusRules
 type PrometheusRuleInterface interface {
 	// List lists all PrometheusRules in the cluster
@@ -50,20 +212,34 @@ type PrometheusRuleInterface interface {
 	// Get retrieves a PrometheusRule by namespace and name
 	Get(ctx context.Context, namespace string, name string) (*monitoringv1.PrometheusRule, bool, error)
 
+	// Create creates a new PrometheusRule, returning an already exists error if it exists
+	Create(ctx context.Context, pr monitoringv1.PrometheusRule) error
+
 	// Update updates an existing PrometheusRule
 	Update(ctx context.Context, pr monitoringv1.PrometheusRule) error
 
 	// Delete deletes a PrometheusRule by namespace and name
 	Delete(ctx context.Context, namespace string, name string) error
 
-	// AddRule adds a new rule to the specified PrometheusRule
+	// DeleteWithResourceVersion deletes a PrometheusRule by namespace and name if it still has the given
+	// resourceVersion, returning a conflict error otherwise
+	DeleteWithResourceVersion(ctx context.Context, namespace string, name string, resourceVersion string) error
+
+	// AddRule adds a new rule to the specified PrometheusRule, retrying on conflicts
 	AddRule(ctx context.Context, namespacedName types.NamespacedName, groupName string, rule monitoringv1.Rule) error
 }
 
 // PrometheusRuleInformerInterface defines operations for PrometheusRules informers
 type PrometheusRuleInformerInterface interface {
-	// Run starts the informer and sets up the provided callbacks for add, update, and delete events
+	// Run starts the informer and sets up the provided callbacks for add, update, and delete events.
+	// It blocks until the context is cancelled
 	Run(ctx context.Context, callbacks PrometheusRuleInformerCallback) error
+
+	// HasSynced returns true once the initial list of PrometheusRules has been delivered to the callbacks
+	HasSynced() bool
+
+	// WaitForCacheSync blocks until the informer has synced, returning false if the context is cancelled first
+	WaitForCacheSync(ctx context.Context) bool
 }
 
 // PrometheusRuleInformerCallback holds the callback functions for informer events
@@ -98,8 +274,15 @@ type AlertRelabelConfigInterface interface {
 
 // AlertRelabelConfigInformerInterface defines operations for AlertRelabelConfig informers
 type AlertRelabelConfigInformerInterface interface {
-	// Run starts the informer and sets up the provided callbacks for add, update, and delete events
+	// Run starts the informer and sets up the provided callbacks for add, update, and delete events.
+	// It blocks until the context is cancelled
 	Run(ctx context.Context, callbacks AlertRelabelConfigInformerCallback) error
+
+	// HasSynced returns true once the initial list of AlertRelabelConfigs has been delivered to the callbacks
+	HasSynced() bool
+
+	// WaitForCacheSync blocks until the informer has synced, returning false if the context is cancelled first
+	WaitForCacheSync(ctx context.Context) bool
 }
 
 // AlertRelabelConfigInformerCallback holds the callback functions for informer events
@@ -0,0 +1,83 @@
+package management
+
+import (
+	"context"
+	"time"
+
+	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
+)
+
+// alertsReadinessMaxAge is how long a successful call to the Prometheus alerts
+// API is trusted before readiness checks call it again
+const alertsReadinessMaxAge = time.Minute
+
+func (c *client) CheckReadiness(ctx context.Context) ReadinessStatus {
+	components := []ComponentStatus{
+		informerStatus(ComponentPrometheusRuleInformer, c.k8sClient.PrometheusRuleInformer().HasSynced()),
+		informerStatus(ComponentAlertRelabelConfigInformer, c.k8sClient.AlertRelabelConfigInformer().HasSynced()),
+		c.prometheusAlertsStatus(ctx),
+		c.apiServerStatus(ctx),
+	}
+
+	ready := true
+	for _, component := range components {
+		ready = ready && component.Ready
+	}
+
+	return ReadinessStatus{
+		Ready:      ready,
+		Components: components,
+	}
+}
+
+func informerStatus(name string, synced bool) ComponentStatus {
+	status := ComponentStatus{Name: name, Ready: synced}
+	if !synced {
+		status.Message = "informer has not synced"
+	}
+	return status
+}
+
+func (c *client) prometheusAlertsStatus(ctx context.Context) ComponentStatus {
+	status := ComponentStatus{Name: ComponentPrometheusAlerts}
+
+	lastSuccess := c.getLastAlertsSuccess()
+	if lastSuccess.IsZero() || time.Since(lastSuccess) > alertsReadinessMaxAge {
+		if _, err := c.k8sClient.PrometheusAlerts().GetAlerts(ctx, k8s.GetAlertsRequest{}); err != nil {
+			status.Message = err.Error()
+		} else {
+			c.recordAlertsSuccess()
+			lastSuccess = c.getLastAlertsSuccess()
+		}
+	}
+
+	if !lastSuccess.IsZero() {
+		status.LastSuccess = &lastSuccess
+		status.Ready = time.Since(lastSuccess) <= alertsReadinessMaxAge
+	}
+
+	return status
+}
+
+func (c *client) apiServerStatus(ctx context.Context) ComponentStatus {
+	status := ComponentStatus{Name: ComponentAPIServer, Ready: true}
+	if err := c.k8sClient.TestConnection(ctx); err != nil {
+		status.Ready = false
+		status.Message = err.Error()
+	}
+	return status
+}
+
+func (c *client) recordAlertsSuccess() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.lastAlertsSuccess = time.Now()
+}
+
+func (c *client) getLastAlertsSuccess() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.lastAlertsSuccess
+}
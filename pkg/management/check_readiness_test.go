@@ -0,0 +1,140 @@
+package management_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management/testutils"
+)
+
+var _ = Describe("CheckReadiness", func() {
+	var (
+		ctx            context.Context
+		mockK8s        *testutils.MockClient
+		mockAlerts     *testutils.MockPrometheusAlertsInterface
+		prSynced       bool
+		arcSynced      bool
+		alertsErr      error
+		alertsCalls    int
+		connectionErr  error
+		client         management.Client
+		componentState func(status management.ReadinessStatus, name string) management.ComponentStatus
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		prSynced = true
+		arcSynced = true
+		alertsErr = nil
+		alertsCalls = 0
+		connectionErr = nil
+
+		mockAlerts = &testutils.MockPrometheusAlertsInterface{
+			GetAlertsFunc: func(ctx context.Context, req k8s.GetAlertsRequest) ([]k8s.PrometheusAlert, error) {
+				alertsCalls++
+				return nil, alertsErr
+			},
+		}
+		mockK8s = &testutils.MockClient{
+			TestConnectionFunc: func(ctx context.Context) error {
+				return connectionErr
+			},
+			PrometheusAlertsFunc: func() k8s.PrometheusAlertsInterface {
+				return mockAlerts
+			},
+			PrometheusRuleInformerFunc: func() k8s.PrometheusRuleInformerInterface {
+				return &testutils.MockPrometheusRuleInformerInterface{
+					HasSyncedFunc: func() bool { return prSynced },
+				}
+			},
+			AlertRelabelConfigInformerFunc: func() k8s.AlertRelabelConfigInformerInterface {
+				return &testutils.MockAlertRelabelConfigInformerInterface{
+					HasSyncedFunc: func() bool { return arcSynced },
+				}
+			},
+		}
+
+		client = management.NewWithCustomMapper(ctx, mockK8s, &testutils.MockMapperClient{})
+
+		componentState = func(status management.ReadinessStatus, name string) management.ComponentStatus {
+			for _, component := range status.Components {
+				if component.Name == name {
+					return component
+				}
+			}
+			Fail("component " + name + " not found")
+			return management.ComponentStatus{}
+		}
+	})
+
+	It("should be ready when all components are ready", func() {
+		status := client.CheckReadiness(ctx)
+
+		Expect(status.Ready).To(BeTrue())
+		Expect(status.Components).To(HaveLen(4))
+		for _, component := range status.Components {
+			Expect(component.Ready).To(BeTrue(), component.Name)
+		}
+		Expect(componentState(status, management.ComponentPrometheusAlerts).LastSuccess).ToNot(BeNil())
+	})
+
+	It("should not be ready when the PrometheusRule informer has not synced", func() {
+		prSynced = false
+
+		status := client.CheckReadiness(ctx)
+
+		Expect(status.Ready).To(BeFalse())
+		Expect(componentState(status, management.ComponentPrometheusRuleInformer).Ready).To(BeFalse())
+		Expect(componentState(status, management.ComponentAlertRelabelConfigInformer).Ready).To(BeTrue())
+	})
+
+	It("should not be ready when the AlertRelabelConfig informer has not synced", func() {
+		arcSynced = false
+
+		status := client.CheckReadiness(ctx)
+
+		Expect(status.Ready).To(BeFalse())
+		Expect(componentState(status, management.ComponentAlertRelabelConfigInformer).Ready).To(BeFalse())
+	})
+
+	It("should not be ready when the API server is unreachable", func() {
+		connectionErr = errors.New("connection refused")
+
+		status := client.CheckReadiness(ctx)
+
+		Expect(status.Ready).To(BeFalse())
+		apiServer := componentState(status, management.ComponentAPIServer)
+		Expect(apiServer.Ready).To(BeFalse())
+		Expect(apiServer.Message).To(ContainSubstring("connection refused"))
+	})
+
+	It("should not be ready when Prometheus alerts have never been retrieved", func() {
+		alertsErr = errors.New("route not found")
+
+		status := client.CheckReadiness(ctx)
+
+		Expect(status.Ready).To(BeFalse())
+		alerts := componentState(status, management.ComponentPrometheusAlerts)
+		Expect(alerts.Ready).To(BeFalse())
+		Expect(alerts.Message).To(ContainSubstring("route not found"))
+		Expect(alerts.LastSuccess).To(BeNil())
+	})
+
+	It("should reuse a recent successful GetAlerts call", func() {
+		_, err := client.GetAlerts(ctx, k8s.GetAlertsRequest{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(alertsCalls).To(Equal(1))
+
+		alertsErr = errors.New("route not found")
+		status := client.CheckReadiness(ctx)
+
+		Expect(alertsCalls).To(Equal(1))
+		Expect(status.Ready).To(BeTrue())
+		Expect(componentState(status, management.ComponentPrometheusAlerts).LastSuccess).ToNot(BeNil())
+	})
+})
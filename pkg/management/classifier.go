@@ -0,0 +1,160 @@
+package management
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
+)
+
+// namespaceLabelsTTL is how long the labels of a namespace are cached for
+// classifying PrometheusRules by NamespaceSelector
+const namespaceLabelsTTL = time.Minute
+
+// DefaultClassificationOptions returns the classification used unless the client
+// is created WithClassification or WithPlatformClassifier: PrometheusRules in
+// namespaces starting with openshift- or labeled openshift.io/cluster-monitoring=true
+// are platform-managed
+func DefaultClassificationOptions() ClassificationOptions {
+	return ClassificationOptions{
+		NamespacePrefixes: []string{"openshift-"},
+		NamespaceSelector: labels.SelectorFromSet(labels.Set{"openshift.io/cluster-monitoring": "true"}),
+	}
+}
+
+type platformClassifier struct {
+	namespaces k8s.NamespaceInterface
+	opts       ClassificationOptions
+
+	mu              sync.Mutex
+	namespaceLabels map[string]cachedNamespaceLabels
+}
+
+type cachedNamespaceLabels struct {
+	labels    labels.Set
+	expiresAt time.Time
+}
+
+// NewPlatformClassifier returns a PlatformClassifier implementing opts. The labels
+// of namespaces are read from namespaces if opts has a NamespaceSelector
+func NewPlatformClassifier(namespaces k8s.NamespaceInterface, opts ClassificationOptions) PlatformClassifier {
+	return &platformClassifier{
+		namespaces:      namespaces,
+		opts:            opts,
+		namespaceLabels: make(map[string]cachedNamespaceLabels),
+	}
+}
+
+func (pc *platformClassifier) IsPlatform(ctx context.Context, pr metav1.Object) (bool, error) {
+	if matchesPrometheusRuleList(pc.opts.DenyList, pr) {
+		return false, nil
+	}
+
+	if matchesPrometheusRuleList(pc.opts.AllowList, pr) {
+		return true, nil
+	}
+
+	for _, prefix := range pc.opts.NamespacePrefixes {
+		if strings.HasPrefix(pr.GetNamespace(), prefix) {
+			return true, nil
+		}
+	}
+
+	if pc.opts.PrometheusRuleSelector != nil && pc.opts.PrometheusRuleSelector.Matches(labels.Set(pr.GetLabels())) {
+		return true, nil
+	}
+
+	for _, owner := range pr.GetOwnerReferences() {
+		gv, err := schema.ParseGroupVersion(owner.APIVersion)
+		if err != nil {
+			continue
+		}
+		if slices.Contains(pc.opts.OwnerKinds, schema.GroupKind{Group: gv.Group, Kind: owner.Kind}) {
+			return true, nil
+		}
+	}
+
+	if pc.opts.NamespaceSelector != nil {
+		nsLabels, err := pc.getNamespaceLabels(ctx, pr.GetNamespace())
+		if err != nil {
+			return false, err
+		}
+		if pc.opts.NamespaceSelector.Matches(nsLabels) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (pc *platformClassifier) getNamespaceLabels(ctx context.Context, namespace string) (labels.Set, error) {
+	pc.mu.Lock()
+	cached, ok := pc.namespaceLabels[namespace]
+	pc.mu.Unlock()
+
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.labels, nil
+	}
+
+	ns, found, err := pc.namespaces.Get(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var nsLabels labels.Set
+	if found {
+		nsLabels = ns.Labels
+	}
+
+	pc.mu.Lock()
+	pc.namespaceLabels[namespace] = cachedNamespaceLabels{labels: nsLabels, expiresAt: time.Now().Add(namespaceLabelsTTL)}
+	pc.mu.Unlock()
+
+	return nsLabels, nil
+}
+
+func matchesPrometheusRuleList(list []types.NamespacedName, pr metav1.Object) bool {
+	for _, entry := range list {
+		if entry.Namespace == pr.GetNamespace() && (entry.Name == "" || entry.Name == pr.GetName()) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetPrometheusRuleSource returns the source of the alert rules of the PrometheusRule
+func (c *client) GetPrometheusRuleSource(ctx context.Context, prId types.NamespacedName) (string, error) {
+	pr, found, err := c.k8sClient.PrometheusRules().Get(ctx, prId.Namespace, prId.Name)
+	if err != nil {
+		return "", err
+	}
+
+	// PrometheusRules that do not exist yet are classified by their namespace and name
+	var obj metav1.Object = &metav1.ObjectMeta{Namespace: prId.Namespace, Name: prId.Name}
+	if found {
+		obj = pr
+	}
+
+	return c.classify(ctx, obj)
+}
+
+func (c *client) classify(ctx context.Context, pr metav1.Object) (string, error) {
+	isPlatform, err := c.classifier.IsPlatform(ctx, pr)
+	if err != nil {
+		return "", fmt.Errorf("failed to classify PrometheusRule %s/%s: %w", pr.GetNamespace(), pr.GetName(), err)
+	}
+
+	if isPlatform {
+		return SourcePlatform, nil
+	}
+	return SourceUserDefined, nil
+}
@@ -0,0 +1,152 @@
+package management_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management/mapper"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management/testutils"
+)
+
+var _ = Describe("PlatformClassifier", func() {
+	var (
+		ctx          context.Context
+		mockNS       *testutils.MockNamespaceInterface
+		isPlatformOf func(opts management.ClassificationOptions, pr metav1.Object) bool
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		mockNS = &testutils.MockNamespaceInterface{}
+		mockNS.SetNamespaces(map[string]*corev1.Namespace{
+			"operator-ns": {ObjectMeta: metav1.ObjectMeta{
+				Name:   "operator-ns",
+				Labels: map[string]string{"openshift.io/cluster-monitoring": "true"},
+			}},
+			"user-ns": {ObjectMeta: metav1.ObjectMeta{Name: "user-ns"}},
+		})
+
+		isPlatformOf = func(opts management.ClassificationOptions, pr metav1.Object) bool {
+			isPlatform, err := management.NewPlatformClassifier(mockNS, opts).IsPlatform(ctx, pr)
+			Expect(err).ToNot(HaveOccurred())
+			return isPlatform
+		}
+	})
+
+	prometheusRule := func(namespace string, name string) *monitoringv1.PrometheusRule {
+		return &monitoringv1.PrometheusRule{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+	}
+
+	It("should classify by the default namespace prefix and label", func() {
+		opts := management.DefaultClassificationOptions()
+
+		Expect(isPlatformOf(opts, prometheusRule("openshift-monitoring", "rules"))).To(BeTrue())
+		Expect(isPlatformOf(opts, prometheusRule("operator-ns", "rules"))).To(BeTrue())
+		Expect(isPlatformOf(opts, prometheusRule("user-ns", "rules"))).To(BeFalse())
+		Expect(isPlatformOf(opts, prometheusRule("missing-ns", "rules"))).To(BeFalse())
+	})
+
+	It("should classify by PrometheusRule labels", func() {
+		opts := management.ClassificationOptions{
+			PrometheusRuleSelector: labels.SelectorFromSet(labels.Set{"app.kubernetes.io/part-of": "platform"}),
+		}
+
+		pr := prometheusRule("user-ns", "rules")
+		Expect(isPlatformOf(opts, pr)).To(BeFalse())
+
+		pr.Labels = map[string]string{"app.kubernetes.io/part-of": "platform"}
+		Expect(isPlatformOf(opts, pr)).To(BeTrue())
+	})
+
+	It("should classify by owner kind", func() {
+		opts := management.ClassificationOptions{
+			OwnerKinds: []schema.GroupKind{{Group: "operator.example.com", Kind: "Monitoring"}},
+		}
+
+		pr := prometheusRule("user-ns", "rules")
+		pr.OwnerReferences = []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "Deployment", Name: "app"}}
+		Expect(isPlatformOf(opts, pr)).To(BeFalse())
+
+		pr.OwnerReferences = append(pr.OwnerReferences, metav1.OwnerReference{APIVersion: "operator.example.com/v1", Kind: "Monitoring", Name: "cluster"})
+		Expect(isPlatformOf(opts, pr)).To(BeTrue())
+	})
+
+	It("should give the deny list precedence over the allow list and other options", func() {
+		opts := management.ClassificationOptions{
+			NamespacePrefixes: []string{"openshift-"},
+			AllowList:         []types.NamespacedName{{Namespace: "user-ns"}},
+			DenyList: []types.NamespacedName{
+				{Namespace: "user-ns", Name: "team-rules"},
+				{Namespace: "openshift-user-workload", Name: "rules"},
+			},
+		}
+
+		Expect(isPlatformOf(opts, prometheusRule("user-ns", "rules"))).To(BeTrue())
+		Expect(isPlatformOf(opts, prometheusRule("user-ns", "team-rules"))).To(BeFalse())
+		Expect(isPlatformOf(opts, prometheusRule("openshift-user-workload", "rules"))).To(BeFalse())
+	})
+
+	It("should return an error if the namespace cannot be read", func() {
+		mockNS.GetFunc = func(ctx context.Context, name string) (*corev1.Namespace, bool, error) {
+			return nil, false, errors.New("connection refused")
+		}
+
+		_, err := management.NewPlatformClassifier(mockNS, management.DefaultClassificationOptions()).
+			IsPlatform(ctx, prometheusRule("user-ns", "rules"))
+		Expect(err).To(MatchError(ContainSubstring("connection refused")))
+	})
+
+	It("should expose the source of rules and use a custom classifier", func() {
+		mockPR := &testutils.MockPrometheusRuleInterface{}
+		mockPR.SetPrometheusRules(map[string]*monitoringv1.PrometheusRule{
+			"user-ns/rules": {
+				ObjectMeta: metav1.ObjectMeta{Namespace: "user-ns", Name: "rules"},
+				Spec: monitoringv1.PrometheusRuleSpec{
+					Groups: []monitoringv1.RuleGroup{{Name: "g1", Rules: []monitoringv1.Rule{{Alert: "OperatorAlert"}}}},
+				},
+			},
+		})
+		mockK8s := &testutils.MockClient{
+			PrometheusRulesFunc: func() k8s.PrometheusRuleInterface {
+				return mockPR
+			},
+			NamespacesFunc: func() k8s.NamespaceInterface {
+				return mockNS
+			},
+		}
+
+		m := mapper.New(mockK8s)
+		m.AddPrometheusRule(mockPR.PrometheusRules["user-ns/rules"])
+
+		client := management.NewWithCustomMapper(ctx, mockK8s, m,
+			management.WithClassification(management.ClassificationOptions{
+				AllowList: []types.NamespacedName{{Namespace: "user-ns", Name: "rules"}},
+			}))
+
+		rules, err := client.ListRules(ctx, management.PrometheusRuleOptions{}, management.AlertRuleOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rules).To(HaveLen(1))
+		Expect(rules[0].Source).To(Equal(management.SourcePlatform))
+
+		_, err = client.CreateUserDefinedAlertRule(ctx, monitoringv1.Rule{Alert: "NewAlert", Expr: intstr.FromString("up == 0")}, management.PrometheusRuleOptions{
+			Namespace: "user-ns",
+			Name:      "rules",
+		})
+		var notAllowedErr *management.NotAllowedError
+		Expect(errors.As(err, &notAllowedErr)).To(BeTrue())
+	})
+})
@@ -0,0 +1,63 @@
+package management
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/machadovilaca/alerts-ui-management/pkg/management/mapper"
+)
+
+func (c *client) CopyAlertRule(ctx context.Context, alertRuleId string, target PrometheusRuleOptions) (string, error) {
+	if target.Name == "" || target.Namespace == "" {
+		return "", &InvalidArgumentError{Message: "PrometheusRule Name and Namespace must be specified"}
+	}
+
+	if target.GroupName == "" {
+		target.GroupName = DefaultGroupName
+	}
+
+	targetId := types.NamespacedName{Namespace: target.Namespace, Name: target.Name}
+	if err := c.checkCopyTarget(ctx, targetId); err != nil {
+		return "", err
+	}
+
+	// The copy has the labels of the rule with AlertRelabelConfigs applied, so
+	// that copies of platform rules keep the labels they are shown with
+	rule, _, _, err := c.getRuleById(ctx, alertRuleId, false)
+	if err != nil {
+		return "", err
+	}
+
+	newRuleId, err := mapper.NewAlertingRuleId(target.Namespace)
+	if err != nil {
+		return "", err
+	}
+	copied := withAlertRuleId(rule.Rule, newRuleId)
+
+	k8sClient, err := c.k8sClientFor(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	err = k8sClient.PrometheusRules().AddRule(ctx, targetId, target.GroupName, copied)
+	if err != nil {
+		return "", err
+	}
+
+	return string(c.mapper.GetAlertingRuleId(&copied)), nil
+}
+
+// checkCopyTarget returns a NotAllowedError if rules cannot be added to the
+// target PrometheusRule of a move or copy
+func (c *client) checkCopyTarget(ctx context.Context, targetId types.NamespacedName) error {
+	source, err := c.GetPrometheusRuleSource(ctx, targetId)
+	if err != nil {
+		return err
+	}
+
+	if source == SourcePlatform {
+		return &NotAllowedError{Message: "cannot add alert rule to a platform-managed PrometheusRule"}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package management_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management/mapper"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management/testutils"
+)
+
+var _ = Describe("CopyAlertRule", func() {
+	var (
+		ctx        context.Context
+		mockPR     *testutils.MockPrometheusRuleInterface
+		ruleMapper mapper.Client
+		client     management.Client
+		platformId mapper.PrometheusAlertRuleId
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		mockPR = &testutils.MockPrometheusRuleInterface{}
+		mockK8s := &testutils.MockClient{
+			PrometheusRulesFunc: func() k8s.PrometheusRuleInterface {
+				return mockPR
+			},
+		}
+		ruleMapper = mapper.New(mockK8s)
+		client = management.NewWithCustomMapper(ctx, mockK8s, ruleMapper)
+
+		platformRule := monitoringv1.Rule{
+			Alert:  "PlatformAlert",
+			Expr:   intstr.FromString("up == 0"),
+			Labels: map[string]string{"severity": "warning"},
+		}
+		platformId = ruleMapper.GetAlertingRuleId(&platformRule)
+
+		pr := &monitoringv1.PrometheusRule{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-monitoring", Name: "platform-rules"},
+			Spec: monitoringv1.PrometheusRuleSpec{
+				Groups: []monitoringv1.RuleGroup{{Name: "platform", Rules: []monitoringv1.Rule{platformRule}}},
+			},
+		}
+		ruleMapper.AddPrometheusRule(pr)
+		mockPR.SetPrometheusRules(map[string]*monitoringv1.PrometheusRule{"openshift-monitoring/platform-rules": pr})
+	})
+
+	It("should copy a platform rule to a user-defined PrometheusRule with a new ID", func() {
+		newRuleId, err := client.CopyAlertRule(ctx, string(platformId), management.PrometheusRuleOptions{Namespace: "team-a", Name: "rules"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(newRuleId).NotTo(Equal(string(platformId)))
+
+		target, found, _ := mockPR.Get(ctx, "team-a", "rules")
+		Expect(found).To(BeTrue())
+		copied := target.Spec.Groups[0].Rules[0]
+		Expect(copied.Alert).To(Equal("PlatformAlert"))
+		Expect(copied.Labels).To(Equal(map[string]string{"severity": "warning"}))
+		Expect(copied.Annotations).To(HaveKeyWithValue(mapper.AlertRuleIdAnnotation, newRuleId))
+
+		source, _, _ := mockPR.Get(ctx, "openshift-monitoring", "platform-rules")
+		Expect(source.Spec.Groups[0].Rules).To(HaveLen(1))
+	})
+
+	It("should keep the ID of the original rule unambiguous", func() {
+		_, err := client.CopyAlertRule(ctx, string(platformId), management.PrometheusRuleOptions{Namespace: "team-a", Name: "rules"})
+		Expect(err).NotTo(HaveOccurred())
+
+		target, _, _ := mockPR.Get(ctx, "team-a", "rules")
+		ruleMapper.AddPrometheusRule(target)
+
+		prId, err := client.FindPrometheusRuleByAlertRuleId(ctx, string(platformId))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(prId.Namespace).To(Equal("openshift-monitoring"))
+	})
+
+	It("should return NotAllowedError for platform-managed targets", func() {
+		_, err := client.CopyAlertRule(ctx, string(platformId), management.PrometheusRuleOptions{Namespace: "openshift-monitoring", Name: "other-rules"})
+
+		var notAllowed *management.NotAllowedError
+		Expect(errors.As(err, &notAllowed)).To(BeTrue())
+	})
+})
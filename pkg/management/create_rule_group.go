@@ -0,0 +1,72 @@
+package management
+
+import (
+	"context"
+	"fmt"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+)
+
+func (c *client) CreateRuleGroup(ctx context.Context, prId types.NamespacedName, group monitoringv1.RuleGroup) (RuleGroup, error) {
+	if prId.Name == "" || prId.Namespace == "" {
+		return RuleGroup{}, &InvalidArgumentError{Message: "PrometheusRule Name and Namespace must be specified"}
+	}
+
+	if err := validateRuleGroup(group); err != nil {
+		return RuleGroup{}, err
+	}
+
+	source, err := c.GetPrometheusRuleSource(ctx, prId)
+	if err != nil {
+		return RuleGroup{}, err
+	}
+
+	if source == SourcePlatform {
+		return RuleGroup{}, &NotAllowedError{Message: "cannot add rule group to a platform-managed PrometheusRule"}
+	}
+
+	k8sClient, err := c.k8sClientFor(ctx)
+	if err != nil {
+		return RuleGroup{}, err
+	}
+
+	pr := &monitoringv1.PrometheusRule{ObjectMeta: metav1.ObjectMeta{Namespace: prId.Namespace, Name: prId.Name}}
+
+	// The PrometheusRule is created if it does not exist, and re-read if it is
+	// changed or created concurrently
+	err = retry.OnError(retry.DefaultRetry, func(err error) bool {
+		return apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err)
+	}, func() error {
+		existing, found, err := k8sClient.PrometheusRules().Get(ctx, prId.Namespace, prId.Name)
+		if err != nil {
+			return err
+		}
+
+		if !found {
+			pr.Spec.Groups = []monitoringv1.RuleGroup{group}
+			return k8sClient.PrometheusRules().Create(ctx, *pr)
+		}
+
+		if ruleGroupIndex(existing, group.Name) >= 0 {
+			return &ConflictError{Message: fmt.Sprintf("rule group %s already exists in PrometheusRule %s/%s", group.Name, prId.Namespace, prId.Name)}
+		}
+
+		existing.Spec.Groups = append(existing.Spec.Groups, group)
+		pr = existing
+
+		err = k8sClient.PrometheusRules().Update(ctx, *existing)
+		if err != nil {
+			return fmt.Errorf("failed to update PrometheusRule %s/%s: %w", prId.Namespace, prId.Name, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return RuleGroup{}, err
+	}
+
+	return ruleGroupFrom(pr, group, source), nil
+}
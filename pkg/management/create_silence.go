@@ -0,0 +1,109 @@
+package management
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
+)
+
+func (c *client) CreateSilence(ctx context.Context, silence k8s.Silence) (string, error) {
+	silence, err := prepareSilence(ctx, silence)
+	if err != nil {
+		return "", err
+	}
+
+	silenceId, err := c.k8sClient.AlertmanagerSilences().Create(ctx, silence)
+	if err != nil {
+		return "", silenceError(err)
+	}
+	return silenceId, nil
+}
+
+// prepareSilence validates a silence before it is sent to Alertmanager,
+// starting it now if unset. The author is always the user in ctx, if any, so
+// that callers cannot create silences in the name of other users
+func prepareSilence(ctx context.Context, silence k8s.Silence) (k8s.Silence, error) {
+	now := time.Now()
+
+	if silence.StartsAt.IsZero() {
+		silence.StartsAt = now
+	}
+
+	if user, ok := k8s.UserFrom(ctx); ok {
+		silence.CreatedBy = user.Username
+	}
+
+	if err := validateSilence(silence, now); err != nil {
+		return k8s.Silence{}, err
+	}
+	return silence, nil
+}
+
+// validateSilence returns an InvalidArgumentError for the silences Alertmanager
+// would reject, and for silences that would end before they are created
+func validateSilence(silence k8s.Silence, now time.Time) error {
+	if len(silence.Matchers) == 0 {
+		return &InvalidArgumentError{Message: "silence must have at least one matcher"}
+	}
+
+	matchesAll := true
+	for _, matcher := range silence.Matchers {
+		if matcher.Name == "" {
+			return &InvalidArgumentError{Message: "silence matcher name is required"}
+		}
+
+		matchesEmpty := matcher.Value == ""
+		if matcher.IsRegex {
+			re, err := regexp.Compile("^(?:" + matcher.Value + ")$")
+			if err != nil {
+				return &InvalidArgumentError{Message: fmt.Sprintf("invalid regex of silence matcher %s: %v", matcher.Name, err)}
+			}
+			matchesEmpty = re.MatchString("")
+		}
+		if matcher.IsEqual != nil && !*matcher.IsEqual {
+			matchesEmpty = !matchesEmpty
+		}
+
+		if !matchesEmpty {
+			matchesAll = false
+		}
+	}
+	if matchesAll {
+		return &InvalidArgumentError{Message: "silence must have at least one matcher not matching the empty string"}
+	}
+
+	if silence.EndsAt.IsZero() {
+		return &InvalidArgumentError{Message: "silence endsAt is required"}
+	}
+	if !silence.EndsAt.After(silence.StartsAt) {
+		return &InvalidArgumentError{Message: "silence endsAt must be after startsAt"}
+	}
+	if !silence.EndsAt.After(now) {
+		return &InvalidArgumentError{Message: "silence endsAt must be in the future"}
+	}
+
+	if silence.CreatedBy == "" {
+		return &InvalidArgumentError{Message: "silence createdBy is required"}
+	}
+	if strings.TrimSpace(silence.Comment) == "" {
+		return &InvalidArgumentError{Message: "silence comment is required"}
+	}
+
+	return nil
+}
+
+// silenceError returns an InvalidArgumentError for the silences Alertmanager
+// rejects, so that they are reported to the caller
+func silenceError(err error) error {
+	var endpointErr *k8s.EndpointError
+	if errors.As(err, &endpointErr) && endpointErr.StatusCode == http.StatusBadRequest {
+		return &InvalidArgumentError{Message: fmt.Sprintf("invalid silence: %s", strings.TrimSpace(endpointErr.Body))}
+	}
+	return err
+}
@@ -0,0 +1,192 @@
+package management_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management/testutils"
+)
+
+var _ = Describe("Silences", func() {
+	var (
+		ctx          context.Context
+		mockSilences *testutils.MockAlertmanagerSilencesInterface
+		client       management.Client
+		silence      k8s.Silence
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		mockSilences = &testutils.MockAlertmanagerSilencesInterface{}
+		mockK8s := &testutils.MockClient{
+			AlertmanagerSilencesFunc: func() k8s.AlertmanagerSilencesInterface {
+				return mockSilences
+			},
+		}
+		client = management.NewWithCustomMapper(ctx, mockK8s, &testutils.MockMapperClient{})
+
+		silence = k8s.Silence{
+			Matchers:  []k8s.SilenceMatcher{{Name: "alertname", Value: "Foo"}},
+			EndsAt:    time.Now().Add(time.Hour),
+			CreatedBy: "admin",
+			Comment:   "maintenance",
+		}
+	})
+
+	Context("when creating a silence", func() {
+		It("should start it now and set its author to the user", func() {
+			silence.CreatedBy = ""
+			userCtx := k8s.WithUser(ctx, k8s.UserInfo{Username: "alice"})
+
+			id, err := client.CreateSilence(userCtx, silence)
+			Expect(err).NotTo(HaveOccurred())
+
+			created, err := client.GetSilence(ctx, id)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(created.CreatedBy).To(Equal("alice"))
+			Expect(created.StartsAt).To(BeTemporally("~", time.Now(), time.Minute))
+			Expect(created.Status.State).To(Equal(k8s.SilenceStateActive))
+		})
+
+		It("should replace the author set by the caller with the user", func() {
+			userCtx := k8s.WithUser(ctx, k8s.UserInfo{Username: "alice"})
+
+			id, err := client.CreateSilence(userCtx, silence)
+			Expect(err).NotTo(HaveOccurred())
+
+			created, err := client.GetSilence(ctx, id)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(created.CreatedBy).To(Equal("alice"))
+		})
+
+		DescribeTable("should reject invalid silences",
+			func(mutate func(*k8s.Silence), message string) {
+				mutate(&silence)
+
+				_, err := client.CreateSilence(ctx, silence)
+
+				var invalidErr *management.InvalidArgumentError
+				Expect(errors.As(err, &invalidErr)).To(BeTrue())
+				Expect(invalidErr.Message).To(ContainSubstring(message))
+				Expect(mockSilences.Silences).To(BeEmpty())
+			},
+			Entry("without matchers", func(s *k8s.Silence) { s.Matchers = nil }, "at least one matcher"),
+			Entry("with an invalid regex", func(s *k8s.Silence) {
+				s.Matchers = []k8s.SilenceMatcher{{Name: "alertname", Value: "(", IsRegex: true}}
+			}, "invalid regex"),
+			Entry("matching every alert", func(s *k8s.Silence) {
+				s.Matchers = []k8s.SilenceMatcher{{Name: "alertname", Value: ".*", IsRegex: true}}
+			}, "not matching the empty string"),
+			Entry("without end", func(s *k8s.Silence) { s.EndsAt = time.Time{} }, "endsAt is required"),
+			Entry("ending before it starts", func(s *k8s.Silence) { s.StartsAt = s.EndsAt.Add(time.Minute) }, "after startsAt"),
+			Entry("ending in the past", func(s *k8s.Silence) {
+				s.StartsAt = time.Now().Add(-2 * time.Hour)
+				s.EndsAt = time.Now().Add(-time.Hour)
+			}, "in the future"),
+			Entry("without comment", func(s *k8s.Silence) { s.Comment = " " }, "comment is required"),
+		)
+
+		It("should accept negative matchers of empty values", func() {
+			isEqual := false
+			silence.Matchers = []k8s.SilenceMatcher{{Name: "namespace", Value: "", IsEqual: &isEqual}}
+
+			_, err := client.CreateSilence(ctx, silence)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should report the silences rejected by Alertmanager as invalid arguments", func() {
+			mockSilences.CreateFunc = func(ctx context.Context, silence k8s.Silence) (string, error) {
+				return "", &k8s.EndpointError{StatusCode: http.StatusBadRequest, Body: "bad matcher\n"}
+			}
+
+			_, err := client.CreateSilence(ctx, silence)
+
+			var invalidErr *management.InvalidArgumentError
+			Expect(errors.As(err, &invalidErr)).To(BeTrue())
+			Expect(invalidErr.Message).To(Equal("invalid silence: bad matcher"))
+		})
+	})
+
+	Context("when updating a silence", func() {
+		It("should keep its start and id", func() {
+			id, err := client.CreateSilence(ctx, silence)
+			Expect(err).NotTo(HaveOccurred())
+			created, _ := client.GetSilence(ctx, id)
+
+			silence.Comment = "extended"
+			silence.EndsAt = silence.EndsAt.Add(time.Hour)
+			newId, err := client.UpdateSilence(ctx, id, silence)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(newId).To(Equal(id))
+
+			updated, _ := client.GetSilence(ctx, id)
+			Expect(updated.Comment).To(Equal("extended"))
+			Expect(updated.StartsAt).To(Equal(created.StartsAt))
+		})
+
+		It("should set its author to the user", func() {
+			id, err := client.CreateSilence(ctx, silence)
+			Expect(err).NotTo(HaveOccurred())
+
+			userCtx := k8s.WithUser(ctx, k8s.UserInfo{Username: "alice"})
+			newId, err := client.UpdateSilence(userCtx, id, silence)
+			Expect(err).NotTo(HaveOccurred())
+
+			updated, _ := client.GetSilence(ctx, newId)
+			Expect(updated.CreatedBy).To(Equal("alice"))
+		})
+
+		It("should not update expired silences", func() {
+			id, err := client.CreateSilence(ctx, silence)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(client.ExpireSilence(ctx, id)).To(Succeed())
+
+			_, err = client.UpdateSilence(ctx, id, silence)
+
+			var notAllowedErr *management.NotAllowedError
+			Expect(errors.As(err, &notAllowedErr)).To(BeTrue())
+		})
+
+		It("should return NotFoundError for missing silences", func() {
+			_, err := client.UpdateSilence(ctx, "missing", silence)
+
+			var notFoundErr *management.NotFoundError
+			Expect(errors.As(err, &notFoundErr)).To(BeTrue())
+			Expect(notFoundErr.Resource).To(Equal("Silence"))
+		})
+	})
+
+	Context("when expiring a silence", func() {
+		It("should expire it once and list it as expired", func() {
+			id, err := client.CreateSilence(ctx, silence)
+			Expect(err).NotTo(HaveOccurred())
+
+			expireCalls := 0
+			mockSilences.ExpireFunc = func(ctx context.Context, id string) error {
+				expireCalls++
+				mockSilences.Silences[id].Status.State = k8s.SilenceStateExpired
+				return nil
+			}
+
+			Expect(client.ExpireSilence(ctx, id)).To(Succeed())
+			Expect(client.ExpireSilence(ctx, id)).To(Succeed())
+			Expect(expireCalls).To(Equal(1))
+
+			expired, err := client.ListSilences(ctx, k8s.ListSilencesRequest{State: k8s.SilenceStateExpired})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(expired).To(HaveLen(1))
+
+			active, err := client.ListSilences(ctx, k8s.ListSilencesRequest{State: k8s.SilenceStateActive})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(active).To(BeEmpty())
+		})
+	})
+})
@@ -2,10 +2,11 @@ package management
 
 import (
 	"context"
-	"errors"
 
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/machadovilaca/alerts-ui-management/pkg/management/mapper"
 )
 
 const (
@@ -14,7 +15,11 @@ const (
 
 func (c *client) CreateUserDefinedAlertRule(ctx context.Context, alertRule monitoringv1.Rule, prOptions PrometheusRuleOptions) (string, error) {
 	if prOptions.Name == "" || prOptions.Namespace == "" {
-		return "", errors.New("PrometheusRule Name and Namespace must be specified")
+		return "", &InvalidArgumentError{Message: "PrometheusRule Name and Namespace must be specified"}
+	}
+
+	if err := validateRule(alertRule); err != nil {
+		return "", err
 	}
 
 	nn := types.NamespacedName{
@@ -22,25 +27,72 @@ func (c *client) CreateUserDefinedAlertRule(ctx context.Context, alertRule monit
 		Namespace: prOptions.Namespace,
 	}
 
-	if IsPlatformAlertRule(nn) {
-		return "", errors.New("cannot add user-defined alert rule to a platform-managed PrometheusRule")
+	source, err := c.GetPrometheusRuleSource(ctx, nn)
+	if err != nil {
+		return "", err
+	}
+
+	if source == SourcePlatform {
+		return "", &NotAllowedError{Message: "cannot add user-defined alert rule to a platform-managed PrometheusRule"}
+	}
+
+	// Rules are only compared with the rules of the target PrometheusRule, since
+	// the user may not be allowed to see the rules of other namespaces, and teams
+	// may define the same rule in their own namespaces
+	contentId := c.mapper.GetContentAlertingRuleId(&alertRule)
+	for _, cached := range c.mapper.ListAlertRules(mapper.PrometheusRuleId{Namespace: nn.Namespace, Name: nn.Name}) {
+		if c.mapper.GetContentAlertingRuleId(&cached.Rule) == contentId {
+			return "", &ConflictError{Message: "alert rule with exact config already exists in the PrometheusRule"}
+		}
 	}
 
-	// Check if rule with the same ID already exists
-	ruleId := c.mapper.GetAlertingRuleId(&alertRule)
-	_, err := c.mapper.FindAlertRuleById(ruleId)
-	if err == nil {
-		return "", errors.New("alert rule with exact config already exists")
+	ruleId, err := mapper.NewAlertingRuleId(prOptions.Namespace)
+	if err != nil {
+		return "", err
 	}
+	alertRule = withAlertRuleId(alertRule, ruleId)
 
 	if prOptions.GroupName == "" {
 		prOptions.GroupName = DefaultGroupName
 	}
 
-	err = c.k8sClient.PrometheusRules().AddRule(ctx, nn, prOptions.GroupName, alertRule)
+	k8sClient, err := c.k8sClientFor(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	err = k8sClient.PrometheusRules().AddRule(ctx, nn, prOptions.GroupName, alertRule)
 	if err != nil {
 		return "", err
 	}
 
 	return string(c.mapper.GetAlertingRuleId(&alertRule)), nil
 }
+
+// withAlertRuleId returns a copy of the alert rule with its stable ID stored in
+// its annotations, and without the alert_rule_id label added to API responses.
+// Recording rules cannot have annotations and keep their hash-based ID
+func withAlertRuleId(alertRule monitoringv1.Rule, alertRuleId mapper.PrometheusAlertRuleId) monitoringv1.Rule {
+	if alertRule.Labels != nil {
+		labels := make(map[string]string, len(alertRule.Labels))
+		for key, value := range alertRule.Labels {
+			if key != alertRuleIdLabel {
+				labels[key] = value
+			}
+		}
+		alertRule.Labels = labels
+	}
+
+	if alertRule.Record != "" {
+		return alertRule
+	}
+
+	annotations := make(map[string]string, len(alertRule.Annotations)+1)
+	for key, value := range alertRule.Annotations {
+		annotations[key] = value
+	}
+	annotations[mapper.AlertRuleIdAnnotation] = string(alertRuleId)
+	alertRule.Annotations = annotations
+
+	return alertRule
+}
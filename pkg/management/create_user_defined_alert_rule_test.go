@@ -7,6 +7,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
@@ -141,6 +142,9 @@ var _ = Describe("CreateUserDefinedAlertRule", func() {
 			By("verifying the error")
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("PrometheusRule Name and Namespace must be specified"))
+
+			var invalidArgumentErr *management.InvalidArgumentError
+			Expect(errors.As(err, &invalidArgumentErr)).To(BeTrue())
 		})
 
 		It("should return error when name is missing", func() {
@@ -183,38 +187,6 @@ var _ = Describe("CreateUserDefinedAlertRule", func() {
 			Expect(err.Error()).To(ContainSubstring("cannot add user-defined alert rule to a platform-managed PrometheusRule"))
 		})
 
-		It("should return error when rule with same config already exists", func() {
-			By("setting up test data")
-			alertRule := monitoringv1.Rule{
-				Alert: "DuplicateAlert",
-				Expr:  intstr.FromString("up == 0"),
-			}
-
-			prOptions := management.PrometheusRuleOptions{
-				Name:      "test-rule",
-				Namespace: "test-namespace",
-			}
-
-			ruleId := "duplicate-rule-id"
-			mockMapper.GetAlertingRuleIdFunc = func(alertRule *monitoringv1.Rule) mapper.PrometheusAlertRuleId {
-				return mapper.PrometheusAlertRuleId(ruleId)
-			}
-			mockMapper.FindAlertRuleByIdFunc = func(id mapper.PrometheusAlertRuleId) (*mapper.PrometheusRuleId, error) {
-				// Return success, indicating the rule already exists
-				return &mapper.PrometheusRuleId{
-					Namespace: "test-namespace",
-					Name:      "test-rule",
-				}, nil
-			}
-
-			By("attempting to create the duplicate alert rule")
-			_, err := client.CreateUserDefinedAlertRule(ctx, alertRule, prOptions)
-
-			By("verifying the error")
-			Expect(err).To(HaveOccurred())
-			Expect(err.Error()).To(ContainSubstring("alert rule with exact config already exists"))
-		})
-
 		It("should return error when AddRule fails", func() {
 			By("setting up test data")
 			alertRule := monitoringv1.Rule{
@@ -274,7 +246,8 @@ var _ = Describe("CreateUserDefinedAlertRule", func() {
 			mockPR.AddRuleFunc = func(ctx context.Context, nn types.NamespacedName, groupName string, rule monitoringv1.Rule) error {
 				addRuleCalled = true
 				Expect(rule.Labels).To(BeNil())
-				Expect(rule.Annotations).To(BeNil())
+				Expect(rule.Annotations).To(HaveLen(1))
+				Expect(rule.Annotations).To(HaveKey(mapper.AlertRuleIdAnnotation))
 				return nil
 			}
 
@@ -287,6 +260,112 @@ var _ = Describe("CreateUserDefinedAlertRule", func() {
 			Expect(addRuleCalled).To(BeTrue())
 		})
 
+		It("should create a recording rule identified by its hash-based ID", func() {
+			recordingRule := monitoringv1.Rule{
+				Record: "job:up:sum",
+				Expr:   intstr.FromString("sum by (job) (up)"),
+				Labels: map[string]string{"alert_rule_id": "ignored"},
+			}
+
+			mockMapper.FindAlertRuleByIdFunc = func(id mapper.PrometheusAlertRuleId) (*mapper.PrometheusRuleId, error) {
+				return nil, errors.New("not found")
+			}
+			mockPR.AddRuleFunc = func(ctx context.Context, nn types.NamespacedName, groupName string, rule monitoringv1.Rule) error {
+				Expect(rule.Record).To(Equal("job:up:sum"))
+				Expect(rule.Labels).To(BeEmpty())
+				Expect(rule.Annotations).To(BeNil())
+				return nil
+			}
+
+			_, err := client.CreateUserDefinedAlertRule(ctx, recordingRule, management.PrometheusRuleOptions{
+				Name:      "recording-rules",
+				Namespace: "test-namespace",
+			})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should reject recording rules with alert-only fields", func() {
+			recordingRule := monitoringv1.Rule{
+				Record:      "job:up:sum",
+				Expr:        intstr.FromString("sum by (job) (up)"),
+				Annotations: map[string]string{"summary": "not allowed"},
+			}
+
+			_, err := client.CreateUserDefinedAlertRule(ctx, recordingRule, management.PrometheusRuleOptions{
+				Name:      "recording-rules",
+				Namespace: "test-namespace",
+			})
+
+			var invalidArgErr *management.InvalidArgumentError
+			Expect(errors.As(err, &invalidArgErr)).To(BeTrue())
+		})
+
+		It("should reject rules that Prometheus would fail to load with field errors", func() {
+			forDuration := monitoringv1.Duration("5 minutes")
+			alertRule := monitoringv1.Rule{
+				Alert:       "TestAlert",
+				Expr:        intstr.FromString("rate(up[5m]"),
+				For:         &forDuration,
+				Labels:      map[string]string{"__name__": "x", "foo-bar": "y"},
+				Annotations: map[string]string{"summary": "{{ $labels.instance ", "team.name": "z"},
+			}
+			mockPR.AddRuleFunc = func(ctx context.Context, nn types.NamespacedName, groupName string, rule monitoringv1.Rule) error {
+				Fail("invalid rules should not be written")
+				return nil
+			}
+
+			_, err := client.CreateUserDefinedAlertRule(ctx, alertRule, management.PrometheusRuleOptions{
+				Name:      "test-rule",
+				Namespace: "test-namespace",
+			})
+
+			var validationErr *management.ValidationError
+			Expect(errors.As(err, &validationErr)).To(BeTrue())
+			fields := make([]string, 0, len(validationErr.Errors))
+			for _, fieldErr := range validationErr.Errors {
+				fields = append(fields, fieldErr.Field)
+			}
+			Expect(fields).To(Equal([]string{"annotations.summary", "annotations.team.name", "expr", "for", "labels.__name__", "labels.foo-bar"}))
+		})
+
+		It("should reject expressions that do not return an instant vector", func() {
+			for _, expr := range []string{"1", `"text"`, "up[5m]"} {
+				_, err := client.CreateUserDefinedAlertRule(ctx, monitoringv1.Rule{Alert: "TestAlert", Expr: intstr.FromString(expr)}, management.PrometheusRuleOptions{
+					Name:      "test-rule",
+					Namespace: "test-namespace",
+				})
+
+				var validationErr *management.ValidationError
+				Expect(errors.As(err, &validationErr)).To(BeTrue(), expr)
+				Expect(validationErr.Errors).To(ConsistOf(management.FieldError{
+					Field:   "expr",
+					Message: validationErr.Errors[0].Message,
+				}))
+				Expect(validationErr.Errors[0].Message).To(ContainSubstring("must return an instant vector"))
+			}
+		})
+
+		It("should accept annotations using the alert template variables", func() {
+			mockMapper.FindAlertRuleByIdFunc = func(id mapper.PrometheusAlertRuleId) (*mapper.PrometheusRuleId, error) {
+				return nil, errors.New("not found")
+			}
+
+			keepFiringFor := monitoringv1.NonEmptyDuration("10m")
+			_, err := client.CreateUserDefinedAlertRule(ctx, monitoringv1.Rule{
+				Alert:         "TestAlert",
+				Expr:          intstr.FromString("up == 0"),
+				KeepFiringFor: &keepFiringFor,
+				Annotations: map[string]string{
+					"summary":     "{{ $labels.instance }} is down",
+					"description": "value is {{ $value | humanize }}, see {{ $externalURL }}",
+				},
+			}, management.PrometheusRuleOptions{
+				Name:      "test-rule",
+				Namespace: "test-namespace",
+			})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
 		It("should reject PrometheusRules in openshift- prefixed namespaces", func() {
 			By("setting up test data with openshift- namespace prefix")
 			alertRule := monitoringv1.Rule{
@@ -307,4 +386,102 @@ var _ = Describe("CreateUserDefinedAlertRule", func() {
 			Expect(err.Error()).To(ContainSubstring("cannot add user-defined alert rule to a platform-managed PrometheusRule"))
 		})
 	})
+
+	Context("when the context carries an authenticated user", func() {
+		It("should add the rule with a client impersonating the user", func() {
+			By("setting up an impersonating client")
+			user := k8s.UserInfo{Username: "alice", Groups: []string{"team-a"}}
+			ctx = k8s.WithUser(ctx, user)
+
+			mockMapper.FindAlertRuleByIdFunc = func(id mapper.PrometheusAlertRuleId) (*mapper.PrometheusRuleId, error) {
+				return nil, errors.New("not found")
+			}
+
+			impersonatedPR := &testutils.MockPrometheusRuleInterface{}
+			var impersonatedUser k8s.UserInfo
+			mockK8s.ImpersonateFunc = func(user k8s.UserInfo) (k8s.Client, error) {
+				impersonatedUser = user
+				return &testutils.MockClient{
+					PrometheusRulesFunc: func() k8s.PrometheusRuleInterface {
+						return impersonatedPR
+					},
+				}, nil
+			}
+			mockPR.AddRuleFunc = func(ctx context.Context, nn types.NamespacedName, groupName string, rule monitoringv1.Rule) error {
+				Fail("the service client should not be used")
+				return nil
+			}
+
+			By("creating the alert rule")
+			_, err := client.CreateUserDefinedAlertRule(ctx, monitoringv1.Rule{Alert: "TestAlert", Expr: intstr.FromString("up == 0")}, management.PrometheusRuleOptions{
+				Name:      "test-rule",
+				Namespace: "team-a",
+			})
+
+			By("verifying the rule was added as the user")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(impersonatedUser).To(Equal(user))
+			Expect(impersonatedPR.PrometheusRules).To(HaveKey("team-a/test-rule"))
+		})
+
+		It("should return error when the user cannot be impersonated", func() {
+			ctx = k8s.WithUser(ctx, k8s.UserInfo{Username: "alice"})
+
+			mockMapper.FindAlertRuleByIdFunc = func(id mapper.PrometheusAlertRuleId) (*mapper.PrometheusRuleId, error) {
+				return nil, errors.New("not found")
+			}
+			mockK8s.ImpersonateFunc = func(user k8s.UserInfo) (k8s.Client, error) {
+				return nil, errors.New("invalid config")
+			}
+
+			_, err := client.CreateUserDefinedAlertRule(ctx, monitoringv1.Rule{Alert: "TestAlert", Expr: intstr.FromString("up == 0")}, management.PrometheusRuleOptions{
+				Name:      "test-rule",
+				Namespace: "team-a",
+			})
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("failed to impersonate user alice"))
+		})
+	})
+
+	Context("when a rule with the same config exists", func() {
+		var alertRule monitoringv1.Rule
+
+		BeforeEach(func() {
+			realMapper := mapper.New(mockK8s)
+			client = management.NewWithCustomMapper(ctx, mockK8s, realMapper)
+
+			alertRule = monitoringv1.Rule{
+				Alert:  "DuplicateAlert",
+				Expr:   intstr.FromString("up == 0"),
+				Labels: map[string]string{"severity": "warning"},
+			}
+			existing := &monitoringv1.PrometheusRule{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "rules"},
+				Spec: monitoringv1.PrometheusRuleSpec{
+					Groups: []monitoringv1.RuleGroup{{Name: "g1", Rules: []monitoringv1.Rule{alertRule}}},
+				},
+			}
+			realMapper.AddPrometheusRule(existing)
+			mockPR.SetPrometheusRules(map[string]*monitoringv1.PrometheusRule{"team-a/rules": existing})
+		})
+
+		It("should return a ConflictError for the same PrometheusRule", func() {
+			_, err := client.CreateUserDefinedAlertRule(ctx, alertRule, management.PrometheusRuleOptions{Namespace: "team-a", Name: "rules"})
+
+			var conflictErr *management.ConflictError
+			Expect(errors.As(err, &conflictErr)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("alert rule with exact config already exists"))
+		})
+
+		It("should create the rule in another namespace", func() {
+			_, err := client.CreateUserDefinedAlertRule(ctx, alertRule, management.PrometheusRuleOptions{Namespace: "team-b", Name: "rules"})
+			Expect(err).NotTo(HaveOccurred())
+
+			pr, found, err := mockPR.Get(ctx, "team-b", "rules")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(pr.Spec.Groups[0].Rules[0].Alert).To(Equal("DuplicateAlert"))
+		})
+	})
 })
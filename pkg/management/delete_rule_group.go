@@ -0,0 +1,23 @@
+package management
+
+import (
+	"context"
+	"slices"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func (c *client) DeleteRuleGroup(ctx context.Context, prId types.NamespacedName, groupName string) error {
+	// The PrometheusRule is deleted with its last group
+	_, err := c.editUserDefinedPrometheusRule(ctx, prId, func(pr *monitoringv1.PrometheusRule) error {
+		idx := ruleGroupIndex(pr, groupName)
+		if idx < 0 {
+			return ruleGroupNotFound(prId, groupName)
+		}
+
+		pr.Spec.Groups = slices.Delete(pr.Spec.Groups, idx, idx+1)
+		return nil
+	})
+	return err
+}
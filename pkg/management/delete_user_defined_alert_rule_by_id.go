@@ -3,24 +3,44 @@ package management
 import (
 	"context"
 	"fmt"
+	"slices"
 
-	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
 
+	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
 	"github.com/machadovilaca/alerts-ui-management/pkg/management/mapper"
 )
 
 func (c *client) DeleteUserDefinedAlertRuleById(ctx context.Context, alertRuleId string) error {
-	prId, err := c.mapper.FindAlertRuleById(mapper.PrometheusAlertRuleId(alertRuleId))
+	prId, err := c.findPrometheusRuleId(alertRuleId)
 	if err != nil {
-		return &NotFoundError{Resource: "AlertRule", Id: alertRuleId}
+		return err
+	}
+
+	source, err := c.GetPrometheusRuleSource(ctx, types.NamespacedName(*prId))
+	if err != nil {
+		return err
 	}
 
-	if IsPlatformAlertRule(types.NamespacedName(*prId)) {
+	if source == SourcePlatform {
 		return &NotAllowedError{Message: "cannot delete alert rule from a platform-managed PrometheusRule"}
 	}
 
-	pr, found, err := c.k8sClient.PrometheusRules().Get(ctx, prId.Namespace, prId.Name)
+	k8sClient, err := c.k8sClientFor(ctx)
+	if err != nil {
+		return err
+	}
+
+	// The PrometheusRule is re-read on conflicts, so that concurrent edits of
+	// other rules in the same PrometheusRule are preserved
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		return c.deleteRuleFromPrometheusRule(ctx, k8sClient, prId, alertRuleId)
+	})
+}
+
+func (c *client) deleteRuleFromPrometheusRule(ctx context.Context, k8sClient k8s.Client, prId *mapper.PrometheusRuleId, alertRuleId string) error {
+	pr, found, err := k8sClient.PrometheusRules().Get(ctx, prId.Namespace, prId.Name)
 	if err != nil {
 		return err
 	}
@@ -29,57 +49,23 @@ func (c *client) DeleteUserDefinedAlertRuleById(ctx context.Context, alertRuleId
 		return &NotFoundError{Resource: "PrometheusRule", Id: fmt.Sprintf("%s/%s", prId.Namespace, prId.Name)}
 	}
 
-	updated := false
-	var newGroups []monitoringv1.RuleGroup
-
-	for _, group := range pr.Spec.Groups {
-		newRules := c.filterRulesById(group.Rules, alertRuleId, &updated)
-
-		// Only keep groups that still have rules
-		if len(newRules) > 0 {
-			group.Rules = newRules
-			newGroups = append(newGroups, group)
-		} else if len(newRules) != len(group.Rules) {
-			// Group became empty due to rule deletion
-			updated = true
-		}
+	if err := checkResourceVersion(ctx, pr); err != nil {
+		return err
 	}
 
-	if updated {
-		if len(newGroups) == 0 {
-			// No groups left, delete the entire PrometheusRule
-			err = c.k8sClient.PrometheusRules().Delete(ctx, pr.Namespace, pr.Name)
-			if err != nil {
-				return fmt.Errorf("failed to delete PrometheusRule %s/%s: %w", pr.Namespace, pr.Name, err)
-			}
-		} else {
-			// Update PrometheusRule with remaining groups
-			pr.Spec.Groups = newGroups
-			err = c.k8sClient.PrometheusRules().Update(ctx, *pr)
-			if err != nil {
-				return fmt.Errorf("failed to update PrometheusRule %s/%s: %w", pr.Namespace, pr.Name, err)
-			}
-		}
-		return nil
+	// Only the located rule is deleted, rules with the same ID elsewhere are kept
+	groupIdx, ruleIdx, found := c.findRule(pr, alertRuleId)
+	if !found {
+		return &NotFoundError{Resource: "AlertRule", Id: alertRuleId}
 	}
 
-	return &NotFoundError{Resource: "PrometheusRule", Id: fmt.Sprintf("%s/%s", pr.Namespace, pr.Name)}
-}
-
-func (c *client) filterRulesById(rules []monitoringv1.Rule, alertRuleId string, updated *bool) []monitoringv1.Rule {
-	var newRules []monitoringv1.Rule
+	group := &pr.Spec.Groups[groupIdx]
+	group.Rules = slices.Delete(group.Rules, ruleIdx, ruleIdx+1)
 
-	for _, rule := range rules {
-		if c.shouldDeleteRule(rule, alertRuleId) {
-			*updated = true
-			continue
-		}
-		newRules = append(newRules, rule)
+	// Only keep groups that still have rules
+	if len(group.Rules) == 0 {
+		pr.Spec.Groups = slices.Delete(pr.Spec.Groups, groupIdx, groupIdx+1)
 	}
 
-	return newRules
-}
-
-func (c *client) shouldDeleteRule(rule monitoringv1.Rule, alertRuleId string) bool {
-	return alertRuleId == string(c.mapper.GetAlertingRuleId(&rule))
+	return savePrometheusRule(ctx, k8sClient, pr)
 }
@@ -524,4 +524,55 @@ var _ = Describe("DeleteUserDefinedAlertRuleById", func() {
 			Expect(updatedPR.Spec.Groups[1].Name).To(Equal("group3"))
 		})
 	})
+
+	Context("when several rules have the same ID", func() {
+		var (
+			ruleMapper mapper.Client
+			ruleId     mapper.PrometheusAlertRuleId
+		)
+
+		BeforeEach(func() {
+			ruleMapper = mapper.New(mockK8s)
+			client = management.NewWithCustomMapper(ctx, mockK8s, ruleMapper)
+
+			rule := monitoringv1.Rule{Alert: "CopiedAlert", Expr: intstr.FromString("up == 0")}
+			ruleId = ruleMapper.GetAlertingRuleId(&rule)
+
+			prs := map[string]*monitoringv1.PrometheusRule{}
+			for _, namespace := range []string{"ns-a", "ns-b"} {
+				pr := &monitoringv1.PrometheusRule{
+					ObjectMeta: metav1.ObjectMeta{Name: "rules", Namespace: namespace},
+					Spec: monitoringv1.PrometheusRuleSpec{
+						Groups: []monitoringv1.RuleGroup{{Name: "group1", Rules: []monitoringv1.Rule{rule, {Alert: "OtherAlert"}}}},
+					},
+				}
+				prs[namespace+"/rules"] = pr
+				ruleMapper.AddPrometheusRule(pr)
+			}
+			mockPR.SetPrometheusRules(prs)
+		})
+
+		It("should return an ambiguity error listing the candidates", func() {
+			err := client.DeleteUserDefinedAlertRuleById(ctx, string(ruleId))
+
+			var ambiguousErr *management.AmbiguousAlertRuleError
+			Expect(errors.As(err, &ambiguousErr)).To(BeTrue())
+			Expect(ambiguousErr.Candidates).To(ConsistOf(
+				string(ruleId)+"@ns-a/rules/0/0",
+				string(ruleId)+"@ns-b/rules/0/0",
+			))
+		})
+
+		It("should only delete the rule at the location of a qualified ID", func() {
+			err := client.DeleteUserDefinedAlertRuleById(ctx, string(ruleId)+"@ns-b/rules/0/0")
+			Expect(err).ToNot(HaveOccurred())
+
+			prA, _, _ := mockPR.Get(ctx, "ns-a", "rules")
+			Expect(prA.Spec.Groups[0].Rules).To(HaveLen(2))
+
+			prB, _, _ := mockPR.Get(ctx, "ns-b", "rules")
+			Expect(prB.Spec.Groups[0].Rules).To(HaveLen(1))
+			Expect(prB.Spec.Groups[0].Rules[0].Alert).To(Equal("OtherAlert"))
+		})
+	})
 })
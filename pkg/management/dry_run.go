@@ -0,0 +1,113 @@
+package management
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pmezard/go-difflib/difflib"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
+)
+
+// DryRunResult collects the changes of the edits performed with a context returned by WithDryRun
+type DryRunResult struct {
+	dryRun *k8s.DryRun
+}
+
+// WithDryRun returns a copy of ctx with which edits are validated by the API server, including
+// its admission webhooks, without being persisted, and the DryRunResult collecting the changes
+// they would have made. Edits return the same results and errors as when they are persisted
+func WithDryRun(ctx context.Context) (context.Context, *DryRunResult) {
+	ctx, dryRun := k8s.WithDryRun(ctx)
+	return ctx, &DryRunResult{dryRun: dryRun}
+}
+
+// Changes returns the changes the edits would have made, in the order they were made
+func (r *DryRunResult) Changes() ([]DryRunChange, error) {
+	writes := r.dryRun.Writes()
+
+	changes := make([]DryRunChange, 0, len(writes))
+	for _, write := range writes {
+		change, err := dryRunChangeFrom(write)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// DryRunChange describes a write to a PrometheusRule or AlertRelabelConfig that was not persisted
+type DryRunChange struct {
+	// Operation is the type of change: create, update or delete
+	Operation string `json:"operation"`
+
+	// Kind is the kind of the changed resource: PrometheusRule or AlertRelabelConfig
+	Kind string `json:"kind"`
+
+	// Namespace of the changed resource
+	Namespace string `json:"namespace"`
+
+	// Name of the changed resource
+	Name string `json:"name"`
+
+	// Object is the resource as it would be persisted, omitted for deletes
+	Object runtime.Object `json:"object,omitempty"`
+
+	// Diff is a unified diff of the YAML of the resource before and after the change
+	Diff string `json:"diff"`
+}
+
+func dryRunChangeFrom(write k8s.DryRunWrite) (DryRunChange, error) {
+	before, err := dryRunYAML(write.Before)
+	if err != nil {
+		return DryRunChange{}, err
+	}
+
+	after, err := dryRunYAML(write.After)
+	if err != nil {
+		return DryRunChange{}, err
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(before),
+		B:        difflib.SplitLines(after),
+		FromFile: "a/" + write.Namespace + "/" + write.Name,
+		ToFile:   "b/" + write.Namespace + "/" + write.Name,
+		Context:  3,
+	})
+	if err != nil {
+		return DryRunChange{}, fmt.Errorf("failed to diff %s %s/%s: %w", write.Kind, write.Namespace, write.Name, err)
+	}
+
+	return DryRunChange{
+		Operation: write.Operation,
+		Kind:      write.Kind,
+		Namespace: write.Namespace,
+		Name:      write.Name,
+		Object:    write.After,
+		Diff:      diff,
+	}, nil
+}
+
+// dryRunYAML returns the YAML of obj without its managedFields, which are
+// written by the API server and only add noise to the diff
+func dryRunYAML(obj runtime.Object) (string, error) {
+	if obj == nil {
+		return "", nil
+	}
+
+	obj = obj.DeepCopyObject()
+	if meta, ok := obj.(metav1.Object); ok {
+		meta.SetManagedFields(nil)
+	}
+
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal %T: %w", obj, err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,177 @@
+package management_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	osmv1 "github.com/openshift/api/monitoring/v1"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management/mapper"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management/testutils"
+)
+
+var _ = Describe("WithDryRun", func() {
+	var (
+		ctx        context.Context
+		mockPR     *testutils.MockPrometheusRuleInterface
+		mockARC    *testutils.MockAlertRelabelConfigInterface
+		ruleMapper mapper.Client
+		client     management.Client
+		userRule   monitoringv1.Rule
+		userId     mapper.PrometheusAlertRuleId
+		platformId mapper.PrometheusAlertRuleId
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		mockPR = &testutils.MockPrometheusRuleInterface{}
+		mockARC = &testutils.MockAlertRelabelConfigInterface{}
+		mockK8s := &testutils.MockClient{
+			PrometheusRulesFunc: func() k8s.PrometheusRuleInterface {
+				return mockPR
+			},
+			AlertRelabelConfigsFunc: func() k8s.AlertRelabelConfigInterface {
+				return mockARC
+			},
+		}
+		ruleMapper = mapper.New(mockK8s)
+		client = management.NewWithCustomMapper(ctx, mockK8s, ruleMapper)
+
+		userRule = monitoringv1.Rule{
+			Alert:  "UserAlert",
+			Expr:   intstr.FromString("up == 0"),
+			Labels: map[string]string{"severity": "warning"},
+		}
+		userId = ruleMapper.GetAlertingRuleId(&userRule)
+
+		platformRule := monitoringv1.Rule{
+			Alert:  "PlatformAlert",
+			Expr:   intstr.FromString("up == 0"),
+			Labels: map[string]string{"severity": "warning"},
+		}
+		platformId = ruleMapper.GetAlertingRuleId(&platformRule)
+
+		userPR := &monitoringv1.PrometheusRule{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "rules", ResourceVersion: "1"},
+			Spec: monitoringv1.PrometheusRuleSpec{
+				Groups: []monitoringv1.RuleGroup{{Name: "g1", Rules: []monitoringv1.Rule{userRule}}},
+			},
+		}
+		platformPR := &monitoringv1.PrometheusRule{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-monitoring", Name: "platform-rules"},
+			Spec: monitoringv1.PrometheusRuleSpec{
+				Groups: []monitoringv1.RuleGroup{{Name: "platform", Rules: []monitoringv1.Rule{platformRule}}},
+			},
+		}
+		ruleMapper.AddPrometheusRule(userPR)
+		ruleMapper.AddPrometheusRule(platformPR)
+		mockPR.SetPrometheusRules(map[string]*monitoringv1.PrometheusRule{
+			"team-a/rules":                        userPR,
+			"openshift-monitoring/platform-rules": platformPR,
+		})
+	})
+
+	It("should return the PrometheusRule a create would write without persisting it", func() {
+		dryRunCtx, result := management.WithDryRun(ctx)
+
+		newRule := monitoringv1.Rule{Alert: "NewAlert", Expr: intstr.FromString("vector(1)")}
+		_, err := client.CreateUserDefinedAlertRule(dryRunCtx, newRule, management.PrometheusRuleOptions{Namespace: "team-b", Name: "new-rules"})
+		Expect(err).NotTo(HaveOccurred())
+
+		_, found, _ := mockPR.Get(ctx, "team-b", "new-rules")
+		Expect(found).To(BeFalse())
+
+		changes, err := result.Changes()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changes).To(HaveLen(1))
+		Expect(changes[0].Operation).To(Equal(k8s.DryRunCreate))
+		Expect(changes[0].Kind).To(Equal("PrometheusRule"))
+		Expect(changes[0].Namespace).To(Equal("team-b"))
+		Expect(changes[0].Name).To(Equal("new-rules"))
+
+		created, ok := changes[0].Object.(*monitoringv1.PrometheusRule)
+		Expect(ok).To(BeTrue())
+		Expect(created.Spec.Groups[0].Rules[0].Alert).To(Equal("NewAlert"))
+		Expect(changes[0].Diff).To(ContainSubstring("+++ b/team-b/new-rules"))
+		Expect(changes[0].Diff).To(ContainSubstring("+    - alert: NewAlert"))
+	})
+
+	It("should return the deletion of a PrometheusRule left without groups", func() {
+		dryRunCtx, result := management.WithDryRun(ctx)
+
+		err := client.DeleteUserDefinedAlertRuleById(dryRunCtx, string(userId))
+		Expect(err).NotTo(HaveOccurred())
+
+		pr, found, _ := mockPR.Get(ctx, "team-a", "rules")
+		Expect(found).To(BeTrue())
+		Expect(pr.Spec.Groups[0].Rules).To(HaveLen(1))
+
+		changes, err := result.Changes()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changes).To(HaveLen(1))
+		Expect(changes[0].Operation).To(Equal(k8s.DryRunDelete))
+		Expect(changes[0].Object).To(BeNil())
+		Expect(changes[0].Diff).To(ContainSubstring("-    - alert: UserAlert"))
+	})
+
+	It("should return the update of a rule group with its diff", func() {
+		dryRunCtx, result := management.WithDryRun(ctx)
+
+		_, err := client.UpdateRuleGroup(dryRunCtx, types.NamespacedName{Namespace: "team-a", Name: "rules"}, "g1", monitoringv1.RuleGroup{Name: "renamed"})
+		Expect(err).NotTo(HaveOccurred())
+
+		pr, _, _ := mockPR.Get(ctx, "team-a", "rules")
+		Expect(pr.Spec.Groups[0].Name).To(Equal("g1"))
+
+		changes, err := result.Changes()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changes).To(HaveLen(1))
+		Expect(changes[0].Operation).To(Equal(k8s.DryRunUpdate))
+		Expect(changes[0].Diff).To(ContainSubstring("-  - name: g1"))
+		Expect(changes[0].Diff).To(ContainSubstring("+  - name: renamed"))
+	})
+
+	It("should return the AlertRelabelConfig overriding the labels of a platform rule", func() {
+		dryRunCtx, result := management.WithDryRun(ctx)
+
+		updateResult, err := client.UpdateAlertRuleLabels(dryRunCtx, string(platformId), map[string]string{"severity": "critical"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updateResult.RelabelConfigs).NotTo(BeEmpty())
+
+		Expect(mockARC.AlertRelabelConfigs).To(BeEmpty())
+
+		changes, err := result.Changes()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changes).To(HaveLen(1))
+		Expect(changes[0].Operation).To(Equal(k8s.DryRunCreate))
+		Expect(changes[0].Kind).To(Equal("AlertRelabelConfig"))
+		Expect(changes[0].Namespace).To(Equal(management.PlatformAlertRelabelConfigNamespace))
+
+		arc, ok := changes[0].Object.(*osmv1.AlertRelabelConfig)
+		Expect(ok).To(BeTrue())
+		Expect(arc.Spec.Configs).To(Equal(updateResult.RelabelConfigs))
+		Expect(changes[0].Diff).To(ContainSubstring("replacement: critical"))
+	})
+
+	It("should return the errors the edit would return", func() {
+		dryRunCtx, result := management.WithDryRun(ctx)
+
+		err := client.DeleteUserDefinedAlertRuleById(dryRunCtx, string(platformId))
+
+		var notAllowed *management.NotAllowedError
+		Expect(errors.As(err, &notAllowed)).To(BeTrue())
+
+		changes, err := result.Changes()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changes).To(BeEmpty())
+	})
+})
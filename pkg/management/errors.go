@@ -1,6 +1,10 @@
 package management
 
-import "fmt"
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
 
 type NotFoundError struct {
 	Resource string
@@ -18,3 +22,62 @@ type NotAllowedError struct {
 func (r *NotAllowedError) Error() string {
 	return r.Message
 }
+
+type ConflictError struct {
+	Message string
+}
+
+func (r *ConflictError) Error() string {
+	return r.Message
+}
+
+type InvalidArgumentError struct {
+	Message string
+}
+
+func (r *InvalidArgumentError) Error() string {
+	return r.Message
+}
+
+type PreconditionFailedError struct {
+	Message string
+}
+
+func (r *PreconditionFailedError) Error() string {
+	return r.Message
+}
+
+type AmbiguousAlertRuleError struct {
+	Id         string
+	Candidates []string
+}
+
+func (r *AmbiguousAlertRuleError) Error() string {
+	return fmt.Sprintf("alert rule id %s matches %d rules, use one of: %s", r.Id, len(r.Candidates), strings.Join(r.Candidates, ", "))
+}
+
+// FieldError is a validation failure of a field of a rule
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError holds the fields of a rule that Prometheus would fail to load
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (r *ValidationError) Error() string {
+	messages := make([]string, 0, len(r.Errors))
+	for _, fieldErr := range r.Errors {
+		messages = append(messages, fmt.Sprintf("%s: %s", fieldErr.Field, fieldErr.Message))
+	}
+	return "invalid rule: " + strings.Join(messages, "; ")
+}
+
+func sortFieldErrors(errs []FieldError) []FieldError {
+	sort.SliceStable(errs, func(i, j int) bool {
+		return errs[i].Field < errs[j].Field
+	})
+	return errs
+}
@@ -0,0 +1,21 @@
+package management
+
+import (
+	"context"
+
+	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
+)
+
+func (c *client) ExpireSilence(ctx context.Context, silenceId string) error {
+	existing, err := c.GetSilence(ctx, silenceId)
+	if err != nil {
+		return err
+	}
+
+	// Expiring a silence twice is a no-op, so that requests can be retried
+	if existing.Status != nil && existing.Status.State == k8s.SilenceStateExpired {
+		return nil
+	}
+
+	return c.k8sClient.AlertmanagerSilences().Expire(ctx, silenceId)
+}
@@ -0,0 +1,39 @@
+package management
+
+import (
+	"context"
+	"errors"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/machadovilaca/alerts-ui-management/pkg/management/mapper"
+)
+
+func (c *client) FindPrometheusRuleByAlertRuleId(_ context.Context, alertRuleId string) (types.NamespacedName, error) {
+	prId, err := c.findPrometheusRuleId(alertRuleId)
+	if err != nil {
+		return types.NamespacedName{}, err
+	}
+
+	return types.NamespacedName(*prId), nil
+}
+
+// findPrometheusRuleId returns the PrometheusRule containing the alert rule, or an
+// AmbiguousAlertRuleError if several rules have the ID and it is not qualified
+// with the rule location
+func (c *client) findPrometheusRuleId(alertRuleId string) (*mapper.PrometheusRuleId, error) {
+	prId, err := c.mapper.FindAlertRuleById(mapper.PrometheusAlertRuleId(alertRuleId))
+	if err != nil {
+		var ambiguousErr *mapper.AmbiguousAlertRuleIdError
+		if errors.As(err, &ambiguousErr) {
+			candidates := make([]string, 0, len(ambiguousErr.Candidates))
+			for _, candidate := range ambiguousErr.Candidates {
+				candidates = append(candidates, string(candidate))
+			}
+			return nil, &AmbiguousAlertRuleError{Id: alertRuleId, Candidates: candidates}
+		}
+		return nil, &NotFoundError{Resource: "AlertRule", Id: alertRuleId}
+	}
+
+	return prId, nil
+}
@@ -0,0 +1,231 @@
+package management
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management/mapper"
+)
+
+const (
+	// defaultAlertHistoryStep is the default resolution of the alert history,
+	// the default evaluation interval of the rules
+	defaultAlertHistoryStep = 30 * time.Second
+
+	// maxAlertHistorySamples is the number of samples per series Prometheus
+	// returns for a range query
+	maxAlertHistorySamples = 11000
+)
+
+func (c *client) GetAlertHistory(ctx context.Context, filters AlertHistoryFilters, start time.Time, end time.Time) ([]AlertHistory, error) {
+	if !start.Before(end) {
+		return nil, &InvalidArgumentError{Message: "the start of the alert history must be before its end"}
+	}
+
+	labels := make(map[string]string, len(filters.Labels)+1)
+	for key, value := range filters.Labels {
+		labels[key] = value
+	}
+
+	var ruleLocation *mapper.AlertRuleLocation
+	if filters.AlertRuleId != "" {
+		cached, err := c.cachedAlertRule(filters.AlertRuleId)
+		if err != nil {
+			return nil, err
+		}
+		if cached.Rule.Alert == "" {
+			return nil, &InvalidArgumentError{Message: fmt.Sprintf("alert rule %s is a recording rule", filters.AlertRuleId)}
+		}
+		if alertname, found := labels["alertname"]; found && alertname != cached.Rule.Alert {
+			return []AlertHistory{}, nil
+		}
+		labels["alertname"] = cached.Rule.Alert
+		ruleLocation = &cached.Location
+	}
+
+	selector, err := alertHistorySelector(labels)
+	if err != nil {
+		return nil, err
+	}
+
+	step := alertHistoryStep(filters.Step, start, end)
+	query := c.k8sClient.PrometheusQuery()
+
+	firing, err := query.QueryRange(ctx, k8s.QueryRangeRequest{
+		Query: `ALERTS{alertstate="firing"` + selector + `}`,
+		Start: start,
+		End:   end,
+		Step:  step,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the ALERTS history: %w", err)
+	}
+
+	forState, err := query.QueryRange(ctx, k8s.QueryRangeRequest{
+		Query: `ALERTS_FOR_STATE{` + strings.TrimPrefix(selector, ",") + `}`,
+		Start: start,
+		End:   end,
+		Step:  step,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the ALERTS_FOR_STATE history: %w", err)
+	}
+
+	// ALERTS_FOR_STATE holds the time each alert became pending, so that
+	// alerts resolved and firing again between two evaluations are told apart
+	activeAt := make(map[string]map[int64]time.Time, len(forState))
+	for _, series := range forState {
+		byTime := make(map[int64]time.Time, len(series.Samples))
+		for _, sample := range series.Samples {
+			byTime[sample.Time.UnixMilli()] = time.Unix(int64(sample.Value), 0).UTC()
+		}
+		activeAt[labelSetKey(alertLabels(series.Metric))] = byTime
+	}
+
+	lastEvaluation := start.Add(end.Sub(start) / step * step)
+
+	alerts := make([]k8s.PrometheusAlert, 0, len(firing))
+	intervals := make([][]FiringInterval, 0, len(firing))
+	for _, series := range firing {
+		seriesLabels := alertLabels(series.Metric)
+		seriesIntervals := firingIntervals(series.Samples, activeAt[labelSetKey(seriesLabels)], step, lastEvaluation)
+		if len(seriesIntervals) == 0 {
+			continue
+		}
+
+		alerts = append(alerts, k8s.PrometheusAlert{Labels: seriesLabels, State: "firing"})
+		intervals = append(intervals, seriesIntervals)
+	}
+
+	// Rules are matched by the labels of the alerts before relabeling
+	locations, err := c.linkAlertRules(ctx, alerts)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]AlertHistory, 0, len(alerts))
+	for i, alert := range alerts {
+		if ruleLocation != nil && (locations[i] == nil || *locations[i] != *ruleLocation) {
+			continue
+		}
+
+		updatedAlert, err := c.updateAlertBasedOnRelabelConfig(&alert)
+		if err != nil {
+			// Alert was dropped by relabel config, skip it
+			continue
+		}
+
+		history = append(history, AlertHistory{
+			Labels:                  updatedAlert.Labels,
+			AlertRuleId:             updatedAlert.AlertRuleId,
+			PrometheusRuleNamespace: updatedAlert.PrometheusRuleNamespace,
+			PrometheusRuleName:      updatedAlert.PrometheusRuleName,
+			Source:                  updatedAlert.Source,
+			Intervals:               intervals[i],
+		})
+	}
+
+	return history, nil
+}
+
+// firingIntervals reduces the samples of an ALERTS series to the intervals
+// during which the alert fired. A missing evaluation, or a change of the time
+// the alert became pending, ends the interval
+func firingIntervals(samples []k8s.Sample, activeAt map[int64]time.Time, step time.Duration, lastEvaluation time.Time) []FiringInterval {
+	var intervals []FiringInterval
+	var current *FiringInterval
+
+	for _, sample := range samples {
+		sampleActiveAt, known := activeAt[sample.Time.UnixMilli()]
+
+		// Sample times are rounded to milliseconds, consecutive evaluations are one step apart
+		if current != nil && sample.Time.Sub(current.End) <= step+step/2 &&
+			(!known || current.ActiveAt == nil || current.ActiveAt.Equal(sampleActiveAt)) {
+			current.End = sample.Time
+			if known && current.ActiveAt == nil {
+				current.ActiveAt = &sampleActiveAt
+			}
+			continue
+		}
+
+		intervals = append(intervals, FiringInterval{Start: sample.Time, End: sample.Time})
+		current = &intervals[len(intervals)-1]
+		if known {
+			current.ActiveAt = &sampleActiveAt
+		}
+	}
+
+	if current != nil {
+		current.Ongoing = !current.End.Before(lastEvaluation.Add(-step / 2))
+	}
+	return intervals
+}
+
+// alertHistorySelector returns the PromQL label matchers of the labels, each
+// preceded by a comma
+func alertHistorySelector(labels map[string]string) (string, error) {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		if !labelNameRegexp.MatchString(key) {
+			return "", &InvalidArgumentError{Message: fmt.Sprintf("invalid label name %q", key)}
+		}
+		if key == "alertstate" || key == "__name__" {
+			return "", &InvalidArgumentError{Message: fmt.Sprintf("alerts cannot be filtered by the %s label", key)}
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var selector strings.Builder
+	for _, key := range keys {
+		selector.WriteString("," + key + "=" + strconv.Quote(labels[key]))
+	}
+	return selector.String(), nil
+}
+
+// alertHistoryStep returns the step of the range queries, increased so that
+// each series has at most maxAlertHistorySamples samples
+func alertHistoryStep(step time.Duration, start time.Time, end time.Time) time.Duration {
+	if step <= 0 {
+		step = defaultAlertHistoryStep
+	}
+
+	minStep := end.Sub(start) / (maxAlertHistorySamples - 1)
+	if step < minStep {
+		step = minStep.Truncate(time.Second) + time.Second
+	}
+	return step
+}
+
+// alertLabels returns the labels of an ALERTS or ALERTS_FOR_STATE series
+// without the metric name and the state
+func alertLabels(metric map[string]string) map[string]string {
+	labels := make(map[string]string, len(metric))
+	for key, value := range metric {
+		if key == "__name__" || key == "alertstate" {
+			continue
+		}
+		labels[key] = value
+	}
+	return labels
+}
+
+// labelSetKey returns a string identifying the label set
+func labelSetKey(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var key strings.Builder
+	for _, name := range keys {
+		key.WriteString(name + "\xff" + labels[name] + "\xff")
+	}
+	return key.String()
+}
@@ -0,0 +1,186 @@
+package management_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management/mapper"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management/testutils"
+)
+
+var _ = Describe("GetAlertHistory", func() {
+	var (
+		ctx        context.Context
+		start      time.Time
+		end        time.Time
+		queries    []k8s.QueryRangeRequest
+		alerts     []k8s.RangeSeries
+		forState   []k8s.RangeSeries
+		ruleMapper mapper.Client
+		client     management.Client
+		fooRule    monitoringv1.Rule
+	)
+
+	// samples returns a sample at each step from the offsets, in steps from start
+	samples := func(value float64, offsets ...int) []k8s.Sample {
+		out := make([]k8s.Sample, 0, len(offsets))
+		for _, offset := range offsets {
+			out = append(out, k8s.Sample{Time: start.Add(time.Duration(offset) * 30 * time.Second), Value: value})
+		}
+		return out
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		start = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+		end = start.Add(5 * time.Minute)
+		queries = nil
+		alerts = nil
+		forState = nil
+
+		mockK8s := &testutils.MockClient{
+			PrometheusQueryFunc: func() k8s.PrometheusQueryInterface {
+				return &testutils.MockPrometheusQueryInterface{
+					QueryRangeFunc: func(ctx context.Context, req k8s.QueryRangeRequest) ([]k8s.RangeSeries, error) {
+						queries = append(queries, req)
+						if strings.HasPrefix(req.Query, "ALERTS_FOR_STATE") {
+							return forState, nil
+						}
+						return alerts, nil
+					},
+				}
+			},
+		}
+		ruleMapper = mapper.New(mockK8s)
+		client = management.NewWithCustomMapper(ctx, mockK8s, ruleMapper)
+
+		fooRule = monitoringv1.Rule{Alert: "Foo", Expr: intstr.FromString("up == 0"), Labels: map[string]string{"severity": "warning"}}
+		ruleMapper.AddPrometheusRule(&monitoringv1.PrometheusRule{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "rules"},
+			Spec: monitoringv1.PrometheusRuleSpec{
+				Groups: []monitoringv1.RuleGroup{{Name: "g1", Rules: []monitoringv1.Rule{
+					fooRule,
+					{Record: "job:up:sum", Expr: intstr.FromString("sum(up)")},
+				}}},
+			},
+		})
+	})
+
+	It("should reduce the samples to firing intervals per label set", func() {
+		alerts = []k8s.RangeSeries{
+			{
+				Metric:  map[string]string{"__name__": "ALERTS", "alertname": "Foo", "alertstate": "firing", "severity": "warning", "pod": "a"},
+				Samples: samples(1, 1, 2, 3, 6, 7),
+			},
+			{
+				Metric:  map[string]string{"__name__": "ALERTS", "alertname": "Bar", "alertstate": "firing", "pod": "b"},
+				Samples: samples(1, 9, 10),
+			},
+		}
+		forState = []k8s.RangeSeries{{
+			Metric:  map[string]string{"__name__": "ALERTS_FOR_STATE", "alertname": "Foo", "severity": "warning", "pod": "a"},
+			Samples: samples(float64(start.Unix()), 0, 1, 2, 3),
+		}}
+
+		history, err := client.GetAlertHistory(ctx, management.AlertHistoryFilters{}, start, end)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(queries).To(HaveLen(2))
+		Expect(queries[0].Query).To(Equal(`ALERTS{alertstate="firing"}`))
+		Expect(queries[1].Query).To(Equal(`ALERTS_FOR_STATE{}`))
+		Expect(queries[0].Step).To(Equal(30 * time.Second))
+
+		activeAt := start
+		Expect(history).To(HaveLen(2))
+		Expect(history[0].Labels).To(Equal(map[string]string{"alertname": "Foo", "severity": "warning", "pod": "a"}))
+		Expect(history[0].AlertRuleId).To(Equal(string(ruleMapper.GetAlertingRuleId(&fooRule))))
+		Expect(history[0].PrometheusRuleNamespace).To(Equal("team-a"))
+		Expect(history[0].Intervals).To(Equal([]management.FiringInterval{
+			{ActiveAt: &activeAt, Start: start.Add(30 * time.Second), End: start.Add(90 * time.Second)},
+			{Start: start.Add(3 * time.Minute), End: start.Add(210 * time.Second)},
+		}))
+
+		Expect(history[1].AlertRuleId).To(BeEmpty())
+		Expect(history[1].Intervals).To(Equal([]management.FiringInterval{
+			{Start: start.Add(270 * time.Second), End: end, Ongoing: true},
+		}))
+	})
+
+	It("should split intervals when the alert became pending again between two evaluations", func() {
+		alerts = []k8s.RangeSeries{{
+			Metric:  map[string]string{"__name__": "ALERTS", "alertname": "Foo", "alertstate": "firing"},
+			Samples: samples(1, 0, 1, 2, 3),
+		}}
+		forState = []k8s.RangeSeries{
+			{
+				Metric:  map[string]string{"__name__": "ALERTS_FOR_STATE", "alertname": "Foo"},
+				Samples: append(samples(float64(start.Unix()), 0, 1), samples(float64(start.Add(time.Minute).Unix()), 2, 3)...),
+			},
+		}
+
+		history, err := client.GetAlertHistory(ctx, management.AlertHistoryFilters{}, start, end)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(history).To(HaveLen(1))
+		Expect(history[0].Intervals).To(HaveLen(2))
+		Expect(history[0].Intervals[0].End).To(Equal(start.Add(30 * time.Second)))
+		Expect(history[0].Intervals[1].Start).To(Equal(start.Add(time.Minute)))
+		Expect(*history[0].Intervals[1].ActiveAt).To(Equal(start.Add(time.Minute)))
+	})
+
+	It("should only query the alerts of the rule and its label filters", func() {
+		alerts = []k8s.RangeSeries{
+			{Metric: map[string]string{"alertname": "Foo", "severity": "warning"}, Samples: samples(1, 0)},
+			{Metric: map[string]string{"alertname": "Foo", "severity": "critical"}, Samples: samples(1, 0)},
+		}
+
+		history, err := client.GetAlertHistory(ctx, management.AlertHistoryFilters{
+			AlertRuleId: string(ruleMapper.GetAlertingRuleId(&fooRule)),
+			Labels:      map[string]string{"pod": `a"b`},
+			Step:        time.Minute,
+		}, start, end)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(queries[0].Query).To(Equal(`ALERTS{alertstate="firing",alertname="Foo",pod="a\"b"}`))
+		Expect(queries[1].Query).To(Equal(`ALERTS_FOR_STATE{alertname="Foo",pod="a\"b"}`))
+		Expect(queries[0].Step).To(Equal(time.Minute))
+		Expect(history).To(HaveLen(1))
+		Expect(history[0].Labels["severity"]).To(Equal("warning"))
+	})
+
+	It("should increase the step of long ranges", func() {
+		_, err := client.GetAlertHistory(ctx, management.AlertHistoryFilters{}, start, start.Add(30*24*time.Hour))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(queries[0].Step).To(Equal(236 * time.Second))
+	})
+
+	It("should reject invalid filters and ranges", func() {
+		var invalidArgument *management.InvalidArgumentError
+
+		_, err := client.GetAlertHistory(ctx, management.AlertHistoryFilters{}, end, start)
+		Expect(errors.As(err, &invalidArgument)).To(BeTrue())
+
+		_, err = client.GetAlertHistory(ctx, management.AlertHistoryFilters{Labels: map[string]string{"a-b": "c"}}, start, end)
+		Expect(errors.As(err, &invalidArgument)).To(BeTrue())
+
+		_, err = client.GetAlertHistory(ctx, management.AlertHistoryFilters{Labels: map[string]string{"alertstate": "pending"}}, start, end)
+		Expect(errors.As(err, &invalidArgument)).To(BeTrue())
+
+		recordingRule := monitoringv1.Rule{Record: "job:up:sum", Expr: intstr.FromString("sum(up)")}
+		_, err = client.GetAlertHistory(ctx, management.AlertHistoryFilters{AlertRuleId: string(ruleMapper.GetAlertingRuleId(&recordingRule))}, start, end)
+		Expect(errors.As(err, &invalidArgument)).To(BeTrue())
+
+		Expect(queries).To(BeEmpty())
+	})
+})
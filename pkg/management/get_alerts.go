@@ -7,16 +7,37 @@ import (
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 
 	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management/mapper"
 )
 
 func (c *client) GetAlerts(ctx context.Context, req k8s.GetAlertsRequest) ([]k8s.PrometheusAlert, error) {
+	var ruleLocation *mapper.AlertRuleLocation
+	if req.AlertRuleId != "" {
+		cached, err := c.cachedAlertRule(req.AlertRuleId)
+		if err != nil {
+			return nil, err
+		}
+		ruleLocation = &cached.Location
+	}
+
 	alerts, err := c.k8sClient.PrometheusAlerts().GetAlerts(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get alerts: %w", err)
 	}
+	c.recordAlertsSuccess()
+
+	// Rules are matched by the labels of the alerts before relabeling
+	locations, err := c.linkAlertRules(ctx, alerts)
+	if err != nil {
+		return nil, err
+	}
 
 	var result []k8s.PrometheusAlert
-	for _, alert := range alerts {
+	for i, alert := range alerts {
+		if ruleLocation != nil && (locations[i] == nil || *locations[i] != *ruleLocation) {
+			continue
+		}
+
 		// Apply relabel configurations to the alert
 		updatedAlert, err := c.updateAlertBasedOnRelabelConfig(&alert)
 		if err != nil {
@@ -26,7 +47,87 @@ func (c *client) GetAlerts(ctx context.Context, req k8s.GetAlertsRequest) ([]k8s
 		result = append(result, updatedAlert)
 	}
 
-	return result, nil
+	if !req.IncludeAlertmanager && req.Silenced == nil && req.Inhibited == nil {
+		return result, nil
+	}
+
+	amAlerts, err := c.k8sClient.AlertmanagerAlerts().GetAlerts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alertmanager alerts: %w", err)
+	}
+
+	return mergeAlertmanagerAlerts(result, amAlerts, req), nil
+}
+
+// cachedAlertRule returns the cached rule with the ID
+func (c *client) cachedAlertRule(alertRuleId string) (*mapper.CachedAlertRule, error) {
+	if _, err := c.findPrometheusRuleId(alertRuleId); err != nil {
+		return nil, err
+	}
+
+	cached, err := c.mapper.GetAlertRuleById(mapper.PrometheusAlertRuleId(alertRuleId))
+	if err != nil {
+		return nil, &NotFoundError{Resource: "AlertRule", Id: alertRuleId}
+	}
+	return cached, nil
+}
+
+// mergeAlertmanagerAlerts sets the Alertmanager status of each alert and
+// returns the alerts matching the silenced and inhibited filters of req
+func mergeAlertmanagerAlerts(alerts []k8s.PrometheusAlert, amAlerts []k8s.AlertmanagerAlert, req k8s.GetAlertsRequest) []k8s.PrometheusAlert {
+	byAlertname := make(map[string][]*k8s.AlertmanagerAlert)
+	for i := range amAlerts {
+		alertname := amAlerts[i].Labels["alertname"]
+		byAlertname[alertname] = append(byAlertname[alertname], &amAlerts[i])
+	}
+
+	var result []k8s.PrometheusAlert
+	for _, alert := range alerts {
+		if amAlert := findAlertmanagerAlert(alert, byAlertname[alert.Labels["alertname"]]); amAlert != nil {
+			alert.Fingerprint = amAlert.Fingerprint
+			alert.SilencedBy = amAlert.Status.SilencedBy
+			alert.InhibitedBy = amAlert.Status.InhibitedBy
+			alert.Receivers = make([]string, 0, len(amAlert.Receivers))
+			for _, receiver := range amAlert.Receivers {
+				alert.Receivers = append(alert.Receivers, receiver.Name)
+			}
+		}
+
+		if req.Silenced != nil && *req.Silenced != (len(alert.SilencedBy) > 0) {
+			continue
+		}
+		if req.Inhibited != nil && *req.Inhibited != (len(alert.InhibitedBy) > 0) {
+			continue
+		}
+		result = append(result, alert)
+	}
+
+	return result
+}
+
+// findAlertmanagerAlert returns the Alertmanager alert of the Prometheus alert.
+// Alertmanager alerts also carry the external labels of Prometheus, so the
+// candidate with all the labels of the alert and the fewest other labels wins
+func findAlertmanagerAlert(alert k8s.PrometheusAlert, candidates []*k8s.AlertmanagerAlert) *k8s.AlertmanagerAlert {
+	var found *k8s.AlertmanagerAlert
+	for _, candidate := range candidates {
+		if !hasLabels(candidate.Labels, alert.Labels) {
+			continue
+		}
+		if found == nil || len(candidate.Labels) < len(found.Labels) {
+			found = candidate
+		}
+	}
+	return found
+}
+
+func hasLabels(labels map[string]string, subset map[string]string) bool {
+	for key, value := range subset {
+		if labels[key] != value {
+			return false
+		}
+	}
+	return true
 }
 
 func (c *client) updateAlertBasedOnRelabelConfig(alert *k8s.PrometheusAlert) (k8s.PrometheusAlert, error) {
@@ -9,9 +9,12 @@ import (
 	. "github.com/onsi/gomega"
 	osmv1 "github.com/openshift/api/monitoring/v1"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
 	"github.com/machadovilaca/alerts-ui-management/pkg/management"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management/mapper"
 	"github.com/machadovilaca/alerts-ui-management/pkg/management/testutils"
 )
 
@@ -119,4 +122,217 @@ var _ = Describe("GetAlerts", func() {
 		Expect(err).ToNot(HaveOccurred())
 		Expect(result[0].Labels).To(HaveKeyWithValue("team", "infra"))
 	})
+
+	It("should apply AlertRelabelConfigs cached by the mapper", func() {
+		realMapper := mapper.New(mockK8s)
+		client = management.NewWithCustomMapper(ctx, mockK8s, realMapper)
+		realMapper.AddAlertRelabelConfig(&osmv1.AlertRelabelConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-wide", Namespace: "openshift-monitoring"},
+			Spec: osmv1.AlertRelabelConfigSpec{
+				Configs: []osmv1.RelabelConfig{
+					{Regex: "pod|instance", Action: "LabelDrop"},
+					{SourceLabels: []osmv1.LabelName{"namespace"}, Regex: "team-(.*)", TargetLabel: "team", Replacement: "$1", Action: "Replace"},
+					{SourceLabels: []osmv1.LabelName{"alertname"}, Regex: "Noisy", Action: "Drop"},
+				},
+			},
+		})
+		mockAlerts.SetActiveAlerts([]k8s.PrometheusAlert{
+			{Labels: map[string]string{"alertname": "PodDown", "namespace": "team-a", "pod": "p1", "instance": "i1"}, State: "firing", ActiveAt: testTime},
+			{Labels: map[string]string{"alertname": "NoisyAlert"}, State: "firing", ActiveAt: testTime},
+			{Labels: map[string]string{"alertname": "Noisy"}, State: "firing", ActiveAt: testTime},
+		})
+
+		result, err := client.GetAlerts(ctx, k8s.GetAlertsRequest{})
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(HaveLen(2))
+		Expect(result[0].Labels).To(Equal(map[string]string{"alertname": "PodDown", "namespace": "team-a", "team": "a"}))
+		Expect(result[1].Labels).To(Equal(map[string]string{"alertname": "NoisyAlert"}))
+	})
+
+	Context("when merging the Alertmanager status of the alerts", func() {
+		var mockAMAlerts *testutils.MockAlertmanagerAlertsInterface
+
+		BeforeEach(func() {
+			mockAMAlerts = &testutils.MockAlertmanagerAlertsInterface{}
+			mockK8s.AlertmanagerAlertsFunc = func() k8s.AlertmanagerAlertsInterface {
+				return mockAMAlerts
+			}
+			mockMapper.GetAlertRelabelConfigSpecFunc = func(*monitoringv1.Rule) []osmv1.RelabelConfig { return nil }
+
+			mockAlerts.SetActiveAlerts([]k8s.PrometheusAlert{
+				{Labels: map[string]string{"alertname": "PodDown", "pod": "p1"}, State: "firing", ActiveAt: testTime},
+				{Labels: map[string]string{"alertname": "PodDown", "pod": "p2"}, State: "firing", ActiveAt: testTime},
+				{Labels: map[string]string{"alertname": "PodDown", "pod": "p3"}, State: "pending", ActiveAt: testTime},
+			})
+			mockAMAlerts.Alerts = []k8s.AlertmanagerAlert{
+				{
+					Labels:      map[string]string{"alertname": "PodDown", "pod": "p1", "prometheus": "openshift-monitoring/k8s"},
+					Fingerprint: "fp1",
+					Receivers:   []k8s.AlertmanagerReceiver{{Name: "pager"}},
+					Status:      k8s.AlertmanagerAlertStatus{State: "suppressed", SilencedBy: []string{"s1"}, InhibitedBy: []string{}},
+				},
+				{
+					Labels:      map[string]string{"alertname": "PodDown", "pod": "p2", "prometheus": "openshift-monitoring/k8s"},
+					Fingerprint: "fp2",
+					Receivers:   []k8s.AlertmanagerReceiver{{Name: "pager"}, {Name: "email"}},
+					Status:      k8s.AlertmanagerAlertStatus{State: "active"},
+				},
+				{
+					Labels:      map[string]string{"alertname": "PodDown", "pod": "p2", "prometheus": "openshift-user-workload-monitoring/user-workload", "extra": "x"},
+					Fingerprint: "fp2-uwm",
+					Status:      k8s.AlertmanagerAlertStatus{State: "suppressed", InhibitedBy: []string{"fp9"}},
+				},
+			}
+		})
+
+		It("should not query Alertmanager unless requested", func() {
+			mockAMAlerts.GetAlertsFunc = func(ctx context.Context) ([]k8s.AlertmanagerAlert, error) {
+				Fail("Alertmanager should not be queried")
+				return nil, nil
+			}
+
+			result, err := client.GetAlerts(ctx, k8s.GetAlertsRequest{})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(HaveLen(3))
+			Expect(result[0].Fingerprint).To(BeEmpty())
+		})
+
+		It("should merge the status of the closest Alertmanager alert", func() {
+			result, err := client.GetAlerts(ctx, k8s.GetAlertsRequest{IncludeAlertmanager: true})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(HaveLen(3))
+			Expect(result[0].Fingerprint).To(Equal("fp1"))
+			Expect(result[0].SilencedBy).To(Equal([]string{"s1"}))
+			Expect(result[0].Receivers).To(Equal([]string{"pager"}))
+			Expect(result[1].Fingerprint).To(Equal("fp2"))
+			Expect(result[1].InhibitedBy).To(BeEmpty())
+			Expect(result[1].Receivers).To(Equal([]string{"pager", "email"}))
+			Expect(result[2].Fingerprint).To(BeEmpty())
+		})
+
+		It("should filter out silenced alerts", func() {
+			silenced := false
+
+			result, err := client.GetAlerts(ctx, k8s.GetAlertsRequest{Silenced: &silenced})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(HaveLen(2))
+			Expect(result[0].Labels).To(HaveKeyWithValue("pod", "p2"))
+			Expect(result[1].Labels).To(HaveKeyWithValue("pod", "p3"))
+		})
+
+		It("should return an error when Alertmanager cannot be queried", func() {
+			mockAMAlerts.GetAlertsFunc = func(ctx context.Context) ([]k8s.AlertmanagerAlert, error) {
+				return nil, errors.New("connection refused")
+			}
+
+			_, err := client.GetAlerts(ctx, k8s.GetAlertsRequest{IncludeAlertmanager: true})
+
+			Expect(err).To(MatchError(ContainSubstring("failed to get alertmanager alerts")))
+		})
+	})
+
+	Context("when linking alerts to their rules", func() {
+		var ruleMapper mapper.Client
+
+		BeforeEach(func() {
+			ruleMapper = mapper.New(mockK8s)
+			client = management.NewWithCustomMapper(ctx, mockK8s, ruleMapper)
+
+			podDown := monitoringv1.Rule{
+				Alert:  "PodDown",
+				Expr:   intstr.FromString("up == 0"),
+				Labels: map[string]string{"severity": "warning", "pod": "{{ $labels.instance }}"},
+			}
+			for _, namespace := range []string{"team-a", "team-b"} {
+				ruleMapper.AddPrometheusRule(&monitoringv1.PrometheusRule{
+					ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "rules"},
+					Spec: monitoringv1.PrometheusRuleSpec{
+						Groups: []monitoringv1.RuleGroup{{Name: "g1", Rules: []monitoringv1.Rule{podDown}}},
+					},
+				})
+			}
+			ruleMapper.AddPrometheusRule(&monitoringv1.PrometheusRule{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-monitoring", Name: "platform-rules"},
+				Spec: monitoringv1.PrometheusRuleSpec{
+					Groups: []monitoringv1.RuleGroup{{Name: "platform", Rules: []monitoringv1.Rule{
+						{Alert: "NodeDown", Expr: intstr.FromString("up == 0"), Labels: map[string]string{"severity": "warning"}},
+						{Alert: "NodeDown", Expr: intstr.FromString("up == 0"), Labels: map[string]string{"severity": "critical"}},
+					}}},
+				},
+			})
+
+			mockAlerts.SetActiveAlerts([]k8s.PrometheusAlert{
+				{Labels: map[string]string{"alertname": "PodDown", "namespace": "team-b", "pod": "p1", "severity": "warning"}, State: "firing"},
+				{Labels: map[string]string{"alertname": "NodeDown", "severity": "critical"}, State: "firing"},
+				{Labels: map[string]string{"alertname": "NodeDown", "severity": "info"}, State: "firing"},
+				{Labels: map[string]string{"alertname": "Unknown"}, State: "firing"},
+			})
+		})
+
+		It("should set the rule, PrometheusRule and source of the alerts", func() {
+			result, err := client.GetAlerts(ctx, k8s.GetAlertsRequest{})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(HaveLen(4))
+
+			Expect(result[0].AlertRuleId).To(HaveSuffix("@team-b/rules/0/0"))
+			Expect(result[0].PrometheusRuleNamespace).To(Equal("team-b"))
+			Expect(result[0].PrometheusRuleName).To(Equal("rules"))
+			Expect(result[0].Source).To(Equal(management.SourceUserDefined))
+
+			rule, err := client.GetRuleById(ctx, result[1].AlertRuleId)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rule.Labels).To(HaveKeyWithValue("severity", "critical"))
+			Expect(result[1].PrometheusRuleName).To(Equal("platform-rules"))
+			Expect(result[1].Source).To(Equal(management.SourcePlatform))
+
+			Expect(result[2].AlertRuleId).To(BeEmpty())
+			Expect(result[3].AlertRuleId).To(BeEmpty())
+			Expect(result[3].Source).To(BeEmpty())
+		})
+
+		It("should prefer the rule whose stable ID the alert carries", func() {
+			pr := &monitoringv1.PrometheusRule{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "team-c", Name: "rules"},
+				Spec: monitoringv1.PrometheusRuleSpec{
+					Groups: []monitoringv1.RuleGroup{{Name: "g1", Rules: []monitoringv1.Rule{
+						{Alert: "Stable", Expr: intstr.FromString("vector(1)"), Annotations: map[string]string{mapper.AlertRuleIdAnnotation: "id-1"}},
+						{Alert: "Stable", Expr: intstr.FromString("vector(2)"), Annotations: map[string]string{mapper.AlertRuleIdAnnotation: "id-2"}},
+					}}},
+				},
+			}
+			ruleMapper.AddPrometheusRule(pr)
+			mockAlerts.SetActiveAlerts([]k8s.PrometheusAlert{
+				{Labels: map[string]string{"alertname": "Stable"}, Annotations: map[string]string{mapper.AlertRuleIdAnnotation: "id-2"}, State: "firing"},
+			})
+
+			result, err := client.GetAlerts(ctx, k8s.GetAlertsRequest{})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(HaveLen(1))
+			Expect(result[0].AlertRuleId).To(Equal("id-2"))
+		})
+
+		It("should filter alerts by rule ID", func() {
+			all, err := client.GetAlerts(ctx, k8s.GetAlertsRequest{})
+			Expect(err).ToNot(HaveOccurred())
+
+			result, err := client.GetAlerts(ctx, k8s.GetAlertsRequest{AlertRuleId: all[1].AlertRuleId})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(HaveLen(1))
+			Expect(result[0].Labels).To(HaveKeyWithValue("alertname", "NodeDown"))
+		})
+
+		It("should return NotFoundError when filtering by an unknown rule ID", func() {
+			_, err := client.GetAlerts(ctx, k8s.GetAlertsRequest{AlertRuleId: "unknown"})
+
+			var notFoundErr *management.NotFoundError
+			Expect(errors.As(err, &notFoundErr)).To(BeTrue())
+		})
+	})
 })
@@ -5,45 +5,142 @@ import (
 	"fmt"
 
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/machadovilaca/alerts-ui-management/pkg/management/mapper"
 )
 
-func (c *client) GetRuleById(ctx context.Context, alertRuleId string) (monitoringv1.Rule, error) {
-	prId, err := c.mapper.FindAlertRuleById(mapper.PrometheusAlertRuleId(alertRuleId))
+func (c *client) GetRuleById(ctx context.Context, alertRuleId string) (Rule, error) {
+	rule, _, _, err := c.getRuleById(ctx, alertRuleId, true)
+	return rule, err
+}
+
+func (c *client) GetRuleByIdWithResourceVersion(ctx context.Context, alertRuleId string) (Rule, string, error) {
+	rule, resourceVersion, _, err := c.getRuleById(ctx, alertRuleId, true)
+	return rule, resourceVersion, err
+}
+
+// getRuleById returns the rule with the resourceVersion of its PrometheusRule
+// and its location, and its status in Prometheus if withStatus is set
+func (c *client) getRuleById(ctx context.Context, alertRuleId string, withStatus bool) (Rule, string, mapper.AlertRuleLocation, error) {
+	// Rules are served from the informer cache, falling back to the API server
+	// for rules not cached yet
+	rule, resourceVersion, prMeta, location, err := c.getCachedRule(alertRuleId)
 	if err != nil {
-		return monitoringv1.Rule{}, err
+		rule, resourceVersion, prMeta, location, err = c.getRuleFromAPIServer(ctx, alertRuleId)
+		if err != nil {
+			return Rule{}, "", mapper.AlertRuleLocation{}, err
+		}
+	}
+
+	source, err := c.classify(ctx, prMeta)
+	if err != nil {
+		return Rule{}, "", mapper.AlertRuleLocation{}, err
+	}
+
+	updatedRule, err := c.updateRuleBasedOnRelabelConfig(rule)
+	if err != nil {
+		return Rule{}, "", mapper.AlertRuleLocation{}, err
+	}
+
+	if updatedRule.Labels == nil {
+		updatedRule.Labels = make(map[string]string)
+	}
+	updatedRule.Labels[alertRuleIdLabel] = alertRuleId
+
+	result := Rule{Rule: updatedRule, Type: ruleType(updatedRule), Source: source}
+	if withStatus {
+		statuses, err := c.getRuleStatusIndex(ctx, false)
+		if err != nil {
+			return Rule{}, "", mapper.AlertRuleLocation{}, err
+		}
+		if statuses != nil {
+			result.Status = statuses.status(prMeta, location, *rule)
+		}
+	}
+
+	return result, resourceVersion, location, nil
+}
+
+func (c *client) getCachedRule(alertRuleId string) (*monitoringv1.Rule, string, metav1.Object, mapper.AlertRuleLocation, error) {
+	cached, err := c.mapper.GetAlertRuleById(mapper.PrometheusAlertRuleId(alertRuleId))
+	if err != nil {
+		return nil, "", nil, mapper.AlertRuleLocation{}, err
+	}
+	return &cached.Rule, cached.ResourceVersion, &cached.PrometheusRuleMeta, cached.Location, nil
+}
+
+func (c *client) getRuleFromAPIServer(ctx context.Context, alertRuleId string) (*monitoringv1.Rule, string, metav1.Object, mapper.AlertRuleLocation, error) {
+	prId, err := c.findPrometheusRuleId(alertRuleId)
+	if err != nil {
+		return nil, "", nil, mapper.AlertRuleLocation{}, err
 	}
 
 	pr, found, err := c.k8sClient.PrometheusRules().Get(ctx, prId.Namespace, prId.Name)
 	if err != nil {
-		return monitoringv1.Rule{}, err
+		return nil, "", nil, mapper.AlertRuleLocation{}, err
 	}
 
 	if !found {
-		return monitoringv1.Rule{}, &NotFoundError{Resource: "PrometheusRule", Id: fmt.Sprintf("%s/%s", prId.Namespace, prId.Name)}
+		return nil, "", nil, mapper.AlertRuleLocation{}, &NotFoundError{Resource: "PrometheusRule", Id: fmt.Sprintf("%s/%s", prId.Namespace, prId.Name)}
+	}
+
+	groupIdx, ruleIdx, found := c.findRule(pr, alertRuleId)
+	if !found {
+		return nil, "", nil, mapper.AlertRuleLocation{}, fmt.Errorf("alert rule with id %s not found in PrometheusRule %s/%s", alertRuleId, prId.Namespace, prId.Name)
+	}
+
+	location := mapper.AlertRuleLocation{
+		PrometheusRuleId: *prId,
+		GroupName:        pr.Spec.Groups[groupIdx].Name,
+		GroupIndex:       groupIdx,
+		RuleIndex:        ruleIdx,
 	}
+	return &pr.Spec.Groups[groupIdx].Rules[ruleIdx], pr.ResourceVersion, pr, location, nil
+}
+
+// findRule returns the group and rule indexes of the alert rule in pr
+// IDs qualified with a location only match the rule at that location. Otherwise,
+// the location cached by the mapper is checked first, so that rules are only
+// scanned if the PrometheusRule has changed since it was cached
+func (c *client) findRule(pr *monitoringv1.PrometheusRule, alertRuleId string) (int, int, bool) {
+	id, location := mapper.ParseAlertingRuleId(mapper.PrometheusAlertRuleId(alertRuleId))
 
-	var rule *monitoringv1.Rule
+	if location != nil {
+		if location.PrometheusRuleId.Namespace == pr.Namespace && location.PrometheusRuleId.Name == pr.Name &&
+			location.GroupIndex < len(pr.Spec.Groups) && location.RuleIndex < len(pr.Spec.Groups[location.GroupIndex].Rules) &&
+			c.mapper.MatchesAlertingRuleId(&pr.Spec.Groups[location.GroupIndex].Rules[location.RuleIndex], id) {
+			return location.GroupIndex, location.RuleIndex, true
+		}
+		return 0, 0, false
+	}
+
+	if cached, err := c.mapper.GetAlertRuleById(id); err == nil {
+		loc := cached.Location
+		if loc.PrometheusRuleId.Namespace == pr.Namespace && loc.PrometheusRuleId.Name == pr.Name &&
+			loc.GroupIndex < len(pr.Spec.Groups) && loc.RuleIndex < len(pr.Spec.Groups[loc.GroupIndex].Rules) &&
+			c.mapper.MatchesAlertingRuleId(&pr.Spec.Groups[loc.GroupIndex].Rules[loc.RuleIndex], id) {
+			return loc.GroupIndex, loc.RuleIndex, true
+		}
+	}
 
 	for groupIdx := range pr.Spec.Groups {
 		for ruleIdx := range pr.Spec.Groups[groupIdx].Rules {
-			foundRule := &pr.Spec.Groups[groupIdx].Rules[ruleIdx]
-			if c.mapper.GetAlertingRuleId(foundRule) == mapper.PrometheusAlertRuleId(alertRuleId) {
-				rule = foundRule
-				break
+			if c.mapper.MatchesAlertingRuleId(&pr.Spec.Groups[groupIdx].Rules[ruleIdx], id) {
+				return groupIdx, ruleIdx, true
 			}
 		}
 	}
 
-	if rule != nil {
-		return c.updateRuleBasedOnRelabelConfig(rule)
-	}
-
-	return monitoringv1.Rule{}, fmt.Errorf("alert rule with id %s not found in PrometheusRule %s/%s", alertRuleId, prId.Namespace, prId.Name)
+	return 0, 0, false
 }
 
 func (c *client) updateRuleBasedOnRelabelConfig(rule *monitoringv1.Rule) (monitoringv1.Rule, error) {
+	// AlertRelabelConfigs only apply to alerts
+	if rule.Record != "" {
+		return *rule, nil
+	}
+
 	configs := c.mapper.GetAlertRelabelConfigSpec(rule)
 
 	updatedLabels, err := applyRelabelConfigs(string(rule.Alert), rule.Labels, configs)
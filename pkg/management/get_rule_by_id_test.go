@@ -6,6 +6,7 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	osmv1 "github.com/openshift/api/monitoring/v1"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -105,6 +106,7 @@ var _ = Describe("GetRuleById", func() {
 			Expect(rule.Alert).To(Equal("TestAlert2"))
 			Expect(rule.Expr.String()).To(Equal("cpu > 80"))
 			Expect(rule.Annotations).To(HaveKeyWithValue("summary", "High CPU usage"))
+			Expect(rule.Labels).To(HaveKeyWithValue("alert_rule_id", alertRuleId))
 		})
 
 		It("should return an error when the mapper cannot find the rule", func() {
@@ -116,9 +118,11 @@ var _ = Describe("GetRuleById", func() {
 			By("attempting to retrieve a nonexistent rule")
 			_, err := client.GetRuleById(ctx, alertRuleId)
 
-			By("verifying an error is returned")
+			By("verifying a NotFoundError is returned")
 			Expect(err).To(HaveOccurred())
-			Expect(err).To(Equal(ErrAlertRuleNotFound))
+			var notFoundErr *management.NotFoundError
+			Expect(errors.As(err, &notFoundErr)).To(BeTrue())
+			Expect(notFoundErr.Resource).To(Equal("AlertRule"))
 		})
 
 		It("should return an error when the PrometheusRule does not exist", func() {
@@ -183,4 +187,96 @@ var _ = Describe("GetRuleById", func() {
 			Expect(err.Error()).To(ContainSubstring("not found"))
 		})
 	})
+
+	Context("when the alert rule is cached by the mapper", func() {
+		It("should return the cached rule without calling the API server", func() {
+			mockMapper.GetAlertRuleByIdFunc = func(alertRuleId mapper.PrometheusAlertRuleId) (*mapper.CachedAlertRule, error) {
+				return &mapper.CachedAlertRule{
+					Rule: monitoringv1.Rule{
+						Alert:  "CachedAlert",
+						Expr:   intstr.FromString("up == 0"),
+						Labels: map[string]string{"severity": "warning"},
+					},
+					Location: mapper.AlertRuleLocation{
+						PrometheusRuleId: mapper.PrometheusRuleId{Namespace: "test-namespace", Name: "test-rule"},
+					},
+					ResourceVersion: "42",
+				}, nil
+			}
+			mockPR.GetFunc = func(ctx context.Context, namespace string, name string) (*monitoringv1.PrometheusRule, bool, error) {
+				Fail("the PrometheusRule should not be read from the API server")
+				return nil, false, nil
+			}
+
+			rule, resourceVersion, err := client.GetRuleByIdWithResourceVersion(ctx, "cached-id")
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(resourceVersion).To(Equal("42"))
+			Expect(rule.Alert).To(Equal("CachedAlert"))
+			Expect(rule.Labels).To(Equal(map[string]string{
+				"severity":      "warning",
+				"alert_rule_id": "cached-id",
+			}))
+		})
+	})
+
+	Context("when AlertRelabelConfigs change the labels of the rule", func() {
+		var realMapper mapper.Client
+
+		BeforeEach(func() {
+			realMapper = mapper.New(mockK8s)
+			client = management.NewWithCustomMapper(ctx, mockK8s, realMapper)
+
+			realMapper.AddPrometheusRule(&monitoringv1.PrometheusRule{
+				ObjectMeta: metav1.ObjectMeta{Name: "platform-rules", Namespace: "openshift-monitoring"},
+				Spec: monitoringv1.PrometheusRuleSpec{
+					Groups: []monitoringv1.RuleGroup{{
+						Name: "g1",
+						Rules: []monitoringv1.Rule{
+							{Alert: "KeptAlert", Expr: intstr.FromString("up == 0"), Labels: map[string]string{"severity": "critical", "tmp_debug": "x"}},
+							{Alert: "DroppedAlert", Expr: intstr.FromString("up == 0"), Labels: map[string]string{"severity": "info"}},
+						},
+					}},
+				},
+			})
+			realMapper.AddAlertRelabelConfig(&osmv1.AlertRelabelConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-wide", Namespace: "openshift-monitoring"},
+				Spec: osmv1.AlertRelabelConfigSpec{
+					Configs: []osmv1.RelabelConfig{
+						{Regex: "tmp_.*", Action: "LabelDrop"},
+						{SourceLabels: []osmv1.LabelName{"severity"}, Regex: "crit.*", TargetLabel: "severity", Replacement: "warning", Action: "Replace"},
+						{SourceLabels: []osmv1.LabelName{"alertname"}, Regex: "Dropped", Action: "Drop"},
+						{SourceLabels: []osmv1.LabelName{"alertname"}, Regex: "DroppedAlert", Action: "Drop"},
+					},
+				},
+			})
+		})
+
+		It("should apply every config, including the ones not matching on alertname", func() {
+			keptRule := monitoringv1.Rule{Alert: "KeptAlert", Expr: intstr.FromString("up == 0"), Labels: map[string]string{"severity": "critical", "tmp_debug": "x"}}
+			alertRuleId := string(realMapper.GetAlertingRuleId(&keptRule))
+
+			rule, err := client.GetRuleById(ctx, alertRuleId)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rule.Labels).To(Equal(map[string]string{
+				"severity":      "warning",
+				"alert_rule_id": alertRuleId,
+			}))
+
+			rules, err := client.ListRules(ctx, management.PrometheusRuleOptions{}, management.AlertRuleOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rules).To(HaveLen(1))
+			Expect(rules[0].Labels).To(Equal(rule.Labels))
+		})
+
+		It("should anchor regexes and drop the rules Alertmanager never receives", func() {
+			droppedRule := monitoringv1.Rule{Alert: "DroppedAlert", Expr: intstr.FromString("up == 0"), Labels: map[string]string{"severity": "info"}}
+
+			_, err := client.GetRuleById(ctx, string(realMapper.GetAlertingRuleId(&droppedRule)))
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("dropped by relabeling"))
+		})
+	})
 })
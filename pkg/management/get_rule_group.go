@@ -0,0 +1,31 @@
+package management
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func (c *client) GetRuleGroup(ctx context.Context, prId types.NamespacedName, groupName string) (RuleGroup, string, error) {
+	pr, found, err := c.k8sClient.PrometheusRules().Get(ctx, prId.Namespace, prId.Name)
+	if err != nil {
+		return RuleGroup{}, "", err
+	}
+
+	if !found {
+		return RuleGroup{}, "", &NotFoundError{Resource: "PrometheusRule", Id: fmt.Sprintf("%s/%s", prId.Namespace, prId.Name)}
+	}
+
+	idx := ruleGroupIndex(pr, groupName)
+	if idx < 0 {
+		return RuleGroup{}, "", ruleGroupNotFound(prId, groupName)
+	}
+
+	source, err := c.classify(ctx, pr)
+	if err != nil {
+		return RuleGroup{}, "", err
+	}
+
+	return ruleGroupFrom(pr, pr.Spec.Groups[idx], source), pr.ResourceVersion, nil
+}
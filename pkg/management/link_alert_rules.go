@@ -0,0 +1,111 @@
+package management
+
+import (
+	"context"
+	"strings"
+
+	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management/mapper"
+)
+
+// alertRuleIndex finds the cached alerting rule of an alert by its alertname
+// and the static labels of the rule
+type alertRuleIndex struct {
+	byAlertname map[string][]mapper.CachedAlertRule
+}
+
+func (c *client) newAlertRuleIndex() *alertRuleIndex {
+	index := &alertRuleIndex{byAlertname: make(map[string][]mapper.CachedAlertRule)}
+	for _, cached := range c.mapper.ListAlertRules(mapper.PrometheusRuleId{}) {
+		if cached.Rule.Alert == "" || cached.Id == "" {
+			continue
+		}
+		index.byAlertname[cached.Rule.Alert] = append(index.byAlertname[cached.Rule.Alert], cached)
+	}
+	return index
+}
+
+// find returns the rule that produced the alert, given its labels before
+// relabeling. Alerts carry the static labels of their rule and the stable ID
+// annotation of user-defined rules, which is not checked for alerts without
+// annotations, e.g. read from the ALERTS metric. If several rules match, the
+// rules of the PrometheusRule in the namespace of the alert are preferred, then
+// the rules with the most static labels, then the first rule by location
+func (idx *alertRuleIndex) find(alert k8s.PrometheusAlert) *mapper.CachedAlertRule {
+	var (
+		found            *mapper.CachedAlertRule
+		foundInNamespace bool
+		foundLabels      int
+	)
+
+	candidates := idx.byAlertname[alert.Labels["alertname"]]
+	for i := range candidates {
+		candidate := &candidates[i]
+
+		if id := candidate.Rule.Annotations[mapper.AlertRuleIdAnnotation]; id != "" && alert.Annotations != nil && alert.Annotations[mapper.AlertRuleIdAnnotation] != id {
+			continue
+		}
+
+		labels, ok := staticLabelsMatch(candidate.Rule.Labels, alert.Labels)
+		if !ok {
+			continue
+		}
+		inNamespace := candidate.Location.PrometheusRuleId.Namespace == alert.Labels["namespace"]
+
+		if found == nil || (inNamespace && !foundInNamespace) || (inNamespace == foundInNamespace && labels > foundLabels) {
+			found, foundInNamespace, foundLabels = candidate, inNamespace, labels
+		}
+	}
+
+	return found
+}
+
+// staticLabelsMatch returns whether the alert has the static labels of the
+// rule, and their number. Templated values depend on each alert and are skipped
+func staticLabelsMatch(ruleLabels map[string]string, alertLabels map[string]string) (int, bool) {
+	count := 0
+	for key, value := range ruleLabels {
+		if strings.Contains(value, "{{") {
+			continue
+		}
+		if alertLabels[key] != value {
+			return 0, false
+		}
+		count++
+	}
+	return count, true
+}
+
+// linkAlertRules sets the rule ID, PrometheusRule and source of the alerts
+// whose rule is found, and returns the location of the rule of each alert
+func (c *client) linkAlertRules(ctx context.Context, alerts []k8s.PrometheusAlert) ([]*mapper.AlertRuleLocation, error) {
+	index := c.newAlertRuleIndex()
+	sources := make(map[mapper.PrometheusRuleId]string)
+	locations := make([]*mapper.AlertRuleLocation, len(alerts))
+
+	for i := range alerts {
+		cached := index.find(alerts[i])
+		if cached == nil {
+			continue
+		}
+
+		prId := cached.Location.PrometheusRuleId
+		source, found := sources[prId]
+		if !found {
+			var err error
+			source, err = c.classify(ctx, &cached.PrometheusRuleMeta)
+			if err != nil {
+				return nil, err
+			}
+			sources[prId] = source
+		}
+
+		alerts[i].AlertRuleId = string(cached.Id)
+		alerts[i].PrometheusRuleNamespace = prId.Namespace
+		alerts[i].PrometheusRuleName = prId.Name
+		alerts[i].Source = source
+		locations[i] = &cached.Location
+	}
+
+	return locations, nil
+}
@@ -0,0 +1,51 @@
+package management
+
+import (
+	"context"
+	"fmt"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+)
+
+func (c *client) ListRuleGroups(ctx context.Context, prOptions PrometheusRuleOptions) ([]RuleGroup, error) {
+	if prOptions.Name != "" && prOptions.Namespace == "" {
+		return nil, &InvalidArgumentError{Message: "PrometheusRule Namespace must be specified when Name is provided"}
+	}
+
+	var prometheusRules []monitoringv1.PrometheusRule
+	if prOptions.Name != "" {
+		pr, found, err := c.k8sClient.PrometheusRules().Get(ctx, prOptions.Namespace, prOptions.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get PrometheusRule %s/%s: %w", prOptions.Namespace, prOptions.Name, err)
+		}
+		if !found {
+			return nil, &NotFoundError{Resource: "PrometheusRule", Id: fmt.Sprintf("%s/%s", prOptions.Namespace, prOptions.Name)}
+		}
+		prometheusRules = append(prometheusRules, *pr)
+	} else {
+		prs, err := c.k8sClient.PrometheusRules().List(ctx, prOptions.Namespace)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list PrometheusRules: %w", err)
+		}
+		prometheusRules = prs
+	}
+
+	var groups []RuleGroup
+	for i := range prometheusRules {
+		pr := &prometheusRules[i]
+
+		source, err := c.classify(ctx, pr)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, group := range pr.Spec.Groups {
+			if prOptions.GroupName != "" && group.Name != prOptions.GroupName {
+				continue
+			}
+			groups = append(groups, ruleGroupFrom(pr, group, source))
+		}
+	}
+
+	return groups, nil
+}
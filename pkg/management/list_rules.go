@@ -2,97 +2,88 @@ package management
 
 import (
 	"context"
-	"errors"
 	"fmt"
 
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
-	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/machadovilaca/alerts-ui-management/pkg/management/mapper"
 )
 
 const alertRuleIdLabel = "alert_rule_id"
 
-func (c *client) ListRules(ctx context.Context, prOptions PrometheusRuleOptions, arOptions AlertRuleOptions) ([]monitoringv1.Rule, error) {
+// ListRules lists the rules cached by the mapper, so that listing does not
+// read every PrometheusRule from the API server
+func (c *client) ListRules(ctx context.Context, prOptions PrometheusRuleOptions, arOptions AlertRuleOptions) ([]Rule, error) {
 	if prOptions.Name != "" && prOptions.Namespace == "" {
-		return nil, errors.New("PrometheusRule Namespace must be specified when Name is provided")
+		return nil, &InvalidArgumentError{Message: "PrometheusRule Namespace must be specified when Name is provided"}
 	}
 
-	// Name and Namespace specified
-	if prOptions.Name != "" && prOptions.Namespace != "" {
-		pr, found, err := c.k8sClient.PrometheusRules().Get(ctx, prOptions.Namespace, prOptions.Name)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get PrometheusRule %s/%s: %w", prOptions.Namespace, prOptions.Name, err)
-		}
-		if !found {
-			return nil, &NotFoundError{Resource: "PrometheusRule", Id: fmt.Sprintf("%s/%s", prOptions.Namespace, prOptions.Name)}
-		}
-		return c.extractAndFilterRules(*pr, &prOptions, &arOptions), nil
+	prId := mapper.PrometheusRuleId{Namespace: prOptions.Namespace, Name: prOptions.Name}
+	if prOptions.Name != "" && !c.mapper.HasPrometheusRule(prId) {
+		return nil, &NotFoundError{Resource: "PrometheusRule", Id: fmt.Sprintf("%s/%s", prOptions.Namespace, prOptions.Name)}
 	}
 
-	// Name not specified
-	allPrometheusRules, err := c.k8sClient.PrometheusRules().List(ctx, prOptions.Namespace)
+	// Rules are only filtered by their status if it can be retrieved
+	statuses, err := c.getRuleStatusIndex(ctx, arOptions.Loaded != nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list PrometheusRules: %w", err)
-	}
-
-	var allRules []monitoringv1.Rule
-	for _, pr := range allPrometheusRules {
-		rules := c.extractAndFilterRules(pr, &prOptions, &arOptions)
-		allRules = append(allRules, rules...)
+		return nil, err
 	}
 
-	return allRules, nil
-}
-
-func (c *client) extractAndFilterRules(pr monitoringv1.PrometheusRule, prOptions *PrometheusRuleOptions, arOptions *AlertRuleOptions) []monitoringv1.Rule {
-	var rules []monitoringv1.Rule
+	sources := make(map[mapper.PrometheusRuleId]string)
+	var rules []Rule
 
-	for _, group := range pr.Spec.Groups {
+	for _, cached := range c.mapper.ListAlertRules(prId) {
 		// Filter by group name if specified
-		if prOptions.GroupName != "" && group.Name != prOptions.GroupName {
+		if prOptions.GroupName != "" && cached.Location.GroupName != prOptions.GroupName {
 			continue
 		}
 
-		for _, rule := range group.Rules {
-			// Skip recording rules (only process alert rules)
-			if rule.Alert == "" {
-				continue
+		source, found := sources[cached.Location.PrometheusRuleId]
+		if !found {
+			var err error
+			source, err = c.classify(ctx, &cached.PrometheusRuleMeta)
+			if err != nil {
+				return nil, err
 			}
+			sources[cached.Location.PrometheusRuleId] = source
+		}
 
-			// Apply alert rule filters
-			if !c.matchesAlertRuleFilters(rule, pr, arOptions) {
+		// Apply alert rule filters
+		if !c.matchesAlertRuleFilters(cached.Rule, source, &arOptions) {
+			continue
+		}
+
+		var status *RuleStatus
+		if statuses != nil {
+			status = statuses.status(&cached.PrometheusRuleMeta, cached.Location, cached.Rule)
+			if arOptions.Loaded != nil && status.Loaded != *arOptions.Loaded {
 				continue
 			}
+		}
 
-			// Parse and update the rule based on relabeling configurations
-			r := c.parseRule(rule)
-			if r != nil {
-				rules = append(rules, *r)
-			}
+		// Parse and update the rule based on relabeling configurations
+		if r := c.parseRule(cached); r != nil {
+			rules = append(rules, Rule{Rule: *r, Type: ruleType(cached.Rule), Source: source, Status: status})
 		}
 	}
 
-	return rules
+	return rules, nil
 }
 
-func (c *client) matchesAlertRuleFilters(rule monitoringv1.Rule, pr monitoringv1.PrometheusRule, arOptions *AlertRuleOptions) bool {
-	// Filter by alert name
-	if arOptions.Name != "" && string(rule.Alert) != arOptions.Name {
+func (c *client) matchesAlertRuleFilters(rule monitoringv1.Rule, source string, arOptions *AlertRuleOptions) bool {
+	// Filter by alert or recorded metric name
+	if arOptions.Name != "" && rule.Alert != arOptions.Name && rule.Record != arOptions.Name {
 		return false
 	}
 
-	// Filter by source (platform or user-defined)
-	if arOptions.Source != "" {
-		prId := types.NamespacedName{Name: pr.Name, Namespace: pr.Namespace}
-		isPlatform := IsPlatformAlertRule(prId)
+	// Filter by rule type (alerting or recording)
+	if arOptions.Type != "" && arOptions.Type != ruleType(rule) {
+		return false
+	}
 
-		if arOptions.Source == "platform" && !isPlatform {
-			return false
-		}
-		if arOptions.Source == "user-defined" && isPlatform {
-			return false
-		}
+	// Filter by source (platform or user-defined)
+	if arOptions.Source != "" && arOptions.Source != source {
+		return false
 	}
 
 	// Filter by labels
@@ -108,18 +99,15 @@ func (c *client) matchesAlertRuleFilters(rule monitoringv1.Rule, pr monitoringv1
 	return true
 }
 
-func (c *client) parseRule(rule monitoringv1.Rule) *monitoringv1.Rule {
-	alertRuleId := c.mapper.GetAlertingRuleId(&rule)
-	if alertRuleId == "" {
+// parseRule returns the rule as served to callers, labeled with its ID. Rules
+// sharing their ID with other rules are labeled with the ID qualified with the
+// rule location, so that each rule can be referenced
+func (c *client) parseRule(cached mapper.CachedAlertRule) *monitoringv1.Rule {
+	if cached.Id == "" {
 		return nil
 	}
 
-	_, err := c.mapper.FindAlertRuleById(mapper.PrometheusAlertRuleId(alertRuleId))
-	if err != nil {
-		return nil
-	}
-
-	rule, err = c.updateRuleBasedOnRelabelConfig(&rule)
+	rule, err := c.updateRuleBasedOnRelabelConfig(&cached.Rule)
 	if err != nil {
 		return nil
 	}
@@ -127,7 +115,14 @@ func (c *client) parseRule(rule monitoringv1.Rule) *monitoringv1.Rule {
 	if rule.Labels == nil {
 		rule.Labels = make(map[string]string)
 	}
-	rule.Labels[alertRuleIdLabel] = string(alertRuleId)
+	rule.Labels[alertRuleIdLabel] = string(cached.Id)
 
 	return &rule
 }
+
+func ruleType(rule monitoringv1.Rule) string {
+	if rule.Record != "" {
+		return RuleTypeRecording
+	}
+	return RuleTypeAlerting
+}
@@ -2,6 +2,7 @@ package management_test
 
 import (
 	"context"
+	"errors"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -12,30 +13,46 @@ import (
 
 	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
 	"github.com/machadovilaca/alerts-ui-management/pkg/management"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management/mapper"
 	"github.com/machadovilaca/alerts-ui-management/pkg/management/testutils"
 )
 
 var _ = Describe("ListRules", func() {
 	var (
-		ctx        context.Context
-		mockK8s    *testutils.MockClient
-		mockPR     *testutils.MockPrometheusRuleInterface
-		mockMapper *testutils.MockMapperClient
-		client     management.Client
+		ctx                context.Context
+		mockK8s            *testutils.MockClient
+		mockPR             *testutils.MockPrometheusRuleInterface
+		client             management.Client
+		setPrometheusRules func(prometheusRules map[string]*monitoringv1.PrometheusRule)
 	)
 
 	BeforeEach(func() {
 		ctx = context.Background()
 
-		mockPR = &testutils.MockPrometheusRuleInterface{}
+		mockPR = &testutils.MockPrometheusRuleInterface{
+			ListFunc: func(ctx context.Context, namespace string) ([]monitoringv1.PrometheusRule, error) {
+				Fail("the PrometheusRules should not be listed from the API server")
+				return nil, nil
+			},
+			GetFunc: func(ctx context.Context, namespace string, name string) (*monitoringv1.PrometheusRule, bool, error) {
+				Fail("the PrometheusRule should not be read from the API server")
+				return nil, false, nil
+			},
+		}
 		mockK8s = &testutils.MockClient{
 			PrometheusRulesFunc: func() k8s.PrometheusRuleInterface {
 				return mockPR
 			},
 		}
-		mockMapper = &testutils.MockMapperClient{}
 
-		client = management.NewWithCustomMapper(ctx, mockK8s, mockMapper)
+		realMapper := mapper.New(mockK8s)
+		client = management.NewWithCustomMapper(ctx, mockK8s, realMapper)
+
+		setPrometheusRules = func(prometheusRules map[string]*monitoringv1.PrometheusRule) {
+			for _, pr := range prometheusRules {
+				realMapper.AddPrometheusRule(pr)
+			}
+		}
 	})
 
 	It("should list rules from a specific PrometheusRule", func() {
@@ -59,7 +76,7 @@ var _ = Describe("ListRules", func() {
 			},
 		}
 
-		mockPR.SetPrometheusRules(map[string]*monitoringv1.PrometheusRule{
+		setPrometheusRules(map[string]*monitoringv1.PrometheusRule{
 			"test-namespace/test-rule": prometheusRule,
 		})
 
@@ -118,7 +135,7 @@ var _ = Describe("ListRules", func() {
 			},
 		}
 
-		mockPR.SetPrometheusRules(map[string]*monitoringv1.PrometheusRule{
+		setPrometheusRules(map[string]*monitoringv1.PrometheusRule{
 			"namespace1/rule1": prometheusRule1,
 			"namespace2/rule2": prometheusRule2,
 		})
@@ -200,7 +217,7 @@ var _ = Describe("ListRules", func() {
 			},
 		}
 
-		mockPR.SetPrometheusRules(map[string]*monitoringv1.PrometheusRule{
+		setPrometheusRules(map[string]*monitoringv1.PrometheusRule{
 			"target-namespace/rule1": prometheusRule1,
 			"target-namespace/rule2": prometheusRule2,
 			"other-namespace/rule3":  prometheusRule3,
@@ -221,6 +238,33 @@ var _ = Describe("ListRules", func() {
 		Expect(alertNames).ToNot(ContainElement("OtherNamespaceAlert"))
 	})
 
+	It("should return NotFoundError when the PrometheusRule is not cached", func() {
+		_, err := client.ListRules(ctx, management.PrometheusRuleOptions{Name: "missing", Namespace: "test-namespace"}, management.AlertRuleOptions{})
+
+		var notFoundErr *management.NotFoundError
+		Expect(errors.As(err, &notFoundErr)).To(BeTrue())
+		Expect(notFoundErr.Resource).To(Equal("PrometheusRule"))
+	})
+
+	It("should qualify the IDs of the rules sharing their ID", func() {
+		duplicated := monitoringv1.Rule{Alert: "Duplicated", Expr: intstr.FromString("up == 0")}
+		setPrometheusRules(map[string]*monitoringv1.PrometheusRule{
+			"test-namespace/test-rule": {
+				ObjectMeta: metav1.ObjectMeta{Name: "test-rule", Namespace: "test-namespace"},
+				Spec: monitoringv1.PrometheusRuleSpec{
+					Groups: []monitoringv1.RuleGroup{{Name: "g1", Rules: []monitoringv1.Rule{duplicated, duplicated}}},
+				},
+			},
+		})
+
+		rules, err := client.ListRules(ctx, management.PrometheusRuleOptions{}, management.AlertRuleOptions{})
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rules).To(HaveLen(2))
+		Expect(rules[0].Labels["alert_rule_id"]).To(HaveSuffix("@test-namespace/test-rule/0/0"))
+		Expect(rules[1].Labels["alert_rule_id"]).To(HaveSuffix("@test-namespace/test-rule/0/1"))
+	})
+
 	Context("AlertRuleOptions filtering", func() {
 		var prometheusRule *monitoringv1.PrometheusRule
 
@@ -265,7 +309,7 @@ var _ = Describe("ListRules", func() {
 				},
 			}
 
-			mockPR.SetPrometheusRules(map[string]*monitoringv1.PrometheusRule{
+			setPrometheusRules(map[string]*monitoringv1.PrometheusRule{
 				"monitoring/test-alerts": prometheusRule,
 			})
 		})
@@ -354,7 +398,7 @@ var _ = Describe("ListRules", func() {
 				},
 			}
 
-			mockPR.SetPrometheusRules(map[string]*monitoringv1.PrometheusRule{
+			setPrometheusRules(map[string]*monitoringv1.PrometheusRule{
 				"monitoring/test-alerts":                         prometheusRule,
 				"openshift-monitoring/openshift-platform-alerts": platformRule,
 			})
@@ -369,6 +413,7 @@ var _ = Describe("ListRules", func() {
 			Expect(err).ToNot(HaveOccurred())
 			Expect(rules).To(HaveLen(1))
 			Expect(rules[0].Alert).To(Equal("PlatformAlert"))
+			Expect(rules[0].Source).To(Equal(management.SourcePlatform))
 		})
 
 		It("should filter by source user-defined", func() {
@@ -392,7 +437,7 @@ var _ = Describe("ListRules", func() {
 				},
 			}
 
-			mockPR.SetPrometheusRules(map[string]*monitoringv1.PrometheusRule{
+			setPrometheusRules(map[string]*monitoringv1.PrometheusRule{
 				"monitoring/test-alerts":                         prometheusRule,
 				"openshift-monitoring/openshift-platform-alerts": platformRule,
 			})
@@ -0,0 +1,28 @@
+package management
+
+import (
+	"context"
+
+	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
+)
+
+func (c *client) ListSilences(ctx context.Context, req k8s.ListSilencesRequest) ([]k8s.Silence, error) {
+	silences, err := c.k8sClient.AlertmanagerSilences().List(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return silences, nil
+}
+
+func (c *client) GetSilence(ctx context.Context, silenceId string) (k8s.Silence, error) {
+	silence, found, err := c.k8sClient.AlertmanagerSilences().Get(ctx, silenceId)
+	if err != nil {
+		return k8s.Silence{}, err
+	}
+
+	if !found {
+		return k8s.Silence{}, &NotFoundError{Resource: "Silence", Id: silenceId}
+	}
+
+	return *silence, nil
+}
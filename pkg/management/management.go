@@ -1,19 +1,35 @@
 package management
 
 import (
-	"strings"
-
-	"k8s.io/apimachinery/pkg/types"
+	"context"
+	"fmt"
+	"sync"
+	"time"
 
 	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
 	"github.com/machadovilaca/alerts-ui-management/pkg/management/mapper"
 )
 
 type client struct {
-	k8sClient k8s.Client
-	mapper    mapper.Client
+	k8sClient  k8s.Client
+	mapper     mapper.Client
+	classifier PlatformClassifier
+
+	mu                sync.RWMutex
+	lastAlertsSuccess time.Time
 }
 
-func IsPlatformAlertRule(prId types.NamespacedName) bool {
-	return strings.HasPrefix(prId.Namespace, "openshift-")
+// k8sClientFor returns the Kubernetes client used to perform the writes of a
+// request, impersonating the authenticated user in ctx if there is one
+func (c *client) k8sClientFor(ctx context.Context) (k8s.Client, error) {
+	user, ok := k8s.UserFrom(ctx)
+	if !ok {
+		return c.k8sClient, nil
+	}
+
+	k8sClient, err := c.k8sClient.Impersonate(user)
+	if err != nil {
+		return nil, fmt.Errorf("failed to impersonate user %s: %w", user.Username, err)
+	}
+	return k8sClient, nil
 }
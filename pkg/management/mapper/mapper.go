@@ -2,10 +2,11 @@ package mapper
 
 import (
 	"context"
+	"crypto/rand"
 	"crypto/sha256"
 	"fmt"
 	"log"
-	"regexp"
+	"maps"
 	"slices"
 	"sort"
 	"strings"
@@ -13,6 +14,7 @@ import (
 
 	osmv1 "github.com/openshift/api/monitoring/v1"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
@@ -23,12 +25,57 @@ type mapper struct {
 	mu        sync.RWMutex
 
 	prometheusRules     map[PrometheusRuleId][]PrometheusAlertRuleId
+	alertRules          map[PrometheusAlertRuleId][]*CachedAlertRule
 	alertRelabelConfigs map[AlertRelabelConfigId][]osmv1.RelabelConfig
 }
 
 var _ Client = (*mapper)(nil)
 
+// AlertRuleIdAnnotation is the rule annotation holding the stable ID of user-defined alert rules
+const AlertRuleIdAnnotation = "alert_rule_id"
+
+// randomIdLength is the length of the random part of stable IDs, followed by the checksum of their namespace
+const randomIdLength = 32
+
+// NewAlertingRuleId generates a random stable ID for a new alert rule in the namespace. The ID ends with a
+// checksum of the namespace, so that the rule is told apart from copies of its ID in other namespaces
+func NewAlertingRuleId(namespace string) (PrometheusAlertRuleId, error) {
+	id := make([]byte, randomIdLength/2)
+	if _, err := rand.Read(id); err != nil {
+		return "", fmt.Errorf("failed to generate alert rule id: %w", err)
+	}
+
+	randomId := fmt.Sprintf("%x", id)
+	return PrometheusAlertRuleId(randomId + namespaceChecksum(namespace, randomId)), nil
+}
+
+func namespaceChecksum(namespace string, randomId string) string {
+	sum := sha256.Sum256([]byte(namespace + "/" + randomId))
+	return fmt.Sprintf("%x", sum[:4])
+}
+
+// isGeneratedFor returns true if the stable ID was generated for a rule in the namespace
+func isGeneratedFor(alertRuleId PrometheusAlertRuleId, namespace string) bool {
+	id := string(alertRuleId)
+	if len(id) <= randomIdLength {
+		return false
+	}
+	return id[randomIdLength:] == namespaceChecksum(namespace, id[:randomIdLength])
+}
+
 func (m *mapper) GetAlertingRuleId(alertRule *monitoringv1.Rule) PrometheusAlertRuleId {
+	if alertRule.Alert == "" && alertRule.Record == "" {
+		return ""
+	}
+
+	if id := alertRule.Annotations[AlertRuleIdAnnotation]; id != "" {
+		return PrometheusAlertRuleId(id)
+	}
+
+	return m.GetContentAlertingRuleId(alertRule)
+}
+
+func (m *mapper) GetContentAlertingRuleId(alertRule *monitoringv1.Rule) PrometheusAlertRuleId {
 	var kind, name string
 	if alertRule.Alert != "" {
 		kind = "alert"
@@ -57,6 +104,11 @@ func (m *mapper) GetAlertingRuleId(alertRule *monitoringv1.Rule) PrometheusAlert
 	var sortedAnnotations []string
 	if alertRule.Annotations != nil {
 		for key, value := range alertRule.Annotations {
+			// The stable ID is not part of the content, so that rules keep the
+			// hash-based ID they had before being assigned one
+			if key == AlertRuleIdAnnotation {
+				continue
+			}
 			sortedAnnotations = append(sortedAnnotations, fmt.Sprintf("%s=%s", key, value))
 		}
 		sort.Strings(sortedAnnotations)
@@ -78,18 +130,191 @@ func (m *mapper) GetAlertingRuleId(alertRule *monitoringv1.Rule) PrometheusAlert
 	return PrometheusAlertRuleId(fmt.Sprintf("%s/%x", name, hash))
 }
 
+func (m *mapper) MatchesAlertingRuleId(alertRule *monitoringv1.Rule, alertRuleId PrometheusAlertRuleId) bool {
+	alertRuleId, _ = ParseAlertingRuleId(alertRuleId)
+	if alertRuleId == "" {
+		return false
+	}
+
+	return m.GetAlertingRuleId(alertRule) == alertRuleId || m.GetContentAlertingRuleId(alertRule) == alertRuleId
+}
+
 func (m *mapper) FindAlertRuleById(alertRuleId PrometheusAlertRuleId) (*PrometheusRuleId, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	for id, rules := range m.prometheusRules {
-		if slices.Contains(rules, alertRuleId) {
-			return &id, nil
+	cached, err := m.findAlertRule(alertRuleId)
+	if err != nil {
+		return nil, err
+	}
+
+	prId := cached.Location.PrometheusRuleId
+	return &prId, nil
+}
+
+func (m *mapper) GetAlertRuleById(alertRuleId PrometheusAlertRuleId) (*CachedAlertRule, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	cached, err := m.findAlertRule(alertRuleId)
+	if err != nil {
+		return nil, err
+	}
+
+	rule := m.copyAlertRule(cached)
+	return &rule, nil
+}
+
+func (m *mapper) GetUniqueAlertingRuleId(alertRule *monitoringv1.Rule, location AlertRuleLocation) PrometheusAlertRuleId {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.uniqueAlertingRuleId(alertRule, location)
+}
+
+func (m *mapper) ListAlertRules(prometheusRuleId PrometheusRuleId) []CachedAlertRule {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var rules []CachedAlertRule
+	for promRuleId, ruleIds := range m.prometheusRules {
+		if (prometheusRuleId.Namespace != "" && prometheusRuleId.Namespace != promRuleId.Namespace) ||
+			(prometheusRuleId.Name != "" && prometheusRuleId.Name != promRuleId.Name) {
+			continue
+		}
+
+		// Rules are indexed by both their stable and hash-based IDs
+		seen := make(map[*CachedAlertRule]bool)
+		for _, ruleId := range ruleIds {
+			for _, cached := range m.alertRules[ruleId] {
+				if cached.Location.PrometheusRuleId != promRuleId || seen[cached] {
+					continue
+				}
+				seen[cached] = true
+				rules = append(rules, m.copyAlertRule(cached))
+			}
 		}
 	}
 
-	// If the PrometheusRuleId is not found, return an error
-	return nil, fmt.Errorf("alert rule with id %s not found", alertRuleId)
+	sort.Slice(rules, func(i, j int) bool {
+		a, b := rules[i].Location, rules[j].Location
+		if a.PrometheusRuleId != b.PrometheusRuleId {
+			if a.PrometheusRuleId.Namespace != b.PrometheusRuleId.Namespace {
+				return a.PrometheusRuleId.Namespace < b.PrometheusRuleId.Namespace
+			}
+			return a.PrometheusRuleId.Name < b.PrometheusRuleId.Name
+		}
+		if a.GroupIndex != b.GroupIndex {
+			return a.GroupIndex < b.GroupIndex
+		}
+		return a.RuleIndex < b.RuleIndex
+	})
+
+	return rules
+}
+
+func (m *mapper) HasPrometheusRule(prometheusRuleId PrometheusRuleId) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	_, found := m.prometheusRules[prometheusRuleId]
+	return found
+}
+
+// copyAlertRule returns a deep copy of a cached rule with its unique ID
+// Must be called with the lock held
+func (m *mapper) copyAlertRule(cached *CachedAlertRule) CachedAlertRule {
+	return CachedAlertRule{
+		Id:                 m.uniqueAlertingRuleId(&cached.Rule, cached.Location),
+		Rule:               *cached.Rule.DeepCopy(),
+		Location:           cached.Location,
+		ResourceVersion:    cached.ResourceVersion,
+		PrometheusRuleMeta: *cached.PrometheusRuleMeta.DeepCopy(),
+	}
+}
+
+// uniqueAlertingRuleId returns the ID of an alerting rule, qualified with its
+// location if other rules have the same ID or the ID resolves to another rule
+// Must be called with the lock held
+func (m *mapper) uniqueAlertingRuleId(alertRule *monitoringv1.Rule, location AlertRuleLocation) PrometheusAlertRuleId {
+	alertRuleId := m.GetAlertingRuleId(alertRule)
+
+	candidates := m.resolveAlertRules(alertRuleId)
+	if len(candidates) > 1 || (len(candidates) == 1 && !sameRuleLocation(candidates[0].Location, location)) {
+		return QualifiedAlertingRuleId(alertRuleId, location)
+	}
+	return alertRuleId
+}
+
+func sameRuleLocation(a AlertRuleLocation, b AlertRuleLocation) bool {
+	return a.PrometheusRuleId == b.PrometheusRuleId && a.GroupIndex == b.GroupIndex && a.RuleIndex == b.RuleIndex
+}
+
+// resolveAlertRules returns the cached rules with the given ID. Rules resolved
+// by their legacy hash-based ID are only returned if no rule has it as its own
+// ID, and rules with a stable ID generated for another namespace are only
+// returned if no rule is in the namespace the ID was generated for, so that
+// copies of a rule do not make the ID of the original ambiguous
+// Must be called with the lock held
+func (m *mapper) resolveAlertRules(alertRuleId PrometheusAlertRuleId) []*CachedAlertRule {
+	candidates := m.alertRules[alertRuleId]
+	if len(candidates) < 2 {
+		return candidates
+	}
+
+	var owned, generated []*CachedAlertRule
+	for _, cached := range candidates {
+		if m.GetAlertingRuleId(&cached.Rule) != alertRuleId {
+			continue
+		}
+		owned = append(owned, cached)
+		if isGeneratedFor(alertRuleId, cached.Location.PrometheusRuleId.Namespace) {
+			generated = append(generated, cached)
+		}
+	}
+
+	switch {
+	case len(generated) > 0:
+		return generated
+	case len(owned) > 0:
+		return owned
+	}
+	return candidates
+}
+
+// findAlertRule returns the cached rule with the given ID, which must be
+// qualified with the rule location if several rules have the same ID
+// Must be called with the lock held
+func (m *mapper) findAlertRule(alertRuleId PrometheusAlertRuleId) (*CachedAlertRule, error) {
+	baseId, location := ParseAlertingRuleId(alertRuleId)
+	candidates := m.alertRules[baseId]
+
+	if location != nil {
+		for _, cached := range candidates {
+			if cached.Location.PrometheusRuleId == location.PrometheusRuleId &&
+				cached.Location.GroupIndex == location.GroupIndex && cached.Location.RuleIndex == location.RuleIndex {
+				return cached, nil
+			}
+		}
+		return nil, fmt.Errorf("alert rule with id %s not found", alertRuleId)
+	}
+
+	candidates = m.resolveAlertRules(baseId)
+	switch len(candidates) {
+	case 0:
+		return nil, fmt.Errorf("alert rule with id %s not found", alertRuleId)
+	case 1:
+		return candidates[0], nil
+	}
+
+	ambiguousErr := &AmbiguousAlertRuleIdError{Id: alertRuleId}
+	for _, cached := range candidates {
+		ambiguousErr.Candidates = append(ambiguousErr.Candidates, QualifiedAlertingRuleId(baseId, cached.Location))
+	}
+	sort.Slice(ambiguousErr.Candidates, func(i, j int) bool {
+		return ambiguousErr.Candidates[i] < ambiguousErr.Candidates[j]
+	})
+	return nil, ambiguousErr
 }
 
 func (m *mapper) WatchPrometheusRules(ctx context.Context) {
@@ -118,16 +343,45 @@ func (m *mapper) AddPrometheusRule(pr *monitoringv1.PrometheusRule) {
 	defer m.mu.Unlock()
 
 	promRuleId := PrometheusRuleId(types.NamespacedName{Namespace: pr.Namespace, Name: pr.Name})
-	delete(m.prometheusRules, promRuleId)
+	m.removePrometheusRule(promRuleId)
+
+	prMeta := metav1.ObjectMeta{
+		Namespace:       pr.Namespace,
+		Name:            pr.Name,
+		UID:             pr.UID,
+		Labels:          maps.Clone(pr.Labels),
+		OwnerReferences: slices.Clone(pr.OwnerReferences),
+	}
 
 	rules := make([]PrometheusAlertRuleId, 0)
-	for _, group := range pr.Spec.Groups {
-		for _, rule := range group.Rules {
-			if rule.Alert != "" {
-				ruleId := m.GetAlertingRuleId(&rule)
-				if ruleId != "" {
-					rules = append(rules, ruleId)
-				}
+	for groupIdx, group := range pr.Spec.Groups {
+		for ruleIdx, rule := range group.Rules {
+			if rule.Alert == "" && rule.Record == "" {
+				continue
+			}
+
+			cached := &CachedAlertRule{
+				Rule: *rule.DeepCopy(),
+				Location: AlertRuleLocation{
+					PrometheusRuleId: promRuleId,
+					GroupName:        group.Name,
+					GroupIndex:       groupIdx,
+					RuleIndex:        ruleIdx,
+				},
+				ResourceVersion:    pr.ResourceVersion,
+				PrometheusRuleMeta: prMeta,
+			}
+
+			ruleId := m.GetAlertingRuleId(&rule)
+			if ruleId != "" {
+				rules = append(rules, ruleId)
+				m.alertRules[ruleId] = append(m.alertRules[ruleId], cached)
+			}
+
+			// Rules with a stable ID are also resolved by their legacy hash-based ID
+			if contentId := m.GetContentAlertingRuleId(&rule); contentId != ruleId {
+				rules = append(rules, contentId)
+				m.alertRules[contentId] = append(m.alertRules[contentId], cached)
 			}
 		}
 	}
@@ -139,7 +393,23 @@ func (m *mapper) DeletePrometheusRule(pr *monitoringv1.PrometheusRule) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	delete(m.prometheusRules, PrometheusRuleId(types.NamespacedName{Namespace: pr.Namespace, Name: pr.Name}))
+	m.removePrometheusRule(PrometheusRuleId(types.NamespacedName{Namespace: pr.Namespace, Name: pr.Name}))
+}
+
+// removePrometheusRule removes a PrometheusRule and its rules from the index
+// It must be called with the lock held
+func (m *mapper) removePrometheusRule(promRuleId PrometheusRuleId) {
+	for _, ruleId := range m.prometheusRules[promRuleId] {
+		remaining := slices.DeleteFunc(m.alertRules[ruleId], func(cached *CachedAlertRule) bool {
+			return cached.Location.PrometheusRuleId == promRuleId
+		})
+		if len(remaining) == 0 {
+			delete(m.alertRules, ruleId)
+		} else {
+			m.alertRules[ruleId] = remaining
+		}
+	}
+	delete(m.prometheusRules, promRuleId)
 }
 
 func (m *mapper) WatchAlertRelabelConfigs(ctx context.Context) {
@@ -163,55 +433,29 @@ func (m *mapper) WatchAlertRelabelConfigs(ctx context.Context) {
 	}()
 }
 
+func (m *mapper) HasSynced() bool {
+	return m.k8sClient.PrometheusRuleInformer().HasSynced() &&
+		m.k8sClient.AlertRelabelConfigInformer().HasSynced()
+}
+
+func (m *mapper) WaitForCacheSync(ctx context.Context) bool {
+	return m.k8sClient.PrometheusRuleInformer().WaitForCacheSync(ctx) &&
+		m.k8sClient.AlertRelabelConfigInformer().WaitForCacheSync(ctx)
+}
+
 func (m *mapper) AddAlertRelabelConfig(arc *osmv1.AlertRelabelConfig) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	arcId := AlertRelabelConfigId(types.NamespacedName{Namespace: arc.Namespace, Name: arc.Name})
 
-	// Clean up old entries
-	delete(m.alertRelabelConfigs, arcId)
-
-	configs := make([]osmv1.RelabelConfig, 0)
-
-	for _, config := range arc.Spec.Configs {
-		if slices.Contains(config.SourceLabels, "alertname") {
-			alertname := parseAlertnameFromRelabelConfig(config)
-			if alertname != "" {
-				configs = append(configs, config)
-			}
-		}
+	// All configs are kept in order, as any of them can change the labels
+	// Alertmanager receives, whether or not they match on alertname
+	if len(arc.Spec.Configs) == 0 {
+		delete(m.alertRelabelConfigs, arcId)
+		return
 	}
-
-	if len(configs) > 0 {
-		m.alertRelabelConfigs[arcId] = configs
-	}
-}
-
-func parseAlertnameFromRelabelConfig(config osmv1.RelabelConfig) string {
-	separator := config.Separator
-	if separator == "" {
-		separator = ";"
-	}
-
-	regex := config.Regex
-	if regex == "" {
-		return ""
-	}
-
-	values := strings.Split(regex, separator)
-	if len(values) != len(config.SourceLabels) {
-		return ""
-	}
-
-	// Find the alertname value from source labels
-	for i, labelName := range config.SourceLabels {
-		if string(labelName) == "alertname" {
-			return values[i]
-		}
-	}
-
-	return ""
+	m.alertRelabelConfigs[arcId] = slices.Clone(arc.Spec.Configs)
 }
 
 func (m *mapper) DeleteAlertRelabelConfig(arc *osmv1.AlertRelabelConfig) {
@@ -226,61 +470,32 @@ func (m *mapper) GetAlertRelabelConfigSpec(alertRule *monitoringv1.Rule) []osmv1
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	if alertRule == nil {
+	// AlertRelabelConfigs only apply to alerts, recording rules are never relabeled
+	if alertRule == nil || alertRule.Alert == "" {
 		return nil
 	}
 
-	var matchingConfigs []osmv1.RelabelConfig
-
-	// Iterate through all AlertRelabelConfigs
-	for _, configs := range m.alertRelabelConfigs {
-		for _, config := range configs {
-			if m.configMatchesAlert(config, alertRule) {
-				matchingConfigs = append(matchingConfigs, config)
-			}
-		}
-	}
-
-	return matchingConfigs
-}
-
-// configMatchesAlert checks if a RelabelConfig matches the given alert rule's labels
-func (m *mapper) configMatchesAlert(config osmv1.RelabelConfig, alertRule *monitoringv1.Rule) bool {
-	separator := config.Separator
-	if separator == "" {
-		separator = ";"
+	// Relabel configs are applied in order, so AlertRelabelConfigs are
+	// iterated by namespace and name to keep the result deterministic
+	arcIds := make([]AlertRelabelConfigId, 0, len(m.alertRelabelConfigs))
+	for arcId := range m.alertRelabelConfigs {
+		arcIds = append(arcIds, arcId)
 	}
-
-	var labelValues []string
-	for _, labelName := range config.SourceLabels {
-		labelValue := ""
-
-		if string(labelName) == "alertname" {
-			if alertRule.Alert != "" {
-				labelValue = alertRule.Alert
-			}
-		} else {
-			if alertRule.Labels != nil {
-				if val, exists := alertRule.Labels[string(labelName)]; exists {
-					labelValue = val
-				}
-			}
+	sort.Slice(arcIds, func(i, j int) bool {
+		if arcIds[i].Namespace != arcIds[j].Namespace {
+			return arcIds[i].Namespace < arcIds[j].Namespace
+		}
+		return arcIds[i].Name < arcIds[j].Name
+	})
+
+	// Whether a config matches the alert is decided when the configs are
+	// applied, following the Prometheus relabeling semantics
+	var configs []osmv1.RelabelConfig
+	for _, arcId := range arcIds {
+		for _, config := range m.alertRelabelConfigs[arcId] {
+			configs = append(configs, *config.DeepCopy())
 		}
-
-		labelValues = append(labelValues, labelValue)
-	}
-
-	ruleLabels := strings.Join(labelValues, separator)
-
-	regex := config.Regex
-	if regex == "" {
-		regex = "(.*)"
-	}
-
-	matched, err := regexp.MatchString(regex, ruleLabels)
-	if err != nil {
-		return false
 	}
 
-	return matched
+	return configs
 }
@@ -1,6 +1,9 @@
 package mapper_test
 
 import (
+	"context"
+	"errors"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
@@ -10,6 +13,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
+	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
 	"github.com/machadovilaca/alerts-ui-management/pkg/management/mapper"
 	"github.com/machadovilaca/alerts-ui-management/pkg/management/testutils"
 )
@@ -116,6 +120,86 @@ var _ = Describe("Mapper", func() {
 		})
 	})
 
+	Describe("Stable alert rule IDs", func() {
+		var (
+			stableRule   monitoringv1.Rule
+			legacyId     mapper.PrometheusAlertRuleId
+			stableRuleId mapper.PrometheusAlertRuleId
+		)
+
+		BeforeEach(func() {
+			var err error
+			stableRuleId, err = mapper.NewAlertingRuleId("test-namespace")
+			Expect(err).ToNot(HaveOccurred())
+
+			legacyId = mapperClient.GetAlertingRuleId(&monitoringv1.Rule{
+				Alert:       "TestAlert",
+				Expr:        intstr.FromString("up == 0"),
+				Annotations: map[string]string{"summary": "Test"},
+			})
+
+			stableRule = monitoringv1.Rule{
+				Alert: "TestAlert",
+				Expr:  intstr.FromString("up == 0"),
+				Annotations: map[string]string{
+					"summary":                    "Test",
+					mapper.AlertRuleIdAnnotation: string(stableRuleId),
+				},
+			}
+		})
+
+		It("should use the stable ID annotation as the rule ID", func() {
+			Expect(mapperClient.GetAlertingRuleId(&stableRule)).To(Equal(stableRuleId))
+		})
+
+		It("should keep the stable ID when the rule is edited", func() {
+			stableRule.Expr = intstr.FromString("up == 1")
+			stableRule.Annotations["summary"] = "Fixed typo"
+
+			Expect(mapperClient.GetAlertingRuleId(&stableRule)).To(Equal(stableRuleId))
+		})
+
+		It("should ignore the stable ID annotation in the content ID", func() {
+			Expect(mapperClient.GetContentAlertingRuleId(&stableRule)).To(Equal(legacyId))
+		})
+
+		It("should resolve both the stable ID and the legacy hash-based ID", func() {
+			pr := createPrometheusRule("test-namespace", "test-rule", []monitoringv1.Rule{stableRule})
+			mapperClient.AddPrometheusRule(pr)
+
+			for _, id := range []mapper.PrometheusAlertRuleId{stableRuleId, legacyId} {
+				prId, err := mapperClient.FindAlertRuleById(id)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(prId.Namespace).To(Equal("test-namespace"))
+				Expect(prId.Name).To(Equal("test-rule"))
+
+				Expect(mapperClient.MatchesAlertingRuleId(&stableRule, id)).To(BeTrue())
+			}
+		})
+
+		It("should resolve the legacy hash-based ID to the rule without a stable ID", func() {
+			legacyRule := stableRule.DeepCopy()
+			delete(legacyRule.Annotations, mapper.AlertRuleIdAnnotation)
+
+			mapperClient.AddPrometheusRule(createPrometheusRule("openshift-monitoring", "platform-rule", []monitoringv1.Rule{*legacyRule}))
+			mapperClient.AddPrometheusRule(createPrometheusRule("test-namespace", "copied-rule", []monitoringv1.Rule{stableRule}))
+
+			prId, err := mapperClient.FindAlertRuleById(legacyId)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(prId.Namespace).To(Equal("openshift-monitoring"))
+			Expect(mapperClient.GetUniqueAlertingRuleId(legacyRule, mapper.AlertRuleLocation{
+				PrometheusRuleId: mapper.PrometheusRuleId{Namespace: "openshift-monitoring", Name: "platform-rule"},
+				GroupName:        "test-group",
+			})).To(Equal(legacyId))
+		})
+
+		It("should generate unique stable IDs", func() {
+			otherId, err := mapper.NewAlertingRuleId("test-namespace")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(otherId).ToNot(Equal(stableRuleId))
+		})
+	})
+
 	Describe("FindAlertRuleById", func() {
 		Context("when the alert rule exists", func() {
 			It("should return the correct PrometheusRuleId", func() {
@@ -222,6 +306,182 @@ var _ = Describe("Mapper", func() {
 		})
 	})
 
+	Describe("GetAlertRuleById", func() {
+		It("should return a copy of the cached rule with its location", func() {
+			rule1 := monitoringv1.Rule{Alert: "Alert1", Expr: intstr.FromString("up == 0")}
+			rule2 := monitoringv1.Rule{Alert: "Alert2", Expr: intstr.FromString("up == 1"), Labels: map[string]string{"severity": "warning"}}
+			pr := createPrometheusRule("test-namespace", "test-rule", []monitoringv1.Rule{rule1, rule2})
+			pr.ResourceVersion = "42"
+			mapperClient.AddPrometheusRule(pr)
+
+			cached, err := mapperClient.GetAlertRuleById(mapperClient.GetAlertingRuleId(&rule2))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cached.Rule).To(Equal(rule2))
+			Expect(cached.ResourceVersion).To(Equal("42"))
+			Expect(cached.Location).To(Equal(mapper.AlertRuleLocation{
+				PrometheusRuleId: mapper.PrometheusRuleId{Namespace: "test-namespace", Name: "test-rule"},
+				GroupName:        "test-group",
+				GroupIndex:       0,
+				RuleIndex:        1,
+			}))
+
+			By("verifying the cache is not modified through the copy")
+			cached.Rule.Labels["severity"] = "critical"
+			again, err := mapperClient.GetAlertRuleById(mapperClient.GetAlertingRuleId(&rule2))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(again.Rule.Labels["severity"]).To(Equal("warning"))
+		})
+
+		It("should drop rules removed from or deleted with their PrometheusRule", func() {
+			rule1 := monitoringv1.Rule{Alert: "Alert1", Expr: intstr.FromString("up == 0")}
+			rule2 := monitoringv1.Rule{Alert: "Alert2", Expr: intstr.FromString("up == 1")}
+			pr := createPrometheusRule("test-namespace", "test-rule", []monitoringv1.Rule{rule1, rule2})
+			mapperClient.AddPrometheusRule(pr)
+
+			mapperClient.AddPrometheusRule(createPrometheusRule("test-namespace", "test-rule", []monitoringv1.Rule{rule1}))
+			_, err := mapperClient.GetAlertRuleById(mapperClient.GetAlertingRuleId(&rule2))
+			Expect(err).To(HaveOccurred())
+
+			mapperClient.DeletePrometheusRule(pr)
+			_, err = mapperClient.GetAlertRuleById(mapperClient.GetAlertingRuleId(&rule1))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("ListAlertRules", func() {
+		It("should list each cached rule once, ordered by location and with its unique ID", func() {
+			stable := monitoringv1.Rule{Alert: "Stable", Expr: intstr.FromString("up == 0"), Annotations: map[string]string{mapper.AlertRuleIdAnnotation: "stable-id"}}
+			duplicated := monitoringv1.Rule{Alert: "Duplicated", Expr: intstr.FromString("up == 1")}
+			mapperClient.AddPrometheusRule(createPrometheusRule("ns-b", "rules", []monitoringv1.Rule{duplicated}))
+			mapperClient.AddPrometheusRule(createPrometheusRule("ns-a", "rules", []monitoringv1.Rule{stable, duplicated}))
+
+			rules := mapperClient.ListAlertRules(mapper.PrometheusRuleId{})
+			Expect(rules).To(HaveLen(3))
+
+			duplicatedId := mapperClient.GetAlertingRuleId(&duplicated)
+			Expect(rules[0].Id).To(Equal(mapper.PrometheusAlertRuleId("stable-id")))
+			Expect(rules[1].Id).To(Equal(mapper.QualifiedAlertingRuleId(duplicatedId, rules[1].Location)))
+			Expect(rules[1].Location.PrometheusRuleId.Namespace).To(Equal("ns-a"))
+			Expect(rules[2].Id).To(Equal(mapper.QualifiedAlertingRuleId(duplicatedId, rules[2].Location)))
+			Expect(rules[2].Location.PrometheusRuleId.Namespace).To(Equal("ns-b"))
+
+			By("filtering by namespace and name")
+			Expect(mapperClient.ListAlertRules(mapper.PrometheusRuleId{Namespace: "ns-b"})).To(HaveLen(1))
+			Expect(mapperClient.ListAlertRules(mapper.PrometheusRuleId{Namespace: "ns-a", Name: "other"})).To(BeEmpty())
+		})
+
+		It("should report whether a PrometheusRule is cached", func() {
+			mapperClient.AddPrometheusRule(createPrometheusRule("test-namespace", "empty", nil))
+
+			Expect(mapperClient.HasPrometheusRule(mapper.PrometheusRuleId{Namespace: "test-namespace", Name: "empty"})).To(BeTrue())
+			Expect(mapperClient.HasPrometheusRule(mapper.PrometheusRuleId{Namespace: "test-namespace", Name: "missing"})).To(BeFalse())
+		})
+	})
+
+	Describe("Duplicate alert rule IDs", func() {
+		var (
+			rule   monitoringv1.Rule
+			ruleId mapper.PrometheusAlertRuleId
+		)
+
+		BeforeEach(func() {
+			rule = monitoringv1.Rule{Alert: "Alert1", Expr: intstr.FromString("up == 0")}
+			ruleId = mapperClient.GetAlertingRuleId(&rule)
+
+			mapperClient.AddPrometheusRule(createPrometheusRule("ns-a", "rules", []monitoringv1.Rule{rule}))
+			mapperClient.AddPrometheusRule(createPrometheusRule("ns-b", "rules", []monitoringv1.Rule{rule}))
+		})
+
+		It("should return an ambiguity error listing the qualified IDs of all candidates", func() {
+			_, err := mapperClient.FindAlertRuleById(ruleId)
+
+			var ambiguousErr *mapper.AmbiguousAlertRuleIdError
+			Expect(errors.As(err, &ambiguousErr)).To(BeTrue())
+			Expect(ambiguousErr.Candidates).To(Equal([]mapper.PrometheusAlertRuleId{
+				ruleId + "@ns-a/rules/0/0",
+				ruleId + "@ns-b/rules/0/0",
+			}))
+		})
+
+		It("should resolve qualified IDs to a single rule", func() {
+			prId, err := mapperClient.FindAlertRuleById(ruleId + "@ns-b/rules/0/0")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*prId).To(Equal(mapper.PrometheusRuleId{Namespace: "ns-b", Name: "rules"}))
+
+			_, err = mapperClient.FindAlertRuleById(ruleId + "@ns-b/rules/0/1")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should only qualify IDs while they are duplicated", func() {
+			location := mapper.AlertRuleLocation{PrometheusRuleId: mapper.PrometheusRuleId{Namespace: "ns-a", Name: "rules"}}
+			Expect(mapperClient.GetUniqueAlertingRuleId(&rule, location)).To(Equal(ruleId + "@ns-a/rules/0/0"))
+
+			mapperClient.DeletePrometheusRule(createPrometheusRule("ns-b", "rules", nil))
+			Expect(mapperClient.GetUniqueAlertingRuleId(&rule, location)).To(Equal(ruleId))
+
+			prId, err := mapperClient.FindAlertRuleById(ruleId)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(prId.Namespace).To(Equal("ns-a"))
+		})
+
+		It("should not let copies of a stable ID in other namespaces make it ambiguous", func() {
+			stableId, err := mapper.NewAlertingRuleId("victim-ns")
+			Expect(err).ToNot(HaveOccurred())
+
+			victimRule := monitoringv1.Rule{Alert: "Victim", Expr: intstr.FromString("up == 0"), Annotations: map[string]string{mapper.AlertRuleIdAnnotation: string(stableId)}}
+			copiedRule := monitoringv1.Rule{Alert: "Copy", Expr: intstr.FromString("up == 1"), Annotations: map[string]string{mapper.AlertRuleIdAnnotation: string(stableId)}}
+			mapperClient.AddPrometheusRule(createPrometheusRule("victim-ns", "rules", []monitoringv1.Rule{victimRule}))
+			mapperClient.AddPrometheusRule(createPrometheusRule("attacker-ns", "rules", []monitoringv1.Rule{copiedRule}))
+
+			prId, err := mapperClient.FindAlertRuleById(stableId)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(prId.Namespace).To(Equal("victim-ns"))
+
+			victimLocation := mapper.AlertRuleLocation{PrometheusRuleId: mapper.PrometheusRuleId{Namespace: "victim-ns", Name: "rules"}, GroupName: "test-group"}
+			Expect(mapperClient.GetUniqueAlertingRuleId(&victimRule, victimLocation)).To(Equal(stableId))
+
+			By("qualifying the ID of the copy, which resolves to the original")
+			copyLocation := mapper.AlertRuleLocation{PrometheusRuleId: mapper.PrometheusRuleId{Namespace: "attacker-ns", Name: "rules"}, GroupName: "test-group"}
+			copyId := mapperClient.GetUniqueAlertingRuleId(&copiedRule, copyLocation)
+			Expect(copyId).To(Equal(stableId + "@attacker-ns/rules/0/0"))
+
+			cached, err := mapperClient.GetAlertRuleById(copyId)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cached.Rule.Alert).To(Equal("Copy"))
+		})
+
+		It("should keep stable IDs not generated for any namespace ambiguous", func() {
+			sharedRule := monitoringv1.Rule{Alert: "Shared", Expr: intstr.FromString("up == 0"), Annotations: map[string]string{mapper.AlertRuleIdAnnotation: "hand-written-id"}}
+			mapperClient.AddPrometheusRule(createPrometheusRule("ns-c", "rules", []monitoringv1.Rule{sharedRule}))
+			mapperClient.AddPrometheusRule(createPrometheusRule("ns-d", "rules", []monitoringv1.Rule{sharedRule}))
+
+			_, err := mapperClient.FindAlertRuleById("hand-written-id")
+
+			var ambiguousErr *mapper.AmbiguousAlertRuleIdError
+			Expect(errors.As(err, &ambiguousErr)).To(BeTrue())
+		})
+	})
+
+	Describe("ParseAlertingRuleId", func() {
+		It("should split qualified IDs", func() {
+			id, location := mapper.ParseAlertingRuleId("Alert1/abc@ns/rules/1/2")
+			Expect(id).To(Equal(mapper.PrometheusAlertRuleId("Alert1/abc")))
+			Expect(*location).To(Equal(mapper.AlertRuleLocation{
+				PrometheusRuleId: mapper.PrometheusRuleId{Namespace: "ns", Name: "rules"},
+				GroupIndex:       1,
+				RuleIndex:        2,
+			}))
+		})
+
+		It("should not parse IDs without a valid location", func() {
+			for _, raw := range []mapper.PrometheusAlertRuleId{"Alert1/abc", "Alert1/abc@ns/rules", "Alert1/abc@ns/rules/x/0"} {
+				id, location := mapper.ParseAlertingRuleId(raw)
+				Expect(id).To(Equal(raw))
+				Expect(location).To(BeNil())
+			}
+		})
+	})
+
 	Describe("AddPrometheusRule", func() {
 		Context("when adding PrometheusRules", func() {
 			It("should successfully add a PrometheusRule with alert rules", func() {
@@ -283,7 +543,7 @@ var _ = Describe("Mapper", func() {
 				Expect(foundPr.Namespace).To(Equal("test-namespace"))
 			})
 
-			It("should ignore recording rules (not alert rules)", func() {
+			It("should index recording rules", func() {
 				By("creating a PrometheusRule with recording rule")
 				recordingRule := monitoringv1.Rule{
 					Record: "test:recording:rule",
@@ -295,10 +555,14 @@ var _ = Describe("Mapper", func() {
 				By("adding the PrometheusRule")
 				mapperClient.AddPrometheusRule(pr)
 
-				By("verifying the recording rule is not found")
+				By("verifying the recording rule is found")
 				ruleId := mapperClient.GetAlertingRuleId(&recordingRule)
-				_, err := mapperClient.FindAlertRuleById(ruleId)
-				Expect(err).To(HaveOccurred())
+				foundPr, err := mapperClient.FindAlertRuleById(ruleId)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(foundPr.Name).To(Equal("test-rule"))
+
+				By("verifying relabel configs are not matched against recording rules")
+				Expect(mapperClient.GetAlertRelabelConfigSpec(&recordingRule)).To(BeEmpty())
 			})
 		})
 	})
@@ -397,7 +661,7 @@ var _ = Describe("Mapper", func() {
 				Expect(configs[0].Regex).To(Equal("TestAlert;critical"))
 			})
 
-			It("should ignore configs without alertname in SourceLabels", func() {
+			It("should keep configs without alertname in SourceLabels", func() {
 				By("creating an AlertRelabelConfig without alertname")
 				arc := &osmv1.AlertRelabelConfig{
 					ObjectMeta: metav1.ObjectMeta{
@@ -421,7 +685,7 @@ var _ = Describe("Mapper", func() {
 				By("adding the AlertRelabelConfig")
 				mapperClient.AddAlertRelabelConfig(arc)
 
-				By("verifying it returns empty for an alert")
+				By("verifying it is returned for an alert")
 				alertRule := &monitoringv1.Rule{
 					Alert: "TestAlert",
 					Labels: map[string]string{
@@ -430,7 +694,8 @@ var _ = Describe("Mapper", func() {
 					},
 				}
 				specs := mapperClient.GetAlertRelabelConfigSpec(alertRule)
-				Expect(specs).To(BeEmpty())
+				Expect(specs).To(HaveLen(1))
+				Expect(specs[0].TargetLabel).To(Equal("priority"))
 			})
 
 			It("should update existing AlertRelabelConfig when added again", func() {
@@ -517,21 +782,14 @@ var _ = Describe("Mapper", func() {
 				By("adding the AlertRelabelConfig")
 				mapperClient.AddAlertRelabelConfig(arc)
 
-				By("verifying Alert1 gets its matching config")
+				By("verifying all configs are returned in order")
 				alertRule1 := &monitoringv1.Rule{
 					Alert: "Alert1",
 				}
 				specs1 := mapperClient.GetAlertRelabelConfigSpec(alertRule1)
-				Expect(specs1).To(HaveLen(1))
+				Expect(specs1).To(HaveLen(2))
 				Expect(specs1[0].TargetLabel).To(Equal("severity"))
-
-				By("verifying Alert2 gets its matching config")
-				alertRule2 := &monitoringv1.Rule{
-					Alert: "Alert2",
-				}
-				specs2 := mapperClient.GetAlertRelabelConfigSpec(alertRule2)
-				Expect(specs2).To(HaveLen(1))
-				Expect(specs2[0].TargetLabel).To(Equal("priority"))
+				Expect(specs1[1].TargetLabel).To(Equal("priority"))
 			})
 
 			It("should handle configs with empty regex", func() {
@@ -558,15 +816,15 @@ var _ = Describe("Mapper", func() {
 				By("adding the AlertRelabelConfig")
 				mapperClient.AddAlertRelabelConfig(arc)
 
-				By("verifying it's ignored (empty regex)")
+				By("verifying it is kept, as the regex defaults to (.*)")
 				alertRule := &monitoringv1.Rule{
 					Alert: "TestAlert",
 				}
 				specs := mapperClient.GetAlertRelabelConfigSpec(alertRule)
-				Expect(specs).To(BeEmpty())
+				Expect(specs).To(HaveLen(1))
 			})
 
-			It("should handle configs where regex values don't match source labels count", func() {
+			It("should keep configs where regex values don't match source labels count", func() {
 				By("creating AlertRelabelConfig with mismatched regex/labels")
 				arc := &osmv1.AlertRelabelConfig{
 					ObjectMeta: metav1.ObjectMeta{
@@ -590,7 +848,7 @@ var _ = Describe("Mapper", func() {
 				By("adding the AlertRelabelConfig")
 				mapperClient.AddAlertRelabelConfig(arc)
 
-				By("verifying it's ignored (mismatch)")
+				By("verifying it is kept, as the regex is matched against the joined values")
 				alertRule := &monitoringv1.Rule{
 					Alert: "OnlyOneValue",
 					Labels: map[string]string{
@@ -598,7 +856,7 @@ var _ = Describe("Mapper", func() {
 					},
 				}
 				specs := mapperClient.GetAlertRelabelConfigSpec(alertRule)
-				Expect(specs).To(BeEmpty())
+				Expect(specs).To(HaveLen(1))
 			})
 		})
 	})
@@ -852,4 +1110,46 @@ var _ = Describe("Mapper", func() {
 			})
 		})
 	})
+
+	Describe("WaitForCacheSync", func() {
+		var (
+			prSynced  bool
+			arcSynced bool
+		)
+
+		BeforeEach(func() {
+			prSynced = false
+			arcSynced = false
+
+			mockK8sClient.PrometheusRuleInformerFunc = func() k8s.PrometheusRuleInformerInterface {
+				return &testutils.MockPrometheusRuleInformerInterface{
+					HasSyncedFunc: func() bool { return prSynced },
+					WaitForCacheSyncFunc: func(ctx context.Context) bool {
+						return prSynced
+					},
+				}
+			}
+			mockK8sClient.AlertRelabelConfigInformerFunc = func() k8s.AlertRelabelConfigInformerInterface {
+				return &testutils.MockAlertRelabelConfigInformerInterface{
+					HasSyncedFunc: func() bool { return arcSynced },
+					WaitForCacheSyncFunc: func(ctx context.Context) bool {
+						return arcSynced
+					},
+				}
+			}
+		})
+
+		It("should report synced only when both informers have synced", func() {
+			Expect(mapperClient.HasSynced()).To(BeFalse())
+			Expect(mapperClient.WaitForCacheSync(context.Background())).To(BeFalse())
+
+			prSynced = true
+			Expect(mapperClient.HasSynced()).To(BeFalse())
+			Expect(mapperClient.WaitForCacheSync(context.Background())).To(BeFalse())
+
+			arcSynced = true
+			Expect(mapperClient.HasSynced()).To(BeTrue())
+			Expect(mapperClient.WaitForCacheSync(context.Background())).To(BeTrue())
+		})
+	})
 })
@@ -11,6 +11,7 @@ func New(k8sClient k8s.Client) Client {
 	return &mapper{
 		k8sClient:           k8sClient,
 		prometheusRules:     make(map[PrometheusRuleId][]PrometheusAlertRuleId),
+		alertRules:          make(map[PrometheusAlertRuleId][]*CachedAlertRule),
 		alertRelabelConfigs: make(map[AlertRelabelConfigId][]osmv1.RelabelConfig),
 	}
 }
@@ -0,0 +1,62 @@
+package mapper
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// qualifiedIdSeparator separates an alerting rule ID from the rule location in qualified IDs.
+// Namespaces and names of Kubernetes resources cannot contain it.
+const qualifiedIdSeparator = "@"
+
+// AmbiguousAlertRuleIdError is returned when several alerting rules have the same ID.
+// The rules can be referenced by the qualified IDs in Candidates.
+type AmbiguousAlertRuleIdError struct {
+	Id         PrometheusAlertRuleId
+	Candidates []PrometheusAlertRuleId
+}
+
+func (e *AmbiguousAlertRuleIdError) Error() string {
+	candidates := make([]string, 0, len(e.Candidates))
+	for _, candidate := range e.Candidates {
+		candidates = append(candidates, string(candidate))
+	}
+	return fmt.Sprintf("alert rule id %s matches %d rules, use one of: %s", e.Id, len(e.Candidates), strings.Join(candidates, ", "))
+}
+
+// QualifiedAlertingRuleId returns the ID of an alerting rule qualified with its location,
+// in the form <id>@<namespace>/<name>/<group index>/<rule index>.
+func QualifiedAlertingRuleId(alertRuleId PrometheusAlertRuleId, location AlertRuleLocation) PrometheusAlertRuleId {
+	return PrometheusAlertRuleId(fmt.Sprintf("%s%s%s/%s/%d/%d", alertRuleId, qualifiedIdSeparator,
+		location.PrometheusRuleId.Namespace, location.PrometheusRuleId.Name, location.GroupIndex, location.RuleIndex))
+}
+
+// ParseAlertingRuleId splits a qualified alerting rule ID into the ID and the rule location.
+// The location is nil if the ID is not qualified.
+func ParseAlertingRuleId(alertRuleId PrometheusAlertRuleId) (PrometheusAlertRuleId, *AlertRuleLocation) {
+	idx := strings.LastIndex(string(alertRuleId), qualifiedIdSeparator)
+	if idx < 0 {
+		return alertRuleId, nil
+	}
+
+	parts := strings.Split(string(alertRuleId)[idx+1:], "/")
+	if len(parts) != 4 || parts[0] == "" || parts[1] == "" {
+		return alertRuleId, nil
+	}
+
+	groupIdx, err := strconv.Atoi(parts[2])
+	if err != nil || groupIdx < 0 {
+		return alertRuleId, nil
+	}
+	ruleIdx, err := strconv.Atoi(parts[3])
+	if err != nil || ruleIdx < 0 {
+		return alertRuleId, nil
+	}
+
+	return alertRuleId[:idx], &AlertRuleLocation{
+		PrometheusRuleId: PrometheusRuleId{Namespace: parts[0], Name: parts[1]},
+		GroupIndex:       groupIdx,
+		RuleIndex:        ruleIdx,
+	}
+}
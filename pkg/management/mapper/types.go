@@ -5,6 +5,7 @@ import (
 
 	osmv1 "github.com/openshift/api/monitoring/v1"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
 
@@ -14,21 +15,82 @@ type PrometheusRuleId types.NamespacedName
 // AlertRelabelConfigId is a unique identifier for an AlertRelabelConfig resource in Kubernetes, represented by its NamespacedName.
 type AlertRelabelConfigId types.NamespacedName
 
-// PrometheusAlertRuleId is a hash-based identifier for an alerting rule within a PrometheusRule, represented by a string.
+// PrometheusAlertRuleId is an identifier for an alerting rule within a PrometheusRule, represented by a string.
+// User-defined alerting rules have a stable ID stored in their AlertRuleIdAnnotation, while the ID of other
+// alerting rules is a hash of their content.
 type PrometheusAlertRuleId string
 
+// AlertRuleLocation is the position of an alerting rule within a PrometheusRule.
+type AlertRuleLocation struct {
+	// PrometheusRuleId is the PrometheusRule containing the alerting rule.
+	PrometheusRuleId PrometheusRuleId
+
+	// GroupName is the name of the rule group containing the alerting rule.
+	GroupName string
+
+	// GroupIndex is the index of the rule group in the PrometheusRule.
+	GroupIndex int
+
+	// RuleIndex is the index of the alerting rule in the rule group.
+	RuleIndex int
+}
+
+// CachedAlertRule is a copy of an alerting rule from the informer cache.
+type CachedAlertRule struct {
+	// Id is the ID of the alerting rule, qualified with its location if other rules have the same ID.
+	Id PrometheusAlertRuleId
+
+	// Rule is the alerting rule, without AlertRelabelConfigs applied.
+	Rule monitoringv1.Rule
+
+	// Location is the position of the alerting rule when the PrometheusRule was cached.
+	Location AlertRuleLocation
+
+	// ResourceVersion is the resourceVersion of the cached PrometheusRule.
+	ResourceVersion string
+
+	// PrometheusRuleMeta holds the namespace, name, UID, labels and owner references of the cached PrometheusRule.
+	PrometheusRuleMeta metav1.ObjectMeta
+}
+
 // Client defines the interface for mapping between Prometheus alerting rules and their unique identifiers.
 type Client interface {
 	// GetAlertingRuleId returns the unique identifier for a given alerting rule.
+	// This is the stable ID of the rule if it has one, and its content hash otherwise.
 	GetAlertingRuleId(alertRule *monitoringv1.Rule) PrometheusAlertRuleId
 
-	// FindAlertRuleById returns the PrometheusRuleId for a given alerting rule ID.
+	// GetContentAlertingRuleId returns the hash-based identifier of the content of a given alerting rule,
+	// ignoring its stable ID.
+	GetContentAlertingRuleId(alertRule *monitoringv1.Rule) PrometheusAlertRuleId
+
+	// MatchesAlertingRuleId returns true if the given ID is the stable ID or the hash-based ID of the alerting rule.
+	// The location of qualified IDs is ignored.
+	MatchesAlertingRuleId(alertRule *monitoringv1.Rule, alertRuleId PrometheusAlertRuleId) bool
+
+	// FindAlertRuleById returns the PrometheusRuleId for a given alerting rule ID, either stable or hash-based.
+	// If several rules have the ID, an AmbiguousAlertRuleIdError is returned unless the ID is qualified
+	// with the rule location.
 	FindAlertRuleById(alertRuleId PrometheusAlertRuleId) (*PrometheusRuleId, error)
 
+	// GetAlertRuleById returns a copy of the cached alerting rule with the given ID, either stable or hash-based.
+	// Like FindAlertRuleById, duplicated IDs must be qualified with the rule location.
+	GetAlertRuleById(alertRuleId PrometheusAlertRuleId) (*CachedAlertRule, error)
+
+	// GetUniqueAlertingRuleId returns the ID of an alerting rule at the given location, qualified with
+	// the location if other rules have the same ID or the ID resolves to another rule.
+	GetUniqueAlertingRuleId(alertRule *monitoringv1.Rule, location AlertRuleLocation) PrometheusAlertRuleId
+
+	// ListAlertRules returns copies of the cached alerting and recording rules of the PrometheusRules matching
+	// the given ID, ordered by their location. An empty namespace or name matches every PrometheusRule.
+	ListAlertRules(prometheusRuleId PrometheusRuleId) []CachedAlertRule
+
+	// HasPrometheusRule returns true if the PrometheusRule with the given ID is cached.
+	HasPrometheusRule(prometheusRuleId PrometheusRuleId) bool
+
 	// WatchPrometheusRules starts watching for changes to PrometheusRules.
 	WatchPrometheusRules(ctx context.Context)
 
-	// AddPrometheusRule adds or updates a PrometheusRule in the mapper.
+	// AddPrometheusRule adds or updates a PrometheusRule in the mapper, indexing its alerting and recording rules.
 	AddPrometheusRule(pr *monitoringv1.PrometheusRule)
 
 	// DeletePrometheusRule removes a PrometheusRule from the mapper.
@@ -43,6 +105,13 @@ type Client interface {
 	// DeleteAlertRelabelConfig removes an AlertRelabelConfig from the mapper.
 	DeleteAlertRelabelConfig(arc *osmv1.AlertRelabelConfig)
 
-	// GetAlertRelabelConfigSpec returns the RelabelConfigs that match the given alert rule's labels.
+	// GetAlertRelabelConfigSpec returns the RelabelConfigs of all AlertRelabelConfigs, in the order they are
+	// applied to the given alert rule. Recording rules are never relabeled, so none are returned for them.
 	GetAlertRelabelConfigSpec(alertRule *monitoringv1.Rule) []osmv1.RelabelConfig
+
+	// HasSynced returns true once the initial PrometheusRules and AlertRelabelConfigs have been added to the mapper.
+	HasSynced() bool
+
+	// WaitForCacheSync blocks until the mapper has synced, returning false if the context is cancelled first.
+	WaitForCacheSync(ctx context.Context) bool
 }
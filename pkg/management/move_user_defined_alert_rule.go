@@ -0,0 +1,210 @@
+package management
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management/mapper"
+)
+
+func (c *client) MoveUserDefinedAlertRule(ctx context.Context, alertRuleId string, target PrometheusRuleOptions) (string, error) {
+	if target.Name == "" || target.Namespace == "" {
+		return "", &InvalidArgumentError{Message: "PrometheusRule Name and Namespace must be specified"}
+	}
+
+	if target.GroupName == "" {
+		target.GroupName = DefaultGroupName
+	}
+
+	prId, err := c.findPrometheusRuleId(alertRuleId)
+	if err != nil {
+		return "", err
+	}
+
+	source, err := c.GetPrometheusRuleSource(ctx, types.NamespacedName(*prId))
+	if err != nil {
+		return "", err
+	}
+
+	if source == SourcePlatform {
+		return "", &NotAllowedError{Message: "cannot move alert rule from a platform-managed PrometheusRule"}
+	}
+
+	targetId := types.NamespacedName{Namespace: target.Namespace, Name: target.Name}
+	if err := c.checkCopyTarget(ctx, targetId); err != nil {
+		return "", err
+	}
+
+	k8sClient, err := c.k8sClientFor(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if targetId == types.NamespacedName(*prId) {
+		return c.moveRuleWithinPrometheusRule(ctx, k8sClient, prId, alertRuleId, target.GroupName)
+	}
+
+	rule, err := c.getMovedRule(ctx, k8sClient, prId, alertRuleId, target.Namespace)
+	if err != nil {
+		return "", err
+	}
+
+	// The rule is added to the target before it is deleted from the source, and
+	// removed from the target again if it cannot be deleted from the source, so
+	// that a failure never loses the rule
+	err = k8sClient.PrometheusRules().AddRule(ctx, targetId, target.GroupName, rule)
+	if err != nil {
+		return "", err
+	}
+
+	newRuleId := c.mapper.GetAlertingRuleId(&rule)
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		return c.deleteRuleFromPrometheusRule(ctx, k8sClient, prId, alertRuleId)
+	})
+	if err != nil {
+		// The expected resourceVersion is the one of the source PrometheusRule
+		rollbackCtx := WithResourceVersion(ctx, "")
+		rollbackErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			return c.removeAddedRule(rollbackCtx, k8sClient, targetId, target.GroupName, newRuleId)
+		})
+		if rollbackErr != nil {
+			return "", fmt.Errorf("failed to delete alert rule %s from PrometheusRule %s/%s: %w (the copy in PrometheusRule %s/%s could not be removed: %v)",
+				alertRuleId, prId.Namespace, prId.Name, err, targetId.Namespace, targetId.Name, rollbackErr)
+		}
+		return "", err
+	}
+
+	return string(newRuleId), nil
+}
+
+// getMovedRule returns the alert rule to add to the target PrometheusRule of a
+// move, keeping its stable ID or assigning one for the target namespace if it
+// has none
+func (c *client) getMovedRule(ctx context.Context, k8sClient k8s.Client, prId *mapper.PrometheusRuleId, alertRuleId string, targetNamespace string) (monitoringv1.Rule, error) {
+	pr, found, err := k8sClient.PrometheusRules().Get(ctx, prId.Namespace, prId.Name)
+	if err != nil {
+		return monitoringv1.Rule{}, err
+	}
+
+	if !found {
+		return monitoringv1.Rule{}, &NotFoundError{Resource: "PrometheusRule", Id: fmt.Sprintf("%s/%s", prId.Namespace, prId.Name)}
+	}
+
+	if err := checkResourceVersion(ctx, pr); err != nil {
+		return monitoringv1.Rule{}, err
+	}
+
+	groupIdx, ruleIdx, found := c.findRule(pr, alertRuleId)
+	if !found {
+		return monitoringv1.Rule{}, &NotFoundError{Resource: "AlertRule", Id: alertRuleId}
+	}
+
+	rule := pr.Spec.Groups[groupIdx].Rules[ruleIdx]
+	stableId, err := c.stableAlertRuleId(&rule, targetNamespace)
+	if err != nil {
+		return monitoringv1.Rule{}, err
+	}
+
+	return withAlertRuleId(rule, stableId), nil
+}
+
+// moveRuleWithinPrometheusRule moves an alert rule to another group of its
+// PrometheusRule in a single update
+func (c *client) moveRuleWithinPrometheusRule(ctx context.Context, k8sClient k8s.Client, prId *mapper.PrometheusRuleId, alertRuleId string, groupName string) (string, error) {
+	var newRuleId mapper.PrometheusAlertRuleId
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		pr, found, err := k8sClient.PrometheusRules().Get(ctx, prId.Namespace, prId.Name)
+		if err != nil {
+			return err
+		}
+
+		if !found {
+			return &NotFoundError{Resource: "PrometheusRule", Id: fmt.Sprintf("%s/%s", prId.Namespace, prId.Name)}
+		}
+
+		if err := checkResourceVersion(ctx, pr); err != nil {
+			return err
+		}
+
+		groupIdx, ruleIdx, found := c.findRule(pr, alertRuleId)
+		if !found {
+			return &NotFoundError{Resource: "AlertRule", Id: alertRuleId}
+		}
+
+		if pr.Spec.Groups[groupIdx].Name == groupName {
+			return &InvalidArgumentError{Message: fmt.Sprintf("alert rule %s is already in group %s", alertRuleId, groupName)}
+		}
+
+		rule := pr.Spec.Groups[groupIdx].Rules[ruleIdx]
+		stableId, err := c.stableAlertRuleId(&rule, pr.Namespace)
+		if err != nil {
+			return err
+		}
+		rule = withAlertRuleId(rule, stableId)
+		newRuleId = c.mapper.GetAlertingRuleId(&rule)
+
+		group := &pr.Spec.Groups[groupIdx]
+		group.Rules = slices.Delete(group.Rules, ruleIdx, ruleIdx+1)
+		if len(group.Rules) == 0 {
+			pr.Spec.Groups = slices.Delete(pr.Spec.Groups, groupIdx, groupIdx+1)
+		}
+
+		if idx := ruleGroupIndex(pr, groupName); idx >= 0 {
+			pr.Spec.Groups[idx].Rules = append(pr.Spec.Groups[idx].Rules, rule)
+		} else {
+			pr.Spec.Groups = append(pr.Spec.Groups, monitoringv1.RuleGroup{Name: groupName, Rules: []monitoringv1.Rule{rule}})
+		}
+
+		err = k8sClient.PrometheusRules().Update(ctx, *pr)
+		if err != nil {
+			return fmt.Errorf("failed to update PrometheusRule %s/%s: %w", pr.Namespace, pr.Name, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return string(newRuleId), nil
+}
+
+// removeAddedRule removes the last rule with the given ID from a group, undoing
+// an AddRule
+func (c *client) removeAddedRule(ctx context.Context, k8sClient k8s.Client, prId types.NamespacedName, groupName string, alertRuleId mapper.PrometheusAlertRuleId) error {
+	pr, found, err := k8sClient.PrometheusRules().Get(ctx, prId.Namespace, prId.Name)
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		return nil
+	}
+
+	groupIdx := ruleGroupIndex(pr, groupName)
+	if groupIdx < 0 {
+		return nil
+	}
+
+	group := &pr.Spec.Groups[groupIdx]
+	for ruleIdx := len(group.Rules) - 1; ruleIdx >= 0; ruleIdx-- {
+		if !c.mapper.MatchesAlertingRuleId(&group.Rules[ruleIdx], alertRuleId) {
+			continue
+		}
+
+		group.Rules = slices.Delete(group.Rules, ruleIdx, ruleIdx+1)
+		if len(group.Rules) == 0 {
+			pr.Spec.Groups = slices.Delete(pr.Spec.Groups, groupIdx, groupIdx+1)
+		}
+		return savePrometheusRule(ctx, k8sClient, pr)
+	}
+
+	return nil
+}
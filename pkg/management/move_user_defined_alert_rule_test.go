@@ -0,0 +1,156 @@
+package management_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management/mapper"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management/testutils"
+)
+
+var _ = Describe("MoveUserDefinedAlertRule", func() {
+	var (
+		ctx        context.Context
+		mockPR     *testutils.MockPrometheusRuleInterface
+		ruleMapper mapper.Client
+		client     management.Client
+		ruleId     mapper.PrometheusAlertRuleId
+		legacyId   mapper.PrometheusAlertRuleId
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		mockPR = &testutils.MockPrometheusRuleInterface{}
+		mockK8s := &testutils.MockClient{
+			PrometheusRulesFunc: func() k8s.PrometheusRuleInterface {
+				return mockPR
+			},
+		}
+		ruleMapper = mapper.New(mockK8s)
+		client = management.NewWithCustomMapper(ctx, mockK8s, ruleMapper)
+
+		ruleId = "stable-id"
+		stableRule := monitoringv1.Rule{
+			Alert:       "StableAlert",
+			Expr:        intstr.FromString("up == 0"),
+			Annotations: map[string]string{mapper.AlertRuleIdAnnotation: string(ruleId)},
+		}
+		legacyRule := monitoringv1.Rule{Alert: "LegacyAlert", Expr: intstr.FromString("up == 1")}
+		legacyId = ruleMapper.GetAlertingRuleId(&legacyRule)
+
+		prs := map[string]*monitoringv1.PrometheusRule{
+			"source-ns/rules": {
+				ObjectMeta: metav1.ObjectMeta{Namespace: "source-ns", Name: "rules"},
+				Spec: monitoringv1.PrometheusRuleSpec{
+					Groups: []monitoringv1.RuleGroup{
+						{Name: "group1", Rules: []monitoringv1.Rule{stableRule, legacyRule}},
+						{Name: "group2", Rules: []monitoringv1.Rule{{Alert: "OtherAlert"}}},
+					},
+				},
+			},
+			"openshift-monitoring/platform-rules": {
+				ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-monitoring", Name: "platform-rules"},
+				Spec: monitoringv1.PrometheusRuleSpec{
+					Groups: []monitoringv1.RuleGroup{{Name: "platform", Rules: []monitoringv1.Rule{{Alert: "PlatformAlert"}}}},
+				},
+			},
+		}
+		for _, pr := range prs {
+			ruleMapper.AddPrometheusRule(pr)
+		}
+		mockPR.SetPrometheusRules(prs)
+	})
+
+	It("should move the rule to another PrometheusRule keeping its stable ID", func() {
+		newRuleId, err := client.MoveUserDefinedAlertRule(ctx, string(ruleId), management.PrometheusRuleOptions{Namespace: "target-ns", Name: "rules", GroupName: "moved"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(newRuleId).To(Equal(string(ruleId)))
+
+		source, _, _ := mockPR.Get(ctx, "source-ns", "rules")
+		Expect(source.Spec.Groups[0].Rules).To(HaveLen(1))
+		Expect(source.Spec.Groups[0].Rules[0].Alert).To(Equal("LegacyAlert"))
+
+		target, found, _ := mockPR.Get(ctx, "target-ns", "rules")
+		Expect(found).To(BeTrue())
+		Expect(target.Spec.Groups[0].Name).To(Equal("moved"))
+		Expect(target.Spec.Groups[0].Rules[0].Alert).To(Equal("StableAlert"))
+	})
+
+	It("should assign a stable ID to rules identified by their hash", func() {
+		newRuleId, err := client.MoveUserDefinedAlertRule(ctx, string(legacyId), management.PrometheusRuleOptions{Namespace: "target-ns", Name: "rules"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(newRuleId).NotTo(Equal(string(legacyId)))
+
+		target, _, _ := mockPR.Get(ctx, "target-ns", "rules")
+		Expect(target.Spec.Groups[0].Name).To(Equal(management.DefaultGroupName))
+		Expect(target.Spec.Groups[0].Rules[0].Annotations).To(HaveKeyWithValue(mapper.AlertRuleIdAnnotation, newRuleId))
+	})
+
+	It("should move the rule to another group of its PrometheusRule", func() {
+		_, err := client.MoveUserDefinedAlertRule(ctx, string(ruleId), management.PrometheusRuleOptions{Namespace: "source-ns", Name: "rules", GroupName: "group2"})
+		Expect(err).NotTo(HaveOccurred())
+
+		source, _, _ := mockPR.Get(ctx, "source-ns", "rules")
+		Expect(source.Spec.Groups[0].Rules).To(HaveLen(1))
+		Expect(source.Spec.Groups[1].Rules).To(HaveLen(2))
+		Expect(source.Spec.Groups[1].Rules[1].Alert).To(Equal("StableAlert"))
+	})
+
+	It("should remove the rule from the target if it cannot be deleted from the source", func() {
+		// PrometheusRules are copied on Get like the API server does, so that
+		// failed edits are not kept
+		mockPR.GetFunc = func(ctx context.Context, namespace string, name string) (*monitoringv1.PrometheusRule, bool, error) {
+			pr, found := mockPR.PrometheusRules[namespace+"/"+name]
+			if !found {
+				return nil, false, nil
+			}
+			return pr.DeepCopy(), true, nil
+		}
+		mockPR.UpdateFunc = func(ctx context.Context, pr monitoringv1.PrometheusRule) error {
+			if pr.Namespace == "source-ns" {
+				return errors.New("update failed")
+			}
+			mockPR.PrometheusRules[pr.Namespace+"/"+pr.Name] = &pr
+			return nil
+		}
+		mockPR.SetPrometheusRules(map[string]*monitoringv1.PrometheusRule{
+			"source-ns/rules": mockPR.PrometheusRules["source-ns/rules"],
+			"target-ns/rules": {
+				ObjectMeta: metav1.ObjectMeta{Namespace: "target-ns", Name: "rules"},
+				Spec: monitoringv1.PrometheusRuleSpec{
+					Groups: []monitoringv1.RuleGroup{{Name: "group1", Rules: []monitoringv1.Rule{{Alert: "TargetAlert"}}}},
+				},
+			},
+		})
+
+		_, err := client.MoveUserDefinedAlertRule(ctx, string(ruleId), management.PrometheusRuleOptions{Namespace: "target-ns", Name: "rules", GroupName: "group1"})
+		Expect(err).To(MatchError(ContainSubstring("update failed")))
+
+		source, _, _ := mockPR.Get(ctx, "source-ns", "rules")
+		Expect(source.Spec.Groups[0].Rules).To(HaveLen(2))
+
+		target, _, _ := mockPR.Get(ctx, "target-ns", "rules")
+		Expect(target.Spec.Groups[0].Rules).To(HaveLen(1))
+		Expect(target.Spec.Groups[0].Rules[0].Alert).To(Equal("TargetAlert"))
+	})
+
+	It("should return NotAllowedError for platform rules and targets", func() {
+		var notAllowed *management.NotAllowedError
+
+		platformId := ruleMapper.GetAlertingRuleId(&monitoringv1.Rule{Alert: "PlatformAlert"})
+		_, err := client.MoveUserDefinedAlertRule(ctx, string(platformId), management.PrometheusRuleOptions{Namespace: "target-ns", Name: "rules"})
+		Expect(errors.As(err, &notAllowed)).To(BeTrue())
+
+		_, err = client.MoveUserDefinedAlertRule(ctx, string(ruleId), management.PrometheusRuleOptions{Namespace: "openshift-monitoring", Name: "platform-rules"})
+		Expect(errors.As(err, &notAllowed)).To(BeTrue())
+	})
+})
@@ -2,23 +2,54 @@ package management
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
 	"github.com/machadovilaca/alerts-ui-management/pkg/management/mapper"
 )
 
-// New creates a new management client
-func New(ctx context.Context, k8sClient k8s.Client) Client {
+// Option configures a management client
+type Option func(*client)
+
+// WithClassification classifies PrometheusRules as platform-managed according to opts,
+// instead of DefaultClassificationOptions
+func WithClassification(opts ClassificationOptions) Option {
+	return func(c *client) {
+		c.classifier = NewPlatformClassifier(c.k8sClient.Namespaces(), opts)
+	}
+}
+
+// WithPlatformClassifier classifies PrometheusRules as platform-managed with a custom classifier
+func WithPlatformClassifier(classifier PlatformClassifier) Option {
+	return func(c *client) {
+		c.classifier = classifier
+	}
+}
+
+// New creates a new management client. It blocks until all PrometheusRules
+// and AlertRelabelConfigs have been loaded into the mapper
+func New(ctx context.Context, k8sClient k8s.Client, opts ...Option) (Client, error) {
 	m := mapper.New(k8sClient)
 	m.WatchPrometheusRules(ctx)
 	m.WatchAlertRelabelConfigs(ctx)
 
-	return NewWithCustomMapper(ctx, k8sClient, m)
+	if !m.WaitForCacheSync(ctx) {
+		return nil, fmt.Errorf("failed to sync PrometheusRules and AlertRelabelConfigs: %w", ctx.Err())
+	}
+
+	return NewWithCustomMapper(ctx, k8sClient, m, opts...), nil
 }
 
-func NewWithCustomMapper(ctx context.Context, k8sClient k8s.Client, m mapper.Client) Client {
-	return &client{
-		k8sClient: k8sClient,
-		mapper:    m,
+func NewWithCustomMapper(ctx context.Context, k8sClient k8s.Client, m mapper.Client, opts ...Option) Client {
+	c := &client{
+		k8sClient:  k8sClient,
+		mapper:     m,
+		classifier: NewPlatformClassifier(k8sClient.Namespaces(), DefaultClassificationOptions()),
 	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
 }
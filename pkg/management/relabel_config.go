@@ -1,46 +1,157 @@
 package management
 
 import (
+	"crypto/md5"
+	"encoding/binary"
 	"fmt"
+	"log"
+	"regexp"
+	"strings"
 
 	osmv1 "github.com/openshift/api/monitoring/v1"
 )
 
+const (
+	defaultRelabelSeparator   = ";"
+	defaultRelabelRegex       = "(.*)"
+	defaultRelabelReplacement = "$1"
+	defaultRelabelAction      = "Replace"
+)
+
+var labelNameRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
 // applyRelabelConfigs applies relabel configurations to a set of labels.
 // Returns the updated labels or an error if the alert/rule should be dropped.
+//
+// The configs are processed in order following the Prometheus relabeling
+// semantics, with the defaults Prometheus uses for omitted fields. Rules do not
+// carry an alertname label, so it is set to name while relabeling and removed
+// afterwards. Configs that Prometheus would reject are skipped.
 func applyRelabelConfigs(name string, labels map[string]string, configs []osmv1.RelabelConfig) (map[string]string, error) {
-	if labels == nil {
-		labels = make(map[string]string)
-	}
-
-	updatedLabels := make(map[string]string, len(labels))
+	updatedLabels := make(map[string]string, len(labels)+1)
 	for k, v := range labels {
 		updatedLabels[k] = v
 	}
 
-	for _, config := range configs {
-		// TODO: (machadovilaca) Implement all relabeling actions
-		// 'Replace', 'Keep', 'Drop', 'HashMod', 'LabelMap', 'LabelDrop', or 'LabelKeep'
+	_, hasAlertname := updatedLabels["alertname"]
+	if !hasAlertname && name != "" {
+		updatedLabels["alertname"] = name
+	}
 
-		switch config.Action {
-		case "Drop":
+	for _, config := range configs {
+		keep, err := applyRelabelConfig(updatedLabels, config)
+		if err != nil {
+			log.Printf("Skipping invalid relabel config for alert/rule %s: %v", name, err)
+			continue
+		}
+		if !keep {
 			return nil, fmt.Errorf("alert/rule %s has been dropped by relabeling configuration", name)
-		case "Replace":
-			updatedLabels[config.TargetLabel] = config.Replacement
-		case "Keep":
-			// Keep action is a no-op in this context since the alert/rule is already matched
-		case "HashMod":
-			// HashMod action is not implemented yet
-		case "LabelMap":
-			// LabelMap action is not implemented yet
-		case "LabelDrop":
-			// LabelDrop action is not implemented yet
-		case "LabelKeep":
-			// LabelKeep action is not implemented yet
-		default:
-			// Unsupported action, ignore
 		}
 	}
 
+	if !hasAlertname {
+		delete(updatedLabels, "alertname")
+	}
+
 	return updatedLabels, nil
 }
+
+// applyRelabelConfig applies a single relabel configuration to labels in place.
+// Returns false if the labels should be dropped.
+func applyRelabelConfig(labels map[string]string, config osmv1.RelabelConfig) (bool, error) {
+	separator := config.Separator
+	if separator == "" {
+		separator = defaultRelabelSeparator
+	}
+
+	regexStr := config.Regex
+	if regexStr == "" {
+		regexStr = defaultRelabelRegex
+	}
+
+	// Prometheus regexes are fully anchored
+	regex, err := regexp.Compile("^(?:" + regexStr + ")$")
+	if err != nil {
+		return true, fmt.Errorf("invalid regex %q: %w", config.Regex, err)
+	}
+
+	replacement := config.Replacement
+	if replacement == "" {
+		replacement = defaultRelabelReplacement
+	}
+
+	action := config.Action
+	if action == "" {
+		action = defaultRelabelAction
+	}
+
+	values := make([]string, 0, len(config.SourceLabels))
+	for _, sourceLabel := range config.SourceLabels {
+		values = append(values, labels[string(sourceLabel)])
+	}
+	value := strings.Join(values, separator)
+
+	switch strings.ToLower(action) {
+	case "drop":
+		if regex.MatchString(value) {
+			return false, nil
+		}
+	case "keep":
+		if !regex.MatchString(value) {
+			return false, nil
+		}
+	case "replace":
+		indexes := regex.FindStringSubmatchIndex(value)
+		// Labels are left unchanged if the regex does not match
+		if indexes == nil {
+			break
+		}
+		target := string(regex.ExpandString(nil, config.TargetLabel, value, indexes))
+		if !labelNameRegexp.MatchString(target) {
+			break
+		}
+		result := string(regex.ExpandString(nil, replacement, value, indexes))
+		if result == "" {
+			delete(labels, target)
+			break
+		}
+		labels[target] = result
+	case "hashmod":
+		if config.Modulus == 0 {
+			return true, fmt.Errorf("modulus must be greater than 0 for action %s", action)
+		}
+		labels[config.TargetLabel] = fmt.Sprintf("%d", sum64(md5.Sum([]byte(value)))%config.Modulus)
+	case "labelmap":
+		mapped := make(map[string]string)
+		for labelName, labelValue := range labels {
+			if regex.MatchString(labelName) {
+				mapped[regex.ReplaceAllString(labelName, replacement)] = labelValue
+			}
+		}
+		for labelName, labelValue := range mapped {
+			labels[labelName] = labelValue
+		}
+	case "labeldrop":
+		for labelName := range labels {
+			if regex.MatchString(labelName) {
+				delete(labels, labelName)
+			}
+		}
+	case "labelkeep":
+		for labelName := range labels {
+			if !regex.MatchString(labelName) {
+				delete(labels, labelName)
+			}
+		}
+	default:
+		// Unsupported action, ignore
+	}
+
+	return true, nil
+}
+
+// sum64 returns the last 8 bytes of an md5 hash as an integer, as done by
+// Prometheus for the HashMod action
+func sum64(hash [md5.Size]byte) uint64 {
+	return binary.BigEndian.Uint64(hash[md5.Size-8:])
+}
@@ -0,0 +1,217 @@
+package management
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	osmv1 "github.com/openshift/api/monitoring/v1"
+)
+
+// The cases below are based on the examples from the Prometheus relabel
+// package tests, to keep applyRelabelConfigs conformant with what
+// Alertmanager receives after the AlertRelabelConfigs are applied.
+var _ = Describe("applyRelabelConfigs conformance", func() {
+	DescribeTable("relabeling labels",
+		func(input map[string]string, configs []osmv1.RelabelConfig, expected map[string]string) {
+			result, err := applyRelabelConfigs("TestAlert", input, configs)
+
+			if expected == nil {
+				Expect(err).To(HaveOccurred())
+				Expect(result).To(BeNil())
+				return
+			}
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(expected))
+		},
+
+		Entry("replace with capture groups",
+			map[string]string{"a": "foo", "b": "bar", "c": "baz"},
+			[]osmv1.RelabelConfig{
+				{SourceLabels: []osmv1.LabelName{"a"}, Regex: "f(.*)", TargetLabel: "d", Separator: ";", Replacement: "ch${1}-ch${1}", Action: "Replace"},
+			},
+			map[string]string{"a": "foo", "b": "bar", "c": "baz", "d": "choo-choo"},
+		),
+		Entry("replace with multiple source labels",
+			map[string]string{"a": "foo", "b": "bar", "c": "baz"},
+			[]osmv1.RelabelConfig{
+				{SourceLabels: []osmv1.LabelName{"a", "b"}, Regex: "f(.*);(.*)r", TargetLabel: "a", Separator: ";", Replacement: "b${1}${2}m", Action: "Replace"},
+				{SourceLabels: []osmv1.LabelName{"c", "a"}, Regex: "(b).*b(.*)ba(.*)", TargetLabel: "d", Separator: ";", Replacement: "$1$2$2$3", Action: "Replace"},
+			},
+			map[string]string{"a": "boobam", "b": "bar", "c": "baz", "d": "boooom"},
+		),
+		Entry("replace with a custom separator",
+			map[string]string{"a": "foo", "b": "bar"},
+			[]osmv1.RelabelConfig{
+				{SourceLabels: []osmv1.LabelName{"a", "b"}, Regex: `foo\|bar`, TargetLabel: "c", Separator: "|", Replacement: "matched", Action: "Replace"},
+			},
+			map[string]string{"a": "foo", "b": "bar", "c": "matched"},
+		),
+		Entry("replace with an unanchored regex that does not match the whole value",
+			map[string]string{"a": "foo"},
+			[]osmv1.RelabelConfig{
+				{SourceLabels: []osmv1.LabelName{"a"}, Regex: "f", TargetLabel: "b", Replacement: "bar", Action: "Replace"},
+			},
+			map[string]string{"a": "foo"},
+		),
+		Entry("replace with capture groups in the target label",
+			map[string]string{"a": "foo"},
+			[]osmv1.RelabelConfig{
+				{SourceLabels: []osmv1.LabelName{"a"}, Regex: "(f).*", TargetLabel: "${1}", Replacement: "${1}", Action: "Replace"},
+			},
+			map[string]string{"a": "foo", "f": "f"},
+		),
+		Entry("replace with an invalid target label",
+			map[string]string{"a": "some-name-value"},
+			[]osmv1.RelabelConfig{
+				{SourceLabels: []osmv1.LabelName{"a"}, Regex: "some-([^-]+)-([^,]+)", TargetLabel: "${3}", Replacement: "${1}", Action: "Replace"},
+				{SourceLabels: []osmv1.LabelName{"a"}, Regex: "some-([^-]+)-([^,]+)", TargetLabel: "0${3}", Replacement: "${1}", Action: "Replace"},
+				{SourceLabels: []osmv1.LabelName{"a"}, Regex: "some-([^-]+)-([^,]+)", TargetLabel: "-${3}", Replacement: "${1}", Action: "Replace"},
+			},
+			map[string]string{"a": "some-name-value"},
+		),
+		Entry("replace with the default regex and replacement",
+			map[string]string{"a": "foo"},
+			[]osmv1.RelabelConfig{
+				{SourceLabels: []osmv1.LabelName{"a"}, TargetLabel: "b"},
+			},
+			map[string]string{"a": "foo", "b": "foo"},
+		),
+		Entry("replace with a missing source label",
+			map[string]string{"a": "foo"},
+			[]osmv1.RelabelConfig{
+				{SourceLabels: []osmv1.LabelName{"z"}, TargetLabel: "b", Replacement: "empty", Action: "Replace"},
+			},
+			map[string]string{"a": "foo", "b": "empty"},
+		),
+		Entry("replace with an empty result removes the target label",
+			map[string]string{"a": "foo", "b": "bar"},
+			[]osmv1.RelabelConfig{
+				{SourceLabels: []osmv1.LabelName{"a"}, Regex: "foo", TargetLabel: "b", Action: "Replace"},
+			},
+			map[string]string{"a": "foo"},
+		),
+		Entry("replace with a lowercase action",
+			map[string]string{"a": "foo"},
+			[]osmv1.RelabelConfig{
+				{SourceLabels: []osmv1.LabelName{"a"}, Regex: "(.*)", TargetLabel: "b", Replacement: "${1}bar", Action: "replace"},
+			},
+			map[string]string{"a": "foo", "b": "foobar"},
+		),
+		Entry("drop when the regex matches",
+			map[string]string{"a": "foo"},
+			[]osmv1.RelabelConfig{
+				{SourceLabels: []osmv1.LabelName{"a"}, Regex: ".*o.*", Action: "Drop"},
+			},
+			nil,
+		),
+		Entry("drop is skipped when the regex does not match",
+			map[string]string{"a": "abc"},
+			[]osmv1.RelabelConfig{
+				{SourceLabels: []osmv1.LabelName{"a"}, Regex: ".*o.*", Action: "Drop"},
+			},
+			map[string]string{"a": "abc"},
+		),
+		Entry("drop by alertname",
+			map[string]string{"severity": "warning"},
+			[]osmv1.RelabelConfig{
+				{SourceLabels: []osmv1.LabelName{"alertname", "severity"}, Regex: "TestAlert;warning", Action: "Drop"},
+			},
+			nil,
+		),
+		Entry("keep when the regex matches",
+			map[string]string{"a": "foo"},
+			[]osmv1.RelabelConfig{
+				{SourceLabels: []osmv1.LabelName{"a"}, Regex: "f.*", Action: "Keep"},
+			},
+			map[string]string{"a": "foo"},
+		),
+		Entry("keep drops when the regex does not match",
+			map[string]string{"a": "foo"},
+			[]osmv1.RelabelConfig{
+				{SourceLabels: []osmv1.LabelName{"a"}, Regex: "no-match", Action: "Keep"},
+			},
+			nil,
+		),
+		Entry("keep with an unanchored regex that does not match the whole value",
+			map[string]string{"a": "foo"},
+			[]osmv1.RelabelConfig{
+				{SourceLabels: []osmv1.LabelName{"a"}, Regex: "f", Action: "Keep"},
+			},
+			nil,
+		),
+		Entry("hashmod",
+			map[string]string{"a": "foo", "b": "bar", "c": "baz"},
+			[]osmv1.RelabelConfig{
+				{SourceLabels: []osmv1.LabelName{"c"}, TargetLabel: "d", Separator: ";", Modulus: 1000, Action: "HashMod"},
+			},
+			map[string]string{"a": "foo", "b": "bar", "c": "baz", "d": "976"},
+		),
+		Entry("hashmod without modulus is skipped",
+			map[string]string{"a": "foo"},
+			[]osmv1.RelabelConfig{
+				{SourceLabels: []osmv1.LabelName{"a"}, TargetLabel: "d", Action: "HashMod"},
+			},
+			map[string]string{"a": "foo"},
+		),
+		Entry("labelmap",
+			map[string]string{"a": "foo", "b1": "bar", "b2": "baz"},
+			[]osmv1.RelabelConfig{
+				{Regex: "(b.*)", Replacement: "bar_${1}_bar", Action: "LabelMap"},
+			},
+			map[string]string{"a": "foo", "b1": "bar", "b2": "baz", "bar_b1_bar": "bar", "bar_b2_bar": "baz"},
+		),
+		Entry("labelmap overriding existing labels",
+			map[string]string{"__meta_my_bar": "aaa", "__meta_my_baz": "bbb", "__meta_other": "ccc", "bar": "old"},
+			[]osmv1.RelabelConfig{
+				{Regex: "__meta_(my.*)", Replacement: "${1}", Action: "LabelMap"},
+			},
+			map[string]string{"__meta_my_bar": "aaa", "__meta_my_baz": "bbb", "__meta_other": "ccc", "bar": "old", "my_bar": "aaa", "my_baz": "bbb"},
+		),
+		Entry("labeldrop",
+			map[string]string{"a": "foo", "b": "bar", "c": "baz"},
+			[]osmv1.RelabelConfig{
+				{Regex: "(b|c)", Action: "LabelDrop"},
+			},
+			map[string]string{"a": "foo"},
+		),
+		Entry("labeldrop with an unanchored regex that does not match whole label names",
+			map[string]string{"a": "foo", "ab": "bar"},
+			[]osmv1.RelabelConfig{
+				{Regex: "b", Action: "LabelDrop"},
+			},
+			map[string]string{"a": "foo", "ab": "bar"},
+		),
+		Entry("labelkeep",
+			map[string]string{"a": "foo", "b": "bar", "c": "baz"},
+			[]osmv1.RelabelConfig{
+				{Regex: "(b|c)", Action: "LabelKeep"},
+			},
+			map[string]string{"b": "bar", "c": "baz"},
+		),
+		Entry("invalid regex is skipped",
+			map[string]string{"a": "foo"},
+			[]osmv1.RelabelConfig{
+				{SourceLabels: []osmv1.LabelName{"a"}, Regex: "(", Action: "Drop"},
+				{SourceLabels: []osmv1.LabelName{"a"}, TargetLabel: "b", Replacement: "bar", Action: "Replace"},
+			},
+			map[string]string{"a": "foo", "b": "bar"},
+		),
+		Entry("label removal generated for platform rules",
+			map[string]string{"severity": "warning", "team": "platform"},
+			[]osmv1.RelabelConfig{
+				{SourceLabels: []osmv1.LabelName{"alertname", "severity"}, Regex: "TestAlert;.*", TargetLabel: "severity", Replacement: "critical", Action: "Replace"},
+				{SourceLabels: []osmv1.LabelName{"alertname"}, Regex: "TestAlert", TargetLabel: "team", Action: "Replace"},
+			},
+			map[string]string{"severity": "critical"},
+		),
+	)
+
+	It("should keep an existing alertname label", func() {
+		result, err := applyRelabelConfigs("TestAlert", map[string]string{"alertname": "TestAlert"}, []osmv1.RelabelConfig{
+			{SourceLabels: []osmv1.LabelName{"alertname"}, TargetLabel: "severity", Replacement: "critical"},
+		})
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(Equal(map[string]string{"alertname": "TestAlert", "severity": "critical"}))
+	})
+})
@@ -0,0 +1,35 @@
+package management
+
+import (
+	"context"
+	"fmt"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+)
+
+type resourceVersionContextKey struct{}
+
+// WithResourceVersion returns a copy of ctx carrying the resourceVersion the
+// PrometheusRule of the edited alert rule is expected to have. Edits fail with
+// a PreconditionFailedError if the PrometheusRule has been changed since
+func WithResourceVersion(ctx context.Context, resourceVersion string) context.Context {
+	return context.WithValue(ctx, resourceVersionContextKey{}, resourceVersion)
+}
+
+func resourceVersionFrom(ctx context.Context) (string, bool) {
+	resourceVersion, ok := ctx.Value(resourceVersionContextKey{}).(string)
+	return resourceVersion, ok && resourceVersion != ""
+}
+
+// checkResourceVersion returns a PreconditionFailedError if ctx carries an
+// expected resourceVersion different from the one of pr
+func checkResourceVersion(ctx context.Context, pr *monitoringv1.PrometheusRule) error {
+	expected, ok := resourceVersionFrom(ctx)
+	if !ok || expected == pr.ResourceVersion {
+		return nil
+	}
+
+	return &PreconditionFailedError{
+		Message: fmt.Sprintf("PrometheusRule %s/%s has resourceVersion %s, expected %s", pr.Namespace, pr.Name, pr.ResourceVersion, expected),
+	}
+}
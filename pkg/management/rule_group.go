@@ -0,0 +1,129 @@
+package management
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
+)
+
+// validateRuleGroup checks the settings of a rule group. Rules are managed
+// through the rules API and cannot be set
+func validateRuleGroup(group monitoringv1.RuleGroup) error {
+	if strings.TrimSpace(group.Name) == "" {
+		return &InvalidArgumentError{Message: "rule group name must be specified"}
+	}
+
+	if len(group.Rules) > 0 {
+		return &InvalidArgumentError{Message: "rules cannot be set on a rule group, they are managed through the rules API"}
+	}
+
+	switch strings.ToLower(group.PartialResponseStrategy) {
+	case "", "abort", "warn":
+	default:
+		return &InvalidArgumentError{Message: "partial_response_strategy must be one of: abort, warn"}
+	}
+
+	if group.Limit != nil && *group.Limit < 0 {
+		return &InvalidArgumentError{Message: "limit must not be negative"}
+	}
+
+	return nil
+}
+
+// ruleGroupIndex returns the index of the group in pr, or -1 if it does not exist
+func ruleGroupIndex(pr *monitoringv1.PrometheusRule, groupName string) int {
+	for i := range pr.Spec.Groups {
+		if pr.Spec.Groups[i].Name == groupName {
+			return i
+		}
+	}
+	return -1
+}
+
+func ruleGroupFrom(pr *monitoringv1.PrometheusRule, group monitoringv1.RuleGroup, source string) RuleGroup {
+	ruleCount := len(group.Rules)
+	group.Rules = nil
+
+	return RuleGroup{
+		RuleGroup:               *group.DeepCopy(),
+		PrometheusRuleNamespace: pr.Namespace,
+		PrometheusRuleName:      pr.Name,
+		RuleCount:               ruleCount,
+		Source:                  source,
+	}
+}
+
+func ruleGroupNotFound(prId types.NamespacedName, groupName string) error {
+	return &NotFoundError{Resource: "RuleGroup", Id: fmt.Sprintf("%s/%s/%s", prId.Namespace, prId.Name, groupName)}
+}
+
+// editUserDefinedPrometheusRule applies edit to a user-defined PrometheusRule as
+// the request user. The PrometheusRule is re-read on conflicts, so that concurrent
+// edits of other rules and groups are preserved
+func (c *client) editUserDefinedPrometheusRule(ctx context.Context, prId types.NamespacedName, edit func(pr *monitoringv1.PrometheusRule) error) (*monitoringv1.PrometheusRule, error) {
+	source, err := c.GetPrometheusRuleSource(ctx, prId)
+	if err != nil {
+		return nil, err
+	}
+
+	if source == SourcePlatform {
+		return nil, &NotAllowedError{Message: "cannot edit rule groups of a platform-managed PrometheusRule"}
+	}
+
+	k8sClient, err := c.k8sClientFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var edited *monitoringv1.PrometheusRule
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		pr, found, err := k8sClient.PrometheusRules().Get(ctx, prId.Namespace, prId.Name)
+		if err != nil {
+			return err
+		}
+
+		if !found {
+			return &NotFoundError{Resource: "PrometheusRule", Id: fmt.Sprintf("%s/%s", prId.Namespace, prId.Name)}
+		}
+
+		if err := checkResourceVersion(ctx, pr); err != nil {
+			return err
+		}
+
+		if err := edit(pr); err != nil {
+			return err
+		}
+
+		edited = pr
+		return savePrometheusRule(ctx, k8sClient, pr)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return edited, nil
+}
+
+// savePrometheusRule updates the PrometheusRule, or deletes it if it has no groups
+// left unless groups were added to it since it was read
+func savePrometheusRule(ctx context.Context, k8sClient k8s.Client, pr *monitoringv1.PrometheusRule) error {
+	if len(pr.Spec.Groups) == 0 {
+		err := k8sClient.PrometheusRules().DeleteWithResourceVersion(ctx, pr.Namespace, pr.Name, pr.ResourceVersion)
+		if err != nil {
+			return fmt.Errorf("failed to delete PrometheusRule %s/%s: %w", pr.Namespace, pr.Name, err)
+		}
+		return nil
+	}
+
+	err := k8sClient.PrometheusRules().Update(ctx, *pr)
+	if err != nil {
+		return fmt.Errorf("failed to update PrometheusRule %s/%s: %w", pr.Namespace, pr.Name, err)
+	}
+	return nil
+}
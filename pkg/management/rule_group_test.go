@@ -0,0 +1,219 @@
+package management_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management/testutils"
+)
+
+var _ = Describe("Rule groups", func() {
+	var (
+		ctx    context.Context
+		mockPR *testutils.MockPrometheusRuleInterface
+		client management.Client
+		prId   types.NamespacedName
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		prId = types.NamespacedName{Namespace: "user-namespace", Name: "user-rules"}
+
+		interval := monitoringv1.Duration("1m")
+		mockPR = &testutils.MockPrometheusRuleInterface{}
+		mockPR.SetPrometheusRules(map[string]*monitoringv1.PrometheusRule{
+			"user-namespace/user-rules": {
+				ObjectMeta: metav1.ObjectMeta{Namespace: "user-namespace", Name: "user-rules", ResourceVersion: "3"},
+				Spec: monitoringv1.PrometheusRuleSpec{
+					Groups: []monitoringv1.RuleGroup{
+						{
+							Name:     "group1",
+							Interval: &interval,
+							Rules: []monitoringv1.Rule{
+								{Alert: "Alert1", Expr: intstr.FromString("up == 0")},
+								{Alert: "Alert2", Expr: intstr.FromString("up == 1")},
+							},
+						},
+						{
+							Name:  "group2",
+							Rules: []monitoringv1.Rule{{Record: "job:up:sum", Expr: intstr.FromString("sum by (job) (up)")}},
+						},
+					},
+				},
+			},
+			"openshift-monitoring/platform-rules": {
+				ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-monitoring", Name: "platform-rules"},
+				Spec: monitoringv1.PrometheusRuleSpec{
+					Groups: []monitoringv1.RuleGroup{{Name: "platform", Rules: []monitoringv1.Rule{{Alert: "PlatformAlert"}}}},
+				},
+			},
+		})
+
+		mockK8s := &testutils.MockClient{
+			PrometheusRulesFunc: func() k8s.PrometheusRuleInterface {
+				return mockPR
+			},
+		}
+
+		client = management.NewWithCustomMapper(ctx, mockK8s, &testutils.MockMapperClient{})
+	})
+
+	Context("when listing rule groups", func() {
+		It("returns the groups with their rule count and source", func() {
+			groups, err := client.ListRuleGroups(ctx, management.PrometheusRuleOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(groups).To(HaveLen(3))
+
+			groups, err = client.ListRuleGroups(ctx, management.PrometheusRuleOptions{Namespace: "user-namespace", Name: "user-rules", GroupName: "group1"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(groups).To(HaveLen(1))
+			Expect(groups[0].Name).To(Equal("group1"))
+			Expect(groups[0].Rules).To(BeNil())
+			Expect(groups[0].RuleCount).To(Equal(2))
+			Expect(groups[0].Source).To(Equal(management.SourceUserDefined))
+			Expect(groups[0].PrometheusRuleNamespace).To(Equal("user-namespace"))
+			Expect(groups[0].PrometheusRuleName).To(Equal("user-rules"))
+		})
+
+		It("requires a namespace when a PrometheusRule name is provided", func() {
+			_, err := client.ListRuleGroups(ctx, management.PrometheusRuleOptions{Name: "user-rules"})
+
+			var invalidArgument *management.InvalidArgumentError
+			Expect(errors.As(err, &invalidArgument)).To(BeTrue())
+		})
+	})
+
+	Context("when getting a rule group", func() {
+		It("returns the group with the resourceVersion of its PrometheusRule", func() {
+			group, resourceVersion, err := client.GetRuleGroup(ctx, prId, "group2")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(group.Name).To(Equal("group2"))
+			Expect(group.RuleCount).To(Equal(1))
+			Expect(resourceVersion).To(Equal("3"))
+		})
+
+		It("returns NotFoundError if the group does not exist", func() {
+			_, _, err := client.GetRuleGroup(ctx, prId, "missing")
+
+			var notFound *management.NotFoundError
+			Expect(errors.As(err, &notFound)).To(BeTrue())
+			Expect(notFound.Resource).To(Equal("RuleGroup"))
+		})
+	})
+
+	Context("when creating a rule group", func() {
+		It("adds the group to an existing PrometheusRule", func() {
+			interval := monitoringv1.Duration("30s")
+			group, err := client.CreateRuleGroup(ctx, prId, monitoringv1.RuleGroup{Name: "group3", Interval: &interval})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(group.Name).To(Equal("group3"))
+			Expect(group.RuleCount).To(BeZero())
+
+			pr, _, _ := mockPR.Get(ctx, prId.Namespace, prId.Name)
+			Expect(pr.Spec.Groups).To(HaveLen(3))
+			Expect(*pr.Spec.Groups[2].Interval).To(Equal(interval))
+		})
+
+		It("creates the PrometheusRule if it does not exist", func() {
+			_, err := client.CreateRuleGroup(ctx, types.NamespacedName{Namespace: "user-namespace", Name: "new-rules"}, monitoringv1.RuleGroup{Name: "group1"})
+			Expect(err).NotTo(HaveOccurred())
+
+			pr, found, _ := mockPR.Get(ctx, "user-namespace", "new-rules")
+			Expect(found).To(BeTrue())
+			Expect(pr.Spec.Groups).To(HaveLen(1))
+			Expect(pr.Spec.Groups[0].Name).To(Equal("group1"))
+		})
+
+		It("returns ConflictError if a group with the same name exists", func() {
+			_, err := client.CreateRuleGroup(ctx, prId, monitoringv1.RuleGroup{Name: "group1"})
+
+			var conflict *management.ConflictError
+			Expect(errors.As(err, &conflict)).To(BeTrue())
+		})
+
+		It("rejects groups with rules", func() {
+			_, err := client.CreateRuleGroup(ctx, prId, monitoringv1.RuleGroup{Name: "group3", Rules: []monitoringv1.Rule{{Alert: "Alert3"}}})
+
+			var invalidArgument *management.InvalidArgumentError
+			Expect(errors.As(err, &invalidArgument)).To(BeTrue())
+		})
+
+		It("returns NotAllowedError for platform-managed PrometheusRules", func() {
+			_, err := client.CreateRuleGroup(ctx, types.NamespacedName{Namespace: "openshift-monitoring", Name: "platform-rules"}, monitoringv1.RuleGroup{Name: "group1"})
+
+			var notAllowed *management.NotAllowedError
+			Expect(errors.As(err, &notAllowed)).To(BeTrue())
+		})
+	})
+
+	Context("when updating a rule group", func() {
+		It("replaces the settings and keeps the rules", func() {
+			interval := monitoringv1.Duration("5m")
+			group, err := client.UpdateRuleGroup(ctx, prId, "group1", monitoringv1.RuleGroup{Name: "group1", Interval: &interval})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(group.RuleCount).To(Equal(2))
+
+			pr, _, _ := mockPR.Get(ctx, prId.Namespace, prId.Name)
+			Expect(*pr.Spec.Groups[0].Interval).To(Equal(interval))
+			Expect(pr.Spec.Groups[0].Rules).To(HaveLen(2))
+		})
+
+		It("renames the group", func() {
+			_, err := client.UpdateRuleGroup(ctx, prId, "group1", monitoringv1.RuleGroup{Name: "renamed"})
+			Expect(err).NotTo(HaveOccurred())
+
+			pr, _, _ := mockPR.Get(ctx, prId.Namespace, prId.Name)
+			Expect(pr.Spec.Groups[0].Name).To(Equal("renamed"))
+			Expect(pr.Spec.Groups[0].Rules).To(HaveLen(2))
+		})
+
+		It("returns ConflictError when renaming to an existing group", func() {
+			_, err := client.UpdateRuleGroup(ctx, prId, "group1", monitoringv1.RuleGroup{Name: "group2"})
+
+			var conflict *management.ConflictError
+			Expect(errors.As(err, &conflict)).To(BeTrue())
+		})
+
+		It("returns PreconditionFailedError if the resourceVersion does not match", func() {
+			_, err := client.UpdateRuleGroup(management.WithResourceVersion(ctx, "2"), prId, "group1", monitoringv1.RuleGroup{Name: "group1"})
+
+			var preconditionFailed *management.PreconditionFailedError
+			Expect(errors.As(err, &preconditionFailed)).To(BeTrue())
+		})
+	})
+
+	Context("when deleting a rule group", func() {
+		It("removes the group and its rules", func() {
+			Expect(client.DeleteRuleGroup(ctx, prId, "group1")).To(Succeed())
+
+			pr, found, _ := mockPR.Get(ctx, prId.Namespace, prId.Name)
+			Expect(found).To(BeTrue())
+			Expect(pr.Spec.Groups).To(HaveLen(1))
+			Expect(pr.Spec.Groups[0].Name).To(Equal("group2"))
+		})
+
+		It("deletes the PrometheusRule with its last group", func() {
+			Expect(client.DeleteRuleGroup(ctx, prId, "group1")).To(Succeed())
+			Expect(client.DeleteRuleGroup(ctx, prId, "group2")).To(Succeed())
+
+			_, found, _ := mockPR.Get(ctx, prId.Namespace, prId.Name)
+			Expect(found).To(BeFalse())
+		})
+
+		It("returns NotAllowedError for platform-managed PrometheusRules", func() {
+			err := client.DeleteRuleGroup(ctx, types.NamespacedName{Namespace: "openshift-monitoring", Name: "platform-rules"}, "platform")
+
+			var notAllowed *management.NotAllowedError
+			Expect(errors.As(err, &notAllowed)).To(BeTrue())
+		})
+	})
+})
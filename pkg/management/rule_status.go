@@ -0,0 +1,102 @@
+package management
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path"
+	"strings"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management/mapper"
+)
+
+// ruleStatusIndex finds the rules loaded by Prometheus by the rule file of
+// their PrometheusRule and the name of their group
+type ruleStatusIndex struct {
+	groups map[ruleGroupKey]*k8s.LoadedRuleGroup
+}
+
+type ruleGroupKey struct {
+	file  string
+	group string
+}
+
+// getRuleStatusIndex returns the index of the rules loaded by Prometheus. If
+// the rules API cannot be reached, nil is returned unless required is set
+func (c *client) getRuleStatusIndex(ctx context.Context, required bool) (*ruleStatusIndex, error) {
+	groups, err := c.k8sClient.PrometheusRulesAPI().GetRuleGroups(ctx)
+	if err != nil {
+		if required {
+			return nil, fmt.Errorf("failed to get the rules loaded by Prometheus: %w", err)
+		}
+		log.Printf("Skipping rule status: %v", err)
+		return nil, nil
+	}
+
+	index := &ruleStatusIndex{groups: make(map[ruleGroupKey]*k8s.LoadedRuleGroup)}
+	for i := range groups {
+		key := ruleGroupKey{file: path.Base(groups[i].File), group: groups[i].Name}
+		if _, found := index.groups[key]; !found {
+			index.groups[key] = &groups[i]
+		}
+	}
+	return index, nil
+}
+
+// status returns the status of the rule at the location in the PrometheusRule.
+// The prometheus-operator writes each PrometheusRule to its own rule file and
+// keeps the order of the rules, so the rule is looked up by its index in the
+// group, and by its name if the group changed since it was loaded
+func (idx *ruleStatusIndex) status(pr metav1.Object, location mapper.AlertRuleLocation, rule monitoringv1.Rule) *RuleStatus {
+	group, found := idx.groups[ruleGroupKey{file: ruleFileName(pr), group: location.GroupName}]
+	if !found {
+		return &RuleStatus{Loaded: false}
+	}
+
+	name, ruleType := rule.Alert, RuleTypeAlerting
+	if rule.Record != "" {
+		name, ruleType = rule.Record, RuleTypeRecording
+	}
+
+	var loaded *k8s.LoadedRule
+	if location.RuleIndex < len(group.Rules) && group.Rules[location.RuleIndex].Name == name {
+		loaded = &group.Rules[location.RuleIndex]
+	} else {
+		for i := range group.Rules {
+			if group.Rules[i].Name != name || group.Rules[i].Type != ruleType {
+				continue
+			}
+			if loaded != nil {
+				// Several rules have the name, none of them is known to be the rule
+				return &RuleStatus{Loaded: false}
+			}
+			loaded = &group.Rules[i]
+		}
+	}
+	if loaded == nil {
+		return &RuleStatus{Loaded: false}
+	}
+
+	status := &RuleStatus{
+		Loaded:         true,
+		Health:         loaded.Health,
+		LastError:      loaded.LastError,
+		EvaluationTime: loaded.EvaluationTime,
+		State:          loaded.State,
+	}
+	if !loaded.LastEvaluation.IsZero() {
+		lastEvaluation := loaded.LastEvaluation
+		status.LastEvaluation = &lastEvaluation
+	}
+	return status
+}
+
+// ruleFileName returns the name of the rule file the prometheus-operator
+// writes the PrometheusRule to
+func ruleFileName(pr metav1.Object) string {
+	return strings.Join([]string{pr.GetNamespace(), pr.GetName(), string(pr.GetUID())}, "-") + ".yaml"
+}
@@ -0,0 +1,152 @@
+package management_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management/mapper"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management/testutils"
+)
+
+var _ = Describe("Rule status", func() {
+	var (
+		ctx          context.Context
+		mockRulesAPI *testutils.MockPrometheusRulesAPIInterface
+		ruleMapper   mapper.Client
+		client       management.Client
+		lastEval     time.Time
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		lastEval = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+		mockPR := &testutils.MockPrometheusRuleInterface{}
+		mockRulesAPI = &testutils.MockPrometheusRulesAPIInterface{}
+		mockK8s := &testutils.MockClient{
+			PrometheusRulesFunc: func() k8s.PrometheusRuleInterface {
+				return mockPR
+			},
+			PrometheusRulesAPIFunc: func() k8s.PrometheusRulesAPIInterface {
+				return mockRulesAPI
+			},
+		}
+		ruleMapper = mapper.New(mockK8s)
+		client = management.NewWithCustomMapper(ctx, mockK8s, ruleMapper)
+
+		loadedPR := &monitoringv1.PrometheusRule{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "rules", UID: "uid-a"},
+			Spec: monitoringv1.PrometheusRuleSpec{
+				Groups: []monitoringv1.RuleGroup{{Name: "g1", Rules: []monitoringv1.Rule{
+					{Alert: "Healthy", Expr: intstr.FromString("up == 0")},
+					{Alert: "Broken", Expr: intstr.FromString("up == 0")},
+					{Record: "job:up:sum", Expr: intstr.FromString("sum(up)")},
+					{Alert: "Added", Expr: intstr.FromString("up == 0")},
+				}}},
+			},
+		}
+		unloadedPR := &monitoringv1.PrometheusRule{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-b", Name: "rules", UID: "uid-b"},
+			Spec: monitoringv1.PrometheusRuleSpec{
+				Groups: []monitoringv1.RuleGroup{{Name: "g1", Rules: []monitoringv1.Rule{
+					{Alert: "Unloaded", Expr: intstr.FromString("up == 0")},
+				}}},
+			},
+		}
+		ruleMapper.AddPrometheusRule(loadedPR)
+		ruleMapper.AddPrometheusRule(unloadedPR)
+		mockPR.SetPrometheusRules(map[string]*monitoringv1.PrometheusRule{
+			"team-a/rules": loadedPR,
+			"team-b/rules": unloadedPR,
+		})
+
+		// The recording rule is loaded before the alerting rules, as if the group
+		// changed since it was loaded
+		mockRulesAPI.RuleGroups = []k8s.LoadedRuleGroup{{
+			Name: "g1",
+			File: "/etc/prometheus/rules/prometheus-k8s-rulefiles-0/team-a-rules-uid-a.yaml",
+			Rules: []k8s.LoadedRule{
+				{Name: "job:up:sum", Type: "recording", Health: k8s.RuleHealthOk, LastEvaluation: lastEval, EvaluationTime: 0.5},
+				{Name: "Healthy", Type: "alerting", Health: k8s.RuleHealthOk, LastEvaluation: lastEval, EvaluationTime: 0.1, State: "firing"},
+				{Name: "Broken", Type: "alerting", Health: k8s.RuleHealthErr, LastError: "many-to-many matching not allowed", LastEvaluation: lastEval, State: "inactive"},
+			},
+		}}
+	})
+
+	statusByName := func(rules []management.Rule) map[string]*management.RuleStatus {
+		statuses := make(map[string]*management.RuleStatus)
+		for _, rule := range rules {
+			statuses[rule.Alert+rule.Record] = rule.Status
+		}
+		return statuses
+	}
+
+	It("should attach the evaluation status of each rule to the listed rules", func() {
+		rules, err := client.ListRules(ctx, management.PrometheusRuleOptions{}, management.AlertRuleOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		statuses := statusByName(rules)
+		Expect(statuses).To(HaveLen(5))
+		Expect(statuses["Healthy"]).To(Equal(&management.RuleStatus{
+			Loaded: true, Health: k8s.RuleHealthOk, LastEvaluation: &lastEval, EvaluationTime: 0.1, State: "firing",
+		}))
+		Expect(statuses["Broken"].Health).To(Equal(k8s.RuleHealthErr))
+		Expect(statuses["Broken"].LastError).To(Equal("many-to-many matching not allowed"))
+		Expect(statuses["job:up:sum"].Loaded).To(BeTrue())
+		Expect(statuses["job:up:sum"].EvaluationTime).To(Equal(0.5))
+		Expect(statuses["Added"]).To(Equal(&management.RuleStatus{Loaded: false}))
+		Expect(statuses["Unloaded"]).To(Equal(&management.RuleStatus{Loaded: false}))
+	})
+
+	It("should filter the rules Prometheus did not load", func() {
+		loaded := false
+
+		rules, err := client.ListRules(ctx, management.PrometheusRuleOptions{}, management.AlertRuleOptions{Loaded: &loaded})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(statusByName(rules)).To(HaveLen(2))
+		Expect(statusByName(rules)).To(HaveKey("Added"))
+		Expect(statusByName(rules)).To(HaveKey("Unloaded"))
+	})
+
+	It("should attach the status to the rule returned by ID", func() {
+		rules, err := client.ListRules(ctx, management.PrometheusRuleOptions{}, management.AlertRuleOptions{Name: "Broken"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rules).To(HaveLen(1))
+
+		rule, err := client.GetRuleById(ctx, rules[0].Labels["alert_rule_id"])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rule.Status).To(Equal(rules[0].Status))
+	})
+
+	Context("when the rules API cannot be reached", func() {
+		BeforeEach(func() {
+			mockRulesAPI.GetRuleGroupsFunc = func(ctx context.Context) ([]k8s.LoadedRuleGroup, error) {
+				return nil, errors.New("connection refused")
+			}
+		})
+
+		It("should list the rules without status", func() {
+			rules, err := client.ListRules(ctx, management.PrometheusRuleOptions{}, management.AlertRuleOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rules).To(HaveLen(5))
+			Expect(rules[0].Status).To(BeNil())
+		})
+
+		It("should return an error when filtering by status", func() {
+			loaded := true
+
+			_, err := client.ListRules(ctx, management.PrometheusRuleOptions{}, management.AlertRuleOptions{Loaded: &loaded})
+			Expect(err).To(MatchError(ContainSubstring("connection refused")))
+		})
+	})
+})
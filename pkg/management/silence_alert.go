@@ -0,0 +1,75 @@
+package management
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
+)
+
+func (c *client) SilenceAlert(ctx context.Context, alertLabels map[string]string, opts SilenceOptions) (string, error) {
+	if alertLabels["alertname"] == "" {
+		return "", &InvalidArgumentError{Message: "the labels of the alert must include alertname"}
+	}
+
+	return c.CreateSilence(ctx, opts.silence(equalityMatchers(alertLabels)))
+}
+
+func (c *client) SilenceAlertRule(ctx context.Context, alertRuleId string, opts SilenceOptions) (string, error) {
+	rule, _, location, err := c.getRuleById(ctx, alertRuleId, false)
+	if err != nil {
+		return "", err
+	}
+
+	if rule.Type != RuleTypeAlerting {
+		return "", &InvalidArgumentError{Message: fmt.Sprintf("rule %s is a recording rule and fires no alerts", alertRuleId)}
+	}
+
+	// The alerts of the rule have its static labels, with AlertRelabelConfigs
+	// applied. Templated values depend on each alert and cannot be matched
+	labels := map[string]string{"alertname": rule.Alert}
+	for key, value := range rule.Labels {
+		if key == alertRuleIdLabel || strings.Contains(value, "{{") {
+			continue
+		}
+		labels[key] = value
+	}
+
+	// The alerts of user-defined rules are restricted to the namespace of their
+	// PrometheusRule by the namespace label
+	if rule.Source == SourceUserDefined {
+		labels["namespace"] = location.PrometheusRuleId.Namespace
+	}
+
+	return c.CreateSilence(ctx, opts.silence(equalityMatchers(labels)))
+}
+
+// silence returns a silence with the matchers and the settings of opts
+func (opts SilenceOptions) silence(matchers []k8s.SilenceMatcher) k8s.Silence {
+	return k8s.Silence{
+		Matchers:  matchers,
+		StartsAt:  opts.StartsAt,
+		EndsAt:    opts.EndsAt,
+		CreatedBy: opts.CreatedBy,
+		Comment:   opts.Comment,
+	}
+}
+
+// equalityMatchers returns a matcher for each label, sorted by label name
+// Labels with empty values are skipped, since they match alerts without the label
+func equalityMatchers(labels map[string]string) []k8s.SilenceMatcher {
+	matchers := make([]k8s.SilenceMatcher, 0, len(labels))
+	for name, value := range labels {
+		if value == "" {
+			continue
+		}
+		matchers = append(matchers, k8s.SilenceMatcher{Name: name, Value: value})
+	}
+
+	sort.Slice(matchers, func(i, j int) bool {
+		return matchers[i].Name < matchers[j].Name
+	})
+	return matchers
+}
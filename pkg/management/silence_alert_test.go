@@ -0,0 +1,166 @@
+package management_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management/mapper"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management/testutils"
+)
+
+var _ = Describe("SilenceAlert", func() {
+	var (
+		ctx          context.Context
+		mockSilences *testutils.MockAlertmanagerSilencesInterface
+		ruleMapper   mapper.Client
+		client       management.Client
+		opts         management.SilenceOptions
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		mockPR := &testutils.MockPrometheusRuleInterface{}
+		mockSilences = &testutils.MockAlertmanagerSilencesInterface{}
+		mockK8s := &testutils.MockClient{
+			PrometheusRulesFunc: func() k8s.PrometheusRuleInterface {
+				return mockPR
+			},
+			AlertmanagerSilencesFunc: func() k8s.AlertmanagerSilencesInterface {
+				return mockSilences
+			},
+		}
+		ruleMapper = mapper.New(mockK8s)
+		client = management.NewWithCustomMapper(ctx, mockK8s, ruleMapper)
+
+		userPR := &monitoringv1.PrometheusRule{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "rules"},
+			Spec: monitoringv1.PrometheusRuleSpec{
+				Groups: []monitoringv1.RuleGroup{{Name: "g1", Rules: []monitoringv1.Rule{
+					{
+						Alert:  "UserAlert",
+						Expr:   intstr.FromString("up == 0"),
+						Labels: map[string]string{"severity": "warning", "instance": "{{ $labels.pod }}"},
+					},
+					{Record: "job:up:sum", Expr: intstr.FromString("sum(up)")},
+				}}},
+			},
+		}
+		platformPR := &monitoringv1.PrometheusRule{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-monitoring", Name: "platform-rules"},
+			Spec: monitoringv1.PrometheusRuleSpec{
+				Groups: []monitoringv1.RuleGroup{{Name: "platform", Rules: []monitoringv1.Rule{
+					{Alert: "PlatformAlert", Expr: intstr.FromString("up == 0"), Labels: map[string]string{"severity": "critical"}},
+				}}},
+			},
+		}
+		ruleMapper.AddPrometheusRule(userPR)
+		ruleMapper.AddPrometheusRule(platformPR)
+		mockPR.SetPrometheusRules(map[string]*monitoringv1.PrometheusRule{
+			"team-a/rules":                        userPR,
+			"openshift-monitoring/platform-rules": platformPR,
+		})
+
+		opts = management.SilenceOptions{
+			EndsAt:    time.Now().Add(time.Hour),
+			CreatedBy: "admin",
+			Comment:   "maintenance",
+		}
+	})
+
+	ruleId := func(namespace, name, alert string) string {
+		rules, err := client.ListRules(ctx, management.PrometheusRuleOptions{Namespace: namespace, Name: name}, management.AlertRuleOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		for _, rule := range rules {
+			if rule.Alert == alert || rule.Record == alert {
+				return rule.Labels["alert_rule_id"]
+			}
+		}
+		Fail("rule " + alert + " not found")
+		return ""
+	}
+
+	It("should silence an alert by its labels", func() {
+		id, err := client.SilenceAlert(ctx, map[string]string{"alertname": "Foo", "namespace": "team-a", "pod": "p1", "empty": ""}, opts)
+		Expect(err).NotTo(HaveOccurred())
+
+		silence, err := client.GetSilence(ctx, id)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(silence.Matchers).To(Equal([]k8s.SilenceMatcher{
+			{Name: "alertname", Value: "Foo"},
+			{Name: "namespace", Value: "team-a"},
+			{Name: "pod", Value: "p1"},
+		}))
+		Expect(silence.Comment).To(Equal("maintenance"))
+	})
+
+	It("should set the author of the silences to the user", func() {
+		userCtx := k8s.WithUser(ctx, k8s.UserInfo{Username: "alice"})
+
+		id, err := client.SilenceAlert(userCtx, map[string]string{"alertname": "Foo"}, opts)
+		Expect(err).NotTo(HaveOccurred())
+		silence, _ := client.GetSilence(ctx, id)
+		Expect(silence.CreatedBy).To(Equal("alice"))
+
+		id, err = client.SilenceAlertRule(userCtx, ruleId("team-a", "rules", "UserAlert"), opts)
+		Expect(err).NotTo(HaveOccurred())
+		silence, _ = client.GetSilence(ctx, id)
+		Expect(silence.CreatedBy).To(Equal("alice"))
+	})
+
+	It("should require the alertname label", func() {
+		_, err := client.SilenceAlert(ctx, map[string]string{"severity": "warning"}, opts)
+
+		var invalidErr *management.InvalidArgumentError
+		Expect(errors.As(err, &invalidErr)).To(BeTrue())
+	})
+
+	It("should silence the alerts of a user-defined rule in the namespace of its PrometheusRule", func() {
+		id, err := client.SilenceAlertRule(ctx, ruleId("team-a", "rules", "UserAlert"), opts)
+		Expect(err).NotTo(HaveOccurred())
+
+		silence, err := client.GetSilence(ctx, id)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(silence.Matchers).To(Equal([]k8s.SilenceMatcher{
+			{Name: "alertname", Value: "UserAlert"},
+			{Name: "namespace", Value: "team-a"},
+			{Name: "severity", Value: "warning"},
+		}))
+	})
+
+	It("should silence the alerts of a platform rule by its static labels", func() {
+		id, err := client.SilenceAlertRule(ctx, ruleId("openshift-monitoring", "platform-rules", "PlatformAlert"), opts)
+		Expect(err).NotTo(HaveOccurred())
+
+		silence, err := client.GetSilence(ctx, id)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(silence.Matchers).To(Equal([]k8s.SilenceMatcher{
+			{Name: "alertname", Value: "PlatformAlert"},
+			{Name: "severity", Value: "critical"},
+		}))
+	})
+
+	It("should reject recording rules", func() {
+		_, err := client.SilenceAlertRule(ctx, ruleId("team-a", "rules", "job:up:sum"), opts)
+
+		var invalidErr *management.InvalidArgumentError
+		Expect(errors.As(err, &invalidErr)).To(BeTrue())
+		Expect(mockSilences.Silences).To(BeEmpty())
+	})
+
+	It("should return NotFoundError for unknown rules", func() {
+		_, err := client.SilenceAlertRule(ctx, "unknown", opts)
+
+		var notFoundErr *management.NotFoundError
+		Expect(errors.As(err, &notFoundErr)).To(BeTrue())
+	})
+})
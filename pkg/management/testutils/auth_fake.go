@@ -0,0 +1,79 @@
+package testutils
+
+import (
+	"context"
+	"sync"
+
+	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
+)
+
+var _ k8s.AuthInterface = &FakeAuthorizer{}
+
+// FakeAuthorizer is a local implementation of k8s.AuthInterface for tests
+// Tokens are authenticated as the users added with AddUser, and users are only
+// allowed the actions granted with Allow
+type FakeAuthorizer struct {
+	mu     sync.RWMutex
+	users  map[string]k8s.UserInfo
+	grants map[string][]k8s.ResourceAttributes
+}
+
+// NewFakeAuthorizer creates a FakeAuthorizer without users or grants
+func NewFakeAuthorizer() *FakeAuthorizer {
+	return &FakeAuthorizer{
+		users:  make(map[string]k8s.UserInfo),
+		grants: make(map[string][]k8s.ResourceAttributes),
+	}
+}
+
+// AddUser authenticates token as user
+func (f *FakeAuthorizer) AddUser(token string, user k8s.UserInfo) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.users[token] = user
+}
+
+// Allow grants an action to the user with the given username
+// An empty Namespace grants the action in all namespaces, like a ClusterRole,
+// and an empty Name grants the action on all resources
+func (f *FakeAuthorizer) Allow(username string, attrs k8s.ResourceAttributes) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.grants[username] = append(f.grants[username], attrs)
+}
+
+// Authenticate returns the user added for token, or nil if there is none
+func (f *FakeAuthorizer) Authenticate(_ context.Context, token string) (*k8s.UserInfo, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	user, ok := f.users[token]
+	if !ok {
+		return nil, nil
+	}
+	return &user, nil
+}
+
+// Authorize returns true if the action was granted to the user
+func (f *FakeAuthorizer) Authorize(_ context.Context, user k8s.UserInfo, attrs k8s.ResourceAttributes) (bool, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	for _, grant := range f.grants[user.Username] {
+		if grant.Verb != attrs.Verb || grant.Group != attrs.Group ||
+			grant.Resource != attrs.Resource || grant.Subresource != attrs.Subresource {
+			continue
+		}
+		if grant.Namespace != "" && grant.Namespace != attrs.Namespace {
+			continue
+		}
+		if grant.Name != "" && grant.Name != attrs.Name {
+			continue
+		}
+		return true, nil
+	}
+
+	return false, nil
+}
@@ -2,9 +2,16 @@ package testutils
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"time"
 
 	osmv1 "github.com/openshift/api/monitoring/v1"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
@@ -14,10 +21,17 @@ import (
 type MockClient struct {
 	TestConnectionFunc             func(ctx context.Context) error
 	PrometheusAlertsFunc           func() k8s.PrometheusAlertsInterface
+	PrometheusRulesAPIFunc         func() k8s.PrometheusRulesAPIInterface
+	PrometheusQueryFunc            func() k8s.PrometheusQueryInterface
+	AlertmanagerSilencesFunc       func() k8s.AlertmanagerSilencesInterface
+	AlertmanagerAlertsFunc         func() k8s.AlertmanagerAlertsInterface
 	PrometheusRulesFunc            func() k8s.PrometheusRuleInterface
 	PrometheusRuleInformerFunc     func() k8s.PrometheusRuleInformerInterface
 	AlertRelabelConfigsFunc        func() k8s.AlertRelabelConfigInterface
 	AlertRelabelConfigInformerFunc func() k8s.AlertRelabelConfigInformerInterface
+	NamespacesFunc                 func() k8s.NamespaceInterface
+	AuthFunc                       func() k8s.AuthInterface
+	ImpersonateFunc                func(user k8s.UserInfo) (k8s.Client, error)
 }
 
 // TestConnection mocks the TestConnection method
@@ -36,6 +50,38 @@ func (m *MockClient) PrometheusAlerts() k8s.PrometheusAlertsInterface {
 	return &MockPrometheusAlertsInterface{}
 }
 
+// PrometheusRulesAPI mocks the PrometheusRulesAPI method
+func (m *MockClient) PrometheusRulesAPI() k8s.PrometheusRulesAPIInterface {
+	if m.PrometheusRulesAPIFunc != nil {
+		return m.PrometheusRulesAPIFunc()
+	}
+	return &MockPrometheusRulesAPIInterface{}
+}
+
+// PrometheusQuery mocks the PrometheusQuery method
+func (m *MockClient) PrometheusQuery() k8s.PrometheusQueryInterface {
+	if m.PrometheusQueryFunc != nil {
+		return m.PrometheusQueryFunc()
+	}
+	return &MockPrometheusQueryInterface{}
+}
+
+// AlertmanagerSilences mocks the AlertmanagerSilences method
+func (m *MockClient) AlertmanagerSilences() k8s.AlertmanagerSilencesInterface {
+	if m.AlertmanagerSilencesFunc != nil {
+		return m.AlertmanagerSilencesFunc()
+	}
+	return &MockAlertmanagerSilencesInterface{}
+}
+
+// AlertmanagerAlerts mocks the AlertmanagerAlerts method
+func (m *MockClient) AlertmanagerAlerts() k8s.AlertmanagerAlertsInterface {
+	if m.AlertmanagerAlertsFunc != nil {
+		return m.AlertmanagerAlertsFunc()
+	}
+	return &MockAlertmanagerAlertsInterface{}
+}
+
 // PrometheusRules mocks the PrometheusRules method
 func (m *MockClient) PrometheusRules() k8s.PrometheusRuleInterface {
 	if m.PrometheusRulesFunc != nil {
@@ -68,6 +114,30 @@ func (m *MockClient) AlertRelabelConfigInformer() k8s.AlertRelabelConfigInformer
 	return &MockAlertRelabelConfigInformerInterface{}
 }
 
+// Namespaces mocks the Namespaces method
+func (m *MockClient) Namespaces() k8s.NamespaceInterface {
+	if m.NamespacesFunc != nil {
+		return m.NamespacesFunc()
+	}
+	return &MockNamespaceInterface{}
+}
+
+// Auth mocks the Auth method
+func (m *MockClient) Auth() k8s.AuthInterface {
+	if m.AuthFunc != nil {
+		return m.AuthFunc()
+	}
+	return NewFakeAuthorizer()
+}
+
+// Impersonate mocks the Impersonate method
+func (m *MockClient) Impersonate(user k8s.UserInfo) (k8s.Client, error) {
+	if m.ImpersonateFunc != nil {
+		return m.ImpersonateFunc(user)
+	}
+	return m, nil
+}
+
 // MockPrometheusAlertsInterface is a mock implementation of k8s.PrometheusAlertsInterface
 type MockPrometheusAlertsInterface struct {
 	GetAlertsFunc func(ctx context.Context, req k8s.GetAlertsRequest) ([]k8s.PrometheusAlert, error)
@@ -92,14 +162,179 @@ func (m *MockPrometheusAlertsInterface) GetAlerts(ctx context.Context, req k8s.G
 	return []k8s.PrometheusAlert{}, nil
 }
 
+// MockPrometheusRulesAPIInterface is a mock implementation of k8s.PrometheusRulesAPIInterface
+type MockPrometheusRulesAPIInterface struct {
+	GetRuleGroupsFunc func(ctx context.Context) ([]k8s.LoadedRuleGroup, error)
+
+	// Storage for test data
+	RuleGroups []k8s.LoadedRuleGroup
+}
+
+// GetRuleGroups mocks the GetRuleGroups method
+func (m *MockPrometheusRulesAPIInterface) GetRuleGroups(ctx context.Context) ([]k8s.LoadedRuleGroup, error) {
+	if m.GetRuleGroupsFunc != nil {
+		return m.GetRuleGroupsFunc(ctx)
+	}
+
+	if m.RuleGroups != nil {
+		return m.RuleGroups, nil
+	}
+	return []k8s.LoadedRuleGroup{}, nil
+}
+
+// MockPrometheusQueryInterface is a mock implementation of k8s.PrometheusQueryInterface
+type MockPrometheusQueryInterface struct {
+	QueryRangeFunc func(ctx context.Context, req k8s.QueryRangeRequest) ([]k8s.RangeSeries, error)
+
+	// Storage for test data, the series returned for each query
+	Series map[string][]k8s.RangeSeries
+}
+
+// QueryRange mocks the QueryRange method
+func (m *MockPrometheusQueryInterface) QueryRange(ctx context.Context, req k8s.QueryRangeRequest) ([]k8s.RangeSeries, error) {
+	if m.QueryRangeFunc != nil {
+		return m.QueryRangeFunc(ctx, req)
+	}
+
+	if series, found := m.Series[req.Query]; found {
+		return series, nil
+	}
+	return []k8s.RangeSeries{}, nil
+}
+
+// MockAlertmanagerAlertsInterface is a mock implementation of k8s.AlertmanagerAlertsInterface
+type MockAlertmanagerAlertsInterface struct {
+	GetAlertsFunc func(ctx context.Context) ([]k8s.AlertmanagerAlert, error)
+
+	// Storage for test data
+	Alerts []k8s.AlertmanagerAlert
+}
+
+// GetAlerts mocks the GetAlerts method
+func (m *MockAlertmanagerAlertsInterface) GetAlerts(ctx context.Context) ([]k8s.AlertmanagerAlert, error) {
+	if m.GetAlertsFunc != nil {
+		return m.GetAlertsFunc(ctx)
+	}
+
+	if m.Alerts != nil {
+		return m.Alerts, nil
+	}
+	return []k8s.AlertmanagerAlert{}, nil
+}
+
+// MockAlertmanagerSilencesInterface is a mock implementation of k8s.AlertmanagerSilencesInterface
+type MockAlertmanagerSilencesInterface struct {
+	ListFunc   func(ctx context.Context, req k8s.ListSilencesRequest) ([]k8s.Silence, error)
+	GetFunc    func(ctx context.Context, id string) (*k8s.Silence, bool, error)
+	CreateFunc func(ctx context.Context, silence k8s.Silence) (string, error)
+	UpdateFunc func(ctx context.Context, silence k8s.Silence) (string, error)
+	ExpireFunc func(ctx context.Context, id string) error
+
+	// Storage for test data
+	Silences map[string]*k8s.Silence
+}
+
+// List mocks the List method
+func (m *MockAlertmanagerSilencesInterface) List(ctx context.Context, req k8s.ListSilencesRequest) ([]k8s.Silence, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx, req)
+	}
+
+	silences := []k8s.Silence{}
+	for _, silence := range m.Silences {
+		if req.State != "" && silence.Status.State != req.State {
+			continue
+		}
+		silences = append(silences, *silence)
+	}
+	sort.Slice(silences, func(i, j int) bool { return silences[i].Id < silences[j].Id })
+	return silences, nil
+}
+
+// Get mocks the Get method
+func (m *MockAlertmanagerSilencesInterface) Get(ctx context.Context, id string) (*k8s.Silence, bool, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, id)
+	}
+
+	if silence, exists := m.Silences[id]; exists {
+		copied := *silence
+		return &copied, true, nil
+	}
+	return nil, false, nil
+}
+
+// Create mocks the Create method
+// Silences are stored with sequential IDs and their state computed from their times
+func (m *MockAlertmanagerSilencesInterface) Create(ctx context.Context, silence k8s.Silence) (string, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, silence)
+	}
+
+	silence.Id = fmt.Sprintf("silence-%d", len(m.Silences)+1)
+	m.store(silence)
+	return silence.Id, nil
+}
+
+// Update mocks the Update method
+// Silences are updated in place, keeping their ID
+func (m *MockAlertmanagerSilencesInterface) Update(ctx context.Context, silence k8s.Silence) (string, error) {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, silence)
+	}
+
+	if _, exists := m.Silences[silence.Id]; !exists {
+		return "", fmt.Errorf("silence %s not found", silence.Id)
+	}
+	m.store(silence)
+	return silence.Id, nil
+}
+
+// Expire mocks the Expire method
+func (m *MockAlertmanagerSilencesInterface) Expire(ctx context.Context, id string) error {
+	if m.ExpireFunc != nil {
+		return m.ExpireFunc(ctx, id)
+	}
+
+	silence, exists := m.Silences[id]
+	if !exists {
+		return fmt.Errorf("silence %s not found", id)
+	}
+	silence.EndsAt = time.Now()
+	silence.Status = &k8s.SilenceStatus{State: k8s.SilenceStateExpired}
+	return nil
+}
+
+func (m *MockAlertmanagerSilencesInterface) store(silence k8s.Silence) {
+	if m.Silences == nil {
+		m.Silences = make(map[string]*k8s.Silence)
+	}
+
+	now := time.Now()
+	state := k8s.SilenceStateActive
+	switch {
+	case !now.Before(silence.EndsAt):
+		state = k8s.SilenceStateExpired
+	case now.Before(silence.StartsAt):
+		state = k8s.SilenceStatePending
+	}
+	silence.Status = &k8s.SilenceStatus{State: state}
+	silence.UpdatedAt = &now
+
+	m.Silences[silence.Id] = &silence
+}
+
 // MockPrometheusRuleInterface is a mock implementation of k8s.PrometheusRuleInterface
 type MockPrometheusRuleInterface struct {
 	ListFunc    func(ctx context.Context, namespace string) ([]monitoringv1.PrometheusRule, error)
 	GetFunc     func(ctx context.Context, namespace string, name string) (*monitoringv1.PrometheusRule, bool, error)
+	CreateFunc  func(ctx context.Context, pr monitoringv1.PrometheusRule) error
 	UpdateFunc  func(ctx context.Context, pr monitoringv1.PrometheusRule) error
 	DeleteFunc  func(ctx context.Context, namespace string, name string) error
 	AddRuleFunc func(ctx context.Context, namespacedName types.NamespacedName, groupName string, rule monitoringv1.Rule) error
 
+	DeleteWithResourceVersionFunc func(ctx context.Context, namespace string, name string, resourceVersion string) error
+
 	// Storage for test data
 	PrometheusRules map[string]*monitoringv1.PrometheusRule
 }
@@ -134,13 +369,34 @@ func (m *MockPrometheusRuleInterface) Get(ctx context.Context, namespace string,
 	key := namespace + "/" + name
 	if m.PrometheusRules != nil {
 		if rule, exists := m.PrometheusRules[key]; exists {
-			return rule, true, nil
+			// The API server returns a new object on every read
+			return rule.DeepCopy(), true, nil
 		}
 	}
 
 	return nil, false, nil
 }
 
+// Create mocks the Create method
+func (m *MockPrometheusRuleInterface) Create(ctx context.Context, pr monitoringv1.PrometheusRule) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, pr)
+	}
+
+	key := pr.Namespace + "/" + pr.Name
+	if m.PrometheusRules == nil {
+		m.PrometheusRules = make(map[string]*monitoringv1.PrometheusRule)
+	}
+	if _, exists := m.PrometheusRules[key]; exists {
+		return apierrors.NewAlreadyExists(monitoringv1.SchemeGroupVersion.WithResource("prometheusrules").GroupResource(), pr.Name)
+	}
+	if recordDryRun(ctx, k8s.DryRunCreate, monitoringv1.PrometheusRuleKind, nil, &pr) {
+		return nil
+	}
+	m.PrometheusRules[key] = &pr
+	return nil
+}
+
 // Update mocks the Update method
 func (m *MockPrometheusRuleInterface) Update(ctx context.Context, pr monitoringv1.PrometheusRule) error {
 	if m.UpdateFunc != nil {
@@ -151,6 +407,9 @@ func (m *MockPrometheusRuleInterface) Update(ctx context.Context, pr monitoringv
 	if m.PrometheusRules == nil {
 		m.PrometheusRules = make(map[string]*monitoringv1.PrometheusRule)
 	}
+	if recordDryRun(ctx, k8s.DryRunUpdate, monitoringv1.PrometheusRuleKind, m.PrometheusRules[key], &pr) {
+		return nil
+	}
 	m.PrometheusRules[key] = &pr
 	return nil
 }
@@ -162,12 +421,25 @@ func (m *MockPrometheusRuleInterface) Delete(ctx context.Context, namespace stri
 	}
 
 	key := namespace + "/" + name
+	if recordDryRun(ctx, k8s.DryRunDelete, monitoringv1.PrometheusRuleKind, m.PrometheusRules[key], nil) {
+		return nil
+	}
 	if m.PrometheusRules != nil {
 		delete(m.PrometheusRules, key)
 	}
 	return nil
 }
 
+// DeleteWithResourceVersion mocks the DeleteWithResourceVersion method
+// By default the resourceVersion is ignored and Delete is called
+func (m *MockPrometheusRuleInterface) DeleteWithResourceVersion(ctx context.Context, namespace string, name string, resourceVersion string) error {
+	if m.DeleteWithResourceVersionFunc != nil {
+		return m.DeleteWithResourceVersionFunc(ctx, namespace, name, resourceVersion)
+	}
+
+	return m.Delete(ctx, namespace, name)
+}
+
 // AddRule mocks the AddRule method
 func (m *MockPrometheusRuleInterface) AddRule(ctx context.Context, namespacedName types.NamespacedName, groupName string, rule monitoringv1.Rule) error {
 	if m.AddRuleFunc != nil {
@@ -180,7 +452,8 @@ func (m *MockPrometheusRuleInterface) AddRule(ctx context.Context, namespacedNam
 	}
 
 	// Get or create PrometheusRule
-	pr, exists := m.PrometheusRules[key]
+	before, exists := m.PrometheusRules[key]
+	pr := before.DeepCopy()
 	if !exists {
 		pr = &monitoringv1.PrometheusRule{
 			Spec: monitoringv1.PrometheusRuleSpec{
@@ -189,7 +462,6 @@ func (m *MockPrometheusRuleInterface) AddRule(ctx context.Context, namespacedNam
 		}
 		pr.Name = namespacedName.Name
 		pr.Namespace = namespacedName.Namespace
-		m.PrometheusRules[key] = pr
 	}
 
 	// Find or create the group
@@ -211,12 +483,22 @@ func (m *MockPrometheusRuleInterface) AddRule(ctx context.Context, namespacedNam
 	// Add the new rule to the group
 	group.Rules = append(group.Rules, rule)
 
+	operation := k8s.DryRunUpdate
+	if !exists {
+		operation = k8s.DryRunCreate
+	}
+	if recordDryRun(ctx, operation, monitoringv1.PrometheusRuleKind, before, pr) {
+		return nil
+	}
+	m.PrometheusRules[key] = pr
 	return nil
 }
 
 // MockPrometheusRuleInformerInterface is a mock implementation of k8s.PrometheusRuleInformerInterface
 type MockPrometheusRuleInformerInterface struct {
-	RunFunc func(ctx context.Context, callbacks k8s.PrometheusRuleInformerCallback) error
+	RunFunc              func(ctx context.Context, callbacks k8s.PrometheusRuleInformerCallback) error
+	HasSyncedFunc        func() bool
+	WaitForCacheSyncFunc func(ctx context.Context) bool
 }
 
 // Run mocks the Run method
@@ -230,6 +512,24 @@ func (m *MockPrometheusRuleInformerInterface) Run(ctx context.Context, callbacks
 	return ctx.Err()
 }
 
+// HasSynced mocks the HasSynced method
+func (m *MockPrometheusRuleInformerInterface) HasSynced() bool {
+	if m.HasSyncedFunc != nil {
+		return m.HasSyncedFunc()
+	}
+
+	return true
+}
+
+// WaitForCacheSync mocks the WaitForCacheSync method
+func (m *MockPrometheusRuleInformerInterface) WaitForCacheSync(ctx context.Context) bool {
+	if m.WaitForCacheSyncFunc != nil {
+		return m.WaitForCacheSyncFunc(ctx)
+	}
+
+	return true
+}
+
 // MockAlertRelabelConfigInterface is a mock implementation of k8s.AlertRelabelConfigInterface
 type MockAlertRelabelConfigInterface struct {
 	ListFunc   func(ctx context.Context, namespace string) ([]osmv1.AlertRelabelConfig, error)
@@ -272,7 +572,8 @@ func (m *MockAlertRelabelConfigInterface) Get(ctx context.Context, namespace str
 	key := namespace + "/" + name
 	if m.AlertRelabelConfigs != nil {
 		if config, exists := m.AlertRelabelConfigs[key]; exists {
-			return config, true, nil
+			// The API server returns a new object on every read
+			return config.DeepCopy(), true, nil
 		}
 	}
 
@@ -289,6 +590,9 @@ func (m *MockAlertRelabelConfigInterface) Create(ctx context.Context, arc osmv1.
 	if m.AlertRelabelConfigs == nil {
 		m.AlertRelabelConfigs = make(map[string]*osmv1.AlertRelabelConfig)
 	}
+	if recordDryRun(ctx, k8s.DryRunCreate, "AlertRelabelConfig", nil, &arc) {
+		return &arc, nil
+	}
 	m.AlertRelabelConfigs[key] = &arc
 	return &arc, nil
 }
@@ -303,6 +607,9 @@ func (m *MockAlertRelabelConfigInterface) Update(ctx context.Context, arc osmv1.
 	if m.AlertRelabelConfigs == nil {
 		m.AlertRelabelConfigs = make(map[string]*osmv1.AlertRelabelConfig)
 	}
+	if recordDryRun(ctx, k8s.DryRunUpdate, "AlertRelabelConfig", m.AlertRelabelConfigs[key], &arc) {
+		return nil
+	}
 	m.AlertRelabelConfigs[key] = &arc
 	return nil
 }
@@ -314,6 +621,9 @@ func (m *MockAlertRelabelConfigInterface) Delete(ctx context.Context, namespace
 	}
 
 	key := namespace + "/" + name
+	if recordDryRun(ctx, k8s.DryRunDelete, "AlertRelabelConfig", m.AlertRelabelConfigs[key], nil) {
+		return nil
+	}
 	if m.AlertRelabelConfigs != nil {
 		delete(m.AlertRelabelConfigs, key)
 	}
@@ -322,7 +632,9 @@ func (m *MockAlertRelabelConfigInterface) Delete(ctx context.Context, namespace
 
 // MockAlertRelabelConfigInformerInterface is a mock implementation of k8s.AlertRelabelConfigInformerInterface
 type MockAlertRelabelConfigInformerInterface struct {
-	RunFunc func(ctx context.Context, callbacks k8s.AlertRelabelConfigInformerCallback) error
+	RunFunc              func(ctx context.Context, callbacks k8s.AlertRelabelConfigInformerCallback) error
+	HasSyncedFunc        func() bool
+	WaitForCacheSyncFunc func(ctx context.Context) bool
 }
 
 // Run mocks the Run method
@@ -335,3 +647,73 @@ func (m *MockAlertRelabelConfigInformerInterface) Run(ctx context.Context, callb
 	<-ctx.Done()
 	return ctx.Err()
 }
+
+// HasSynced mocks the HasSynced method
+func (m *MockAlertRelabelConfigInformerInterface) HasSynced() bool {
+	if m.HasSyncedFunc != nil {
+		return m.HasSyncedFunc()
+	}
+
+	return true
+}
+
+// WaitForCacheSync mocks the WaitForCacheSync method
+func (m *MockAlertRelabelConfigInformerInterface) WaitForCacheSync(ctx context.Context) bool {
+	if m.WaitForCacheSyncFunc != nil {
+		return m.WaitForCacheSyncFunc(ctx)
+	}
+
+	return true
+}
+
+// MockNamespaceInterface is a mock implementation of k8s.NamespaceInterface
+type MockNamespaceInterface struct {
+	GetFunc func(ctx context.Context, name string) (*corev1.Namespace, bool, error)
+
+	// Storage for test data
+	Namespaces map[string]*corev1.Namespace
+}
+
+func (m *MockNamespaceInterface) SetNamespaces(namespaces map[string]*corev1.Namespace) {
+	m.Namespaces = namespaces
+}
+
+// Get mocks the Get method
+func (m *MockNamespaceInterface) Get(ctx context.Context, name string) (*corev1.Namespace, bool, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, name)
+	}
+
+	if m.Namespaces != nil {
+		if ns, exists := m.Namespaces[name]; exists {
+			return ns, true, nil
+		}
+	}
+
+	return nil, false, nil
+}
+
+// recordDryRun records a write with the DryRun carried by ctx and returns true
+// if ctx is a dry run, in which case the write must not be stored
+func recordDryRun[T interface {
+	*monitoringv1.PrometheusRule | *osmv1.AlertRelabelConfig
+	metav1.Object
+	runtime.Object
+}](ctx context.Context, operation string, kind string, before T, after T) bool {
+	dryRun, ok := k8s.DryRunFrom(ctx)
+	if !ok {
+		return false
+	}
+
+	write := k8s.DryRunWrite{Operation: operation, Kind: kind}
+	if before != nil {
+		write.Namespace, write.Name = before.GetNamespace(), before.GetName()
+		write.Before = before
+	}
+	if after != nil {
+		write.Namespace, write.Name = after.GetNamespace(), after.GetName()
+		write.After = after
+	}
+	dryRun.Record(write)
+	return true
+}
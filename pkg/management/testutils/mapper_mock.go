@@ -2,6 +2,8 @@ package testutils
 
 import (
 	"context"
+	"fmt"
+	"sort"
 
 	osmv1 "github.com/openshift/api/monitoring/v1"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
@@ -14,7 +16,13 @@ var _ mapper.Client = &MockMapperClient{}
 // MockMapperClient is a simple mock for the mapper.Client interface
 type MockMapperClient struct {
 	GetAlertingRuleIdFunc         func(alertRule *monitoringv1.Rule) mapper.PrometheusAlertRuleId
+	GetContentAlertingRuleIdFunc  func(alertRule *monitoringv1.Rule) mapper.PrometheusAlertRuleId
+	MatchesAlertingRuleIdFunc     func(alertRule *monitoringv1.Rule, alertRuleId mapper.PrometheusAlertRuleId) bool
 	FindAlertRuleByIdFunc         func(alertRuleId mapper.PrometheusAlertRuleId) (*mapper.PrometheusRuleId, error)
+	GetAlertRuleByIdFunc          func(alertRuleId mapper.PrometheusAlertRuleId) (*mapper.CachedAlertRule, error)
+	GetUniqueAlertingRuleIdFunc   func(alertRule *monitoringv1.Rule, location mapper.AlertRuleLocation) mapper.PrometheusAlertRuleId
+	ListAlertRulesFunc            func(prometheusRuleId mapper.PrometheusRuleId) []mapper.CachedAlertRule
+	HasPrometheusRuleFunc         func(prometheusRuleId mapper.PrometheusRuleId) bool
 	WatchPrometheusRulesFunc      func(ctx context.Context)
 	AddPrometheusRuleFunc         func(pr *monitoringv1.PrometheusRule)
 	DeletePrometheusRuleFunc      func(pr *monitoringv1.PrometheusRule)
@@ -22,6 +30,8 @@ type MockMapperClient struct {
 	AddAlertRelabelConfigFunc     func(arc *osmv1.AlertRelabelConfig)
 	DeleteAlertRelabelConfigFunc  func(arc *osmv1.AlertRelabelConfig)
 	GetAlertRelabelConfigSpecFunc func(alertRule *monitoringv1.Rule) []osmv1.RelabelConfig
+	HasSyncedFunc                 func() bool
+	WaitForCacheSyncFunc          func(ctx context.Context) bool
 }
 
 func (m *MockMapperClient) GetAlertingRuleId(alertRule *monitoringv1.Rule) mapper.PrometheusAlertRuleId {
@@ -31,6 +41,22 @@ func (m *MockMapperClient) GetAlertingRuleId(alertRule *monitoringv1.Rule) mappe
 	return mapper.PrometheusAlertRuleId("mock-id")
 }
 
+// GetContentAlertingRuleId defaults to GetAlertingRuleId
+func (m *MockMapperClient) GetContentAlertingRuleId(alertRule *monitoringv1.Rule) mapper.PrometheusAlertRuleId {
+	if m.GetContentAlertingRuleIdFunc != nil {
+		return m.GetContentAlertingRuleIdFunc(alertRule)
+	}
+	return m.GetAlertingRuleId(alertRule)
+}
+
+// MatchesAlertingRuleId defaults to comparing the ID with GetAlertingRuleId
+func (m *MockMapperClient) MatchesAlertingRuleId(alertRule *monitoringv1.Rule, alertRuleId mapper.PrometheusAlertRuleId) bool {
+	if m.MatchesAlertingRuleIdFunc != nil {
+		return m.MatchesAlertingRuleIdFunc(alertRule, alertRuleId)
+	}
+	return m.GetAlertingRuleId(alertRule) == alertRuleId
+}
+
 func (m *MockMapperClient) FindAlertRuleById(alertRuleId mapper.PrometheusAlertRuleId) (*mapper.PrometheusRuleId, error) {
 	if m.FindAlertRuleByIdFunc != nil {
 		return m.FindAlertRuleByIdFunc(alertRuleId)
@@ -38,6 +64,36 @@ func (m *MockMapperClient) FindAlertRuleById(alertRuleId mapper.PrometheusAlertR
 	return nil, nil
 }
 
+// GetAlertRuleById defaults to a cache miss, so that callers fall back to the API server
+func (m *MockMapperClient) GetAlertRuleById(alertRuleId mapper.PrometheusAlertRuleId) (*mapper.CachedAlertRule, error) {
+	if m.GetAlertRuleByIdFunc != nil {
+		return m.GetAlertRuleByIdFunc(alertRuleId)
+	}
+	return nil, fmt.Errorf("alert rule with id %s not cached", alertRuleId)
+}
+
+// GetUniqueAlertingRuleId defaults to GetAlertingRuleId
+func (m *MockMapperClient) GetUniqueAlertingRuleId(alertRule *monitoringv1.Rule, location mapper.AlertRuleLocation) mapper.PrometheusAlertRuleId {
+	if m.GetUniqueAlertingRuleIdFunc != nil {
+		return m.GetUniqueAlertingRuleIdFunc(alertRule, location)
+	}
+	return m.GetAlertingRuleId(alertRule)
+}
+
+func (m *MockMapperClient) ListAlertRules(prometheusRuleId mapper.PrometheusRuleId) []mapper.CachedAlertRule {
+	if m.ListAlertRulesFunc != nil {
+		return m.ListAlertRulesFunc(prometheusRuleId)
+	}
+	return nil
+}
+
+func (m *MockMapperClient) HasPrometheusRule(prometheusRuleId mapper.PrometheusRuleId) bool {
+	if m.HasPrometheusRuleFunc != nil {
+		return m.HasPrometheusRuleFunc(prometheusRuleId)
+	}
+	return false
+}
+
 func (m *MockMapperClient) WatchPrometheusRules(ctx context.Context) {
 	if m.WatchPrometheusRulesFunc != nil {
 		m.WatchPrometheusRulesFunc(ctx)
@@ -80,3 +136,60 @@ func (m *MockMapperClient) GetAlertRelabelConfigSpec(alertRule *monitoringv1.Rul
 	}
 	return nil
 }
+
+func (m *MockMapperClient) HasSynced() bool {
+	if m.HasSyncedFunc != nil {
+		return m.HasSyncedFunc()
+	}
+	return true
+}
+
+func (m *MockMapperClient) WaitForCacheSync(ctx context.Context) bool {
+	if m.WaitForCacheSyncFunc != nil {
+		return m.WaitForCacheSyncFunc(ctx)
+	}
+	return true
+}
+
+// CachePrometheusRules serves ListAlertRules and HasPrometheusRule from the PrometheusRules stored in
+// prometheusRules, as if the mapper had cached them
+func (m *MockMapperClient) CachePrometheusRules(prometheusRules *MockPrometheusRuleInterface) {
+	m.ListAlertRulesFunc = func(prometheusRuleId mapper.PrometheusRuleId) []mapper.CachedAlertRule {
+		var rules []mapper.CachedAlertRule
+		for _, pr := range prometheusRules.PrometheusRules {
+			if (prometheusRuleId.Namespace != "" && prometheusRuleId.Namespace != pr.Namespace) ||
+				(prometheusRuleId.Name != "" && prometheusRuleId.Name != pr.Name) {
+				continue
+			}
+
+			for groupIdx, group := range pr.Spec.Groups {
+				for ruleIdx, rule := range group.Rules {
+					location := mapper.AlertRuleLocation{
+						PrometheusRuleId: mapper.PrometheusRuleId{Namespace: pr.Namespace, Name: pr.Name},
+						GroupName:        group.Name,
+						GroupIndex:       groupIdx,
+						RuleIndex:        ruleIdx,
+					}
+					rules = append(rules, mapper.CachedAlertRule{
+						Id:                 m.GetUniqueAlertingRuleId(&rule, location),
+						Rule:               *rule.DeepCopy(),
+						Location:           location,
+						ResourceVersion:    pr.ResourceVersion,
+						PrometheusRuleMeta: *pr.ObjectMeta.DeepCopy(),
+					})
+				}
+			}
+		}
+
+		sort.SliceStable(rules, func(i, j int) bool {
+			a, b := rules[i].Location.PrometheusRuleId, rules[j].Location.PrometheusRuleId
+			return a.Namespace < b.Namespace || (a.Namespace == b.Namespace && a.Name < b.Name)
+		})
+		return rules
+	}
+
+	m.HasPrometheusRuleFunc = func(prometheusRuleId mapper.PrometheusRuleId) bool {
+		_, found := prometheusRules.PrometheusRules[prometheusRuleId.Namespace+"/"+prometheusRuleId.Name]
+		return found
+	}
+}
@@ -2,8 +2,14 @@ package management
 
 import (
 	"context"
+	"time"
 
+	osmv1 "github.com/openshift/api/monitoring/v1"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
 )
@@ -11,26 +17,184 @@ import (
 // Client is the interface for managing alert rules
 type Client interface {
 	// ListRules lists all alert rules in the specified PrometheusRule resource
-	ListRules(ctx context.Context, prOptions PrometheusRuleOptions, arOptions AlertRuleOptions) ([]monitoringv1.Rule, error)
+	ListRules(ctx context.Context, prOptions PrometheusRuleOptions, arOptions AlertRuleOptions) ([]Rule, error)
 
 	// GetRuleById retrieves a specific alert rule by its ID
-	GetRuleById(ctx context.Context, alertRuleId string) (monitoringv1.Rule, error)
+	GetRuleById(ctx context.Context, alertRuleId string) (Rule, error)
+
+	// GetRuleByIdWithResourceVersion retrieves a specific alert rule by its ID, with the resourceVersion
+	// of its PrometheusRule, which can be passed to edits with WithResourceVersion
+	GetRuleByIdWithResourceVersion(ctx context.Context, alertRuleId string) (Rule, string, error)
 
 	// CreateUserDefinedAlertRule creates a new user-defined alert rule
 	CreateUserDefinedAlertRule(ctx context.Context, alertRule monitoringv1.Rule, prOptions PrometheusRuleOptions) (alertRuleId string, err error)
 
 	// UpdateUserDefinedAlertRule updates an existing user-defined alert rule by its ID
-	UpdateUserDefinedAlertRule(ctx context.Context, alertRuleId string, alertRule monitoringv1.Rule) error
+	// and returns the new ID of the updated alert rule
+	UpdateUserDefinedAlertRule(ctx context.Context, alertRuleId string, alertRule monitoringv1.Rule) (newAlertRuleId string, err error)
 
 	// DeleteUserDefinedAlertRuleById deletes a user-defined alert rule by its ID
 	DeleteUserDefinedAlertRuleById(ctx context.Context, alertRuleId string) error
 
+	// MoveUserDefinedAlertRule moves a user-defined alert rule to another user-defined PrometheusRule or group
+	// and returns its new ID. The rule is removed from the target again if it cannot be deleted from its
+	// PrometheusRule
+	MoveUserDefinedAlertRule(ctx context.Context, alertRuleId string, target PrometheusRuleOptions) (newAlertRuleId string, err error)
+
+	// CopyAlertRule copies a platform or user-defined alert rule to a user-defined PrometheusRule and returns
+	// the ID of the copy
+	CopyAlertRule(ctx context.Context, alertRuleId string, target PrometheusRuleOptions) (newAlertRuleId string, err error)
+
 	// UpdatePlatformAlertRule updates an existing platform alert rule by its ID
 	// Platform alert rules can only have the labels updated through AlertRelabelConfigs
 	UpdatePlatformAlertRule(ctx context.Context, alertRuleId string, alertRule monitoringv1.Rule) error
 
+	// UpdateAlertRuleLabels sets the labels of an alert rule by its ID
+	// Platform alert rules are updated through AlertRelabelConfigs and user-defined alert rules
+	// are updated in their PrometheusRule
+	UpdateAlertRuleLabels(ctx context.Context, alertRuleId string, labels map[string]string) (*UpdateAlertRuleLabelsResult, error)
+
+	// ListRuleGroups lists the rule groups of the specified PrometheusRule resources, without their rules
+	ListRuleGroups(ctx context.Context, prOptions PrometheusRuleOptions) ([]RuleGroup, error)
+
+	// GetRuleGroup retrieves a rule group by name, with the resourceVersion of its PrometheusRule
+	GetRuleGroup(ctx context.Context, prId types.NamespacedName, groupName string) (RuleGroup, string, error)
+
+	// CreateRuleGroup adds an empty rule group to a user-defined PrometheusRule, creating the PrometheusRule
+	// if it does not exist
+	CreateRuleGroup(ctx context.Context, prId types.NamespacedName, group monitoringv1.RuleGroup) (RuleGroup, error)
+
+	// UpdateRuleGroup replaces the settings of a rule group of a user-defined PrometheusRule, keeping its rules
+	// The group is renamed if group has a different name
+	UpdateRuleGroup(ctx context.Context, prId types.NamespacedName, groupName string, group monitoringv1.RuleGroup) (RuleGroup, error)
+
+	// DeleteRuleGroup deletes a rule group and its rules from a user-defined PrometheusRule
+	// The PrometheusRule is deleted if it has no groups left
+	DeleteRuleGroup(ctx context.Context, prId types.NamespacedName, groupName string) error
+
 	// GetAlerts retrieves Prometheus alerts
 	GetAlerts(ctx context.Context, req k8s.GetAlertsRequest) ([]k8s.PrometheusAlert, error)
+
+	// GetAlertHistory retrieves the intervals during which the alerts matching the filters fired between
+	// start and end, from the ALERTS and ALERTS_FOR_STATE metrics
+	GetAlertHistory(ctx context.Context, filters AlertHistoryFilters, start time.Time, end time.Time) ([]AlertHistory, error)
+
+	// ListSilences lists Alertmanager silences
+	ListSilences(ctx context.Context, req k8s.ListSilencesRequest) ([]k8s.Silence, error)
+
+	// GetSilence retrieves an Alertmanager silence by its ID
+	GetSilence(ctx context.Context, silenceId string) (k8s.Silence, error)
+
+	// CreateSilence creates an Alertmanager silence and returns its ID
+	// The silence starts now if StartsAt is unset, and CreatedBy is replaced by the user in ctx, if any
+	CreateSilence(ctx context.Context, silence k8s.Silence) (silenceId string, err error)
+
+	// UpdateSilence replaces an Alertmanager silence by its ID and returns its new ID, which differs from
+	// silenceId if Alertmanager expired the silence and created a new one
+	UpdateSilence(ctx context.Context, silenceId string, silence k8s.Silence) (newSilenceId string, err error)
+
+	// ExpireSilence ends an Alertmanager silence by its ID
+	ExpireSilence(ctx context.Context, silenceId string) error
+
+	// SilenceAlert creates an Alertmanager silence matching the labels of an alert and returns its ID
+	SilenceAlert(ctx context.Context, alertLabels map[string]string, opts SilenceOptions) (silenceId string, err error)
+
+	// SilenceAlertRule creates an Alertmanager silence matching all alerts of an alert rule by its alertname
+	// and static labels, and returns its ID
+	SilenceAlertRule(ctx context.Context, alertRuleId string, opts SilenceOptions) (silenceId string, err error)
+
+	// FindPrometheusRuleByAlertRuleId returns the namespaced name of the PrometheusRule containing the alert rule
+	FindPrometheusRuleByAlertRuleId(ctx context.Context, alertRuleId string) (types.NamespacedName, error)
+
+	// GetPrometheusRuleSource returns the source type of the alert rules of the PrometheusRule (platform or user-defined)
+	GetPrometheusRuleSource(ctx context.Context, prId types.NamespacedName) (string, error)
+
+	// CheckReadiness reports whether the client is able to serve requests, with the status of each component
+	CheckReadiness(ctx context.Context) ReadinessStatus
+}
+
+// Rule is an alerting or recording rule with AlertRelabelConfigs applied and its ID in the alert_rule_id label
+type Rule struct {
+	monitoringv1.Rule `json:",inline"`
+
+	// Type is the type of the rule (alerting or recording)
+	Type string `json:"type"`
+
+	// Source is the source type of the rule (platform or user-defined)
+	Source string `json:"source"`
+
+	// Status is the evaluation status of the rule in Prometheus, nil if the Prometheus rules API cannot be reached
+	Status *RuleStatus `json:"status,omitempty"`
+}
+
+// RuleStatus is the evaluation status of a rule in Prometheus
+type RuleStatus struct {
+	// Loaded is false if no Prometheus loaded the rule, e.g. because no Prometheus selects its PrometheusRule
+	Loaded bool `json:"loaded"`
+
+	// Health is the health of the last evaluation: ok, err or unknown
+	Health string `json:"health,omitempty"`
+
+	// LastError is the error of the last evaluation, if it failed
+	LastError string `json:"lastError,omitempty"`
+
+	// LastEvaluation is the time the rule was last evaluated, nil if it was not evaluated yet
+	LastEvaluation *time.Time `json:"lastEvaluation,omitempty"`
+
+	// EvaluationTime is the duration of the last evaluation in seconds
+	EvaluationTime float64 `json:"evaluationTime,omitempty"`
+
+	// State is the state of alerting rules: inactive, pending or firing
+	State string `json:"state,omitempty"`
+}
+
+// RuleGroup is a rule group of a PrometheusRule with the number of its rules instead of the rules
+type RuleGroup struct {
+	monitoringv1.RuleGroup `json:",inline"`
+
+	// PrometheusRuleNamespace is the namespace of the PrometheusRule containing the group
+	PrometheusRuleNamespace string `json:"prometheusRuleNamespace"`
+
+	// PrometheusRuleName is the name of the PrometheusRule containing the group
+	PrometheusRuleName string `json:"prometheusRuleName"`
+
+	// RuleCount is the number of rules in the group
+	RuleCount int `json:"ruleCount"`
+
+	// Source is the source type of the PrometheusRule (platform or user-defined)
+	Source string `json:"source"`
+}
+
+// PlatformClassifier decides whether the alert rules of PrometheusRules are platform-managed
+type PlatformClassifier interface {
+	// IsPlatform returns true if the alert rules of the PrometheusRule are platform-managed.
+	// PrometheusRules that do not exist yet only have their namespace and name set
+	IsPlatform(ctx context.Context, pr metav1.Object) (bool, error)
+}
+
+// ClassificationOptions configures which PrometheusRules are platform-managed.
+// PrometheusRules in the DenyList are user-defined, and otherwise PrometheusRules
+// matching any of the other options are platform-managed
+type ClassificationOptions struct {
+	// NamespacePrefixes are prefixes of the namespaces of platform PrometheusRules
+	NamespacePrefixes []string
+
+	// NamespaceSelector selects the namespaces of platform PrometheusRules by their labels,
+	// e.g. openshift.io/cluster-monitoring=true
+	NamespaceSelector labels.Selector
+
+	// PrometheusRuleSelector selects platform PrometheusRules by their labels
+	PrometheusRuleSelector labels.Selector
+
+	// OwnerKinds are the kinds of the owners of platform PrometheusRules, e.g. the custom resource of an operator
+	OwnerKinds []schema.GroupKind
+
+	// AllowList lists platform PrometheusRules. Entries without name match all PrometheusRules in the namespace
+	AllowList []types.NamespacedName
+
+	// DenyList lists user-defined PrometheusRules and takes precedence over all other options.
+	// Entries without name match all PrometheusRules in the namespace
+	DenyList []types.NamespacedName
 }
 
 // PrometheusRuleOptions specifies options for selecting PrometheusRule resources and groups
@@ -45,13 +209,180 @@ type PrometheusRuleOptions struct {
 	GroupName string `json:"groupName"`
 }
 
+// PlatformAlertRelabelConfigNamespace is the namespace of the AlertRelabelConfigs written for platform alert rules
+const PlatformAlertRelabelConfigNamespace = openshiftMonitoringNamespace
+
+const (
+	// SourcePlatform identifies alert rules from platform-managed PrometheusRules
+	SourcePlatform = "platform"
+
+	// SourceUserDefined identifies alert rules from user-defined PrometheusRules
+	SourceUserDefined = "user-defined"
+)
+
+const (
+	// RuleTypeAlerting identifies alerting rules
+	RuleTypeAlerting = "alerting"
+
+	// RuleTypeRecording identifies recording rules
+	RuleTypeRecording = "recording"
+)
+
 type AlertRuleOptions struct {
-	// Name filters alert rules by alert name
+	// Name filters rules by alert name or recorded metric name
 	Name string `json:"name,omitempty"`
 
+	// Type filters rules by type (alerting or recording), both are listed if empty
+	Type string `json:"type,omitempty"`
+
 	// Source filters alert rules by source type (platform or user-defined)
 	Source string `json:"source,omitempty"`
 
 	// Labels filters alert rules by arbitrary label key-value pairs
 	Labels map[string]string `json:"labels,omitempty"`
+
+	// Loaded filters rules by whether Prometheus loaded them, all rules are listed if nil
+	Loaded *bool `json:"loaded,omitempty"`
+}
+
+// SilenceOptions configures the silences created for alerts and alert rules
+type SilenceOptions struct {
+	// StartsAt is the time the silence starts, defaults to now
+	StartsAt time.Time `json:"startsAt,omitempty"`
+
+	// EndsAt is the time the silence ends
+	EndsAt time.Time `json:"endsAt"`
+
+	// CreatedBy is the author of the silence, replaced by the user of the request, if any
+	CreatedBy string `json:"createdBy,omitempty"`
+
+	// Comment explains why the alerts are silenced
+	Comment string `json:"comment"`
+}
+
+// AlertHistoryFilters selects the alerts returned by GetAlertHistory
+type AlertHistoryFilters struct {
+	// Labels filters alerts by label key-value pairs, before AlertRelabelConfigs are applied
+	Labels map[string]string
+
+	// AlertRuleId only returns the alerts of the rule
+	AlertRuleId string
+
+	// Step is the resolution of the history, defaults to 30s. It is increased for long ranges to stay
+	// within the number of samples Prometheus returns per series
+	Step time.Duration
+}
+
+// AlertHistory is the firing history of the alerts with a label set
+type AlertHistory struct {
+	// Labels are the labels of the alerts, with AlertRelabelConfigs applied
+	Labels map[string]string `json:"labels"`
+
+	// AlertRuleId is the ID of the rule that produced the alerts, if it is found
+	AlertRuleId string `json:"alertRuleId,omitempty"`
+
+	// PrometheusRuleNamespace is the namespace of the PrometheusRule of the rule
+	PrometheusRuleNamespace string `json:"prometheusRuleNamespace,omitempty"`
+
+	// PrometheusRuleName is the name of the PrometheusRule of the rule
+	PrometheusRuleName string `json:"prometheusRuleName,omitempty"`
+
+	// Source is the source of the rule: platform or user-defined
+	Source string `json:"source,omitempty"`
+
+	// Intervals are the periods during which the alerts fired, ordered by time
+	Intervals []FiringInterval `json:"intervals"`
+}
+
+// FiringInterval is a period during which an alert fired without interruption
+type FiringInterval struct {
+	// ActiveAt is the time the alert became pending, from ALERTS_FOR_STATE, nil if unknown
+	ActiveAt *time.Time `json:"activeAt,omitempty"`
+
+	// Start is the first evaluation of the history at which the alert was firing
+	Start time.Time `json:"start"`
+
+	// End is the last evaluation of the history at which the alert was firing
+	End time.Time `json:"end"`
+
+	// Ongoing is true if the alert was still firing at the end of the history
+	Ongoing bool `json:"ongoing"`
+}
+
+// UpdateAlertRuleLabelsResult describes the changes produced by UpdateAlertRuleLabels
+type UpdateAlertRuleLabelsResult struct {
+	// AlertRuleId is the ID of the alert rule after the update
+	AlertRuleId string `json:"alertRuleId"`
+
+	// Source is the source type of the alert rule (platform or user-defined)
+	Source string `json:"source"`
+
+	// LabelChanges lists the label changes applied to the alert rule
+	LabelChanges []LabelChange `json:"labelChanges"`
+
+	// AlertRelabelConfig is the namespaced name of the AlertRelabelConfig written for platform alert rules
+	AlertRelabelConfig string `json:"alertRelabelConfig,omitempty"`
+
+	// AlertRelabelConfigDeleted is true if the labels of a platform alert rule were set back to the labels of
+	// its PrometheusRule, and its AlertRelabelConfig was deleted
+	AlertRelabelConfigDeleted bool `json:"alertRelabelConfigDeleted,omitempty"`
+
+	// RelabelConfigs are the relabel configs of the AlertRelabelConfig written for platform alert rules
+	RelabelConfigs []osmv1.RelabelConfig `json:"relabelConfigs,omitempty"`
+
+	// PrometheusRule is the namespaced name of the PrometheusRule edited for user-defined alert rules
+	PrometheusRule string `json:"prometheusRule,omitempty"`
+}
+
+// LabelChange describes a change to a single label of an alert rule
+type LabelChange struct {
+	// Operation is the type of change: add, update or remove
+	Operation string `json:"operation"`
+
+	// Label is the name of the changed label
+	Label string `json:"label"`
+
+	// OldValue is the value of the label before the change
+	OldValue string `json:"oldValue,omitempty"`
+
+	// NewValue is the value of the label after the change
+	NewValue string `json:"newValue,omitempty"`
+}
+
+const (
+	// ComponentPrometheusRuleInformer is the readiness component for the PrometheusRule informer
+	ComponentPrometheusRuleInformer = "prometheusRuleInformer"
+
+	// ComponentAlertRelabelConfigInformer is the readiness component for the AlertRelabelConfig informer
+	ComponentAlertRelabelConfigInformer = "alertRelabelConfigInformer"
+
+	// ComponentPrometheusAlerts is the readiness component for the Prometheus alerts API
+	ComponentPrometheusAlerts = "prometheusAlerts"
+
+	// ComponentAPIServer is the readiness component for the Kubernetes API server
+	ComponentAPIServer = "apiServer"
+)
+
+// ReadinessStatus describes whether the client is able to serve requests
+type ReadinessStatus struct {
+	// Ready is true when all components are ready
+	Ready bool `json:"ready"`
+
+	// Components lists the status of each component
+	Components []ComponentStatus `json:"components"`
+}
+
+// ComponentStatus describes the readiness of a single component
+type ComponentStatus struct {
+	// Name of the component
+	Name string `json:"name"`
+
+	// Ready is true when the component is able to serve requests
+	Ready bool `json:"ready"`
+
+	// Message explains why the component is not ready
+	Message string `json:"message,omitempty"`
+
+	// LastSuccess is the time of the last successful call to the component, if tracked
+	LastSuccess *time.Time `json:"lastSuccess,omitempty"`
 }
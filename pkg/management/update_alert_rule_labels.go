@@ -0,0 +1,109 @@
+package management
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func (c *client) UpdateAlertRuleLabels(ctx context.Context, alertRuleId string, labels map[string]string) (*UpdateAlertRuleLabelsResult, error) {
+	prId, err := c.findPrometheusRuleId(alertRuleId)
+	if err != nil {
+		return nil, err
+	}
+
+	originalRule, err := c.getOriginalRule(ctx, prId, alertRuleId)
+	if err != nil {
+		return nil, err
+	}
+
+	// alert_rule_id is only added to the rules returned to the caller and
+	// must not be written back
+	desiredLabels := make(map[string]string, len(labels))
+	for key, value := range labels {
+		if key == alertRuleIdLabel {
+			continue
+		}
+		desiredLabels[key] = value
+	}
+
+	// originalRule points into the stored PrometheusRule, keep a copy of its
+	// labels to report the changes after the update is applied
+	originalLabels := make(map[string]string, len(originalRule.Labels))
+	for key, value := range originalRule.Labels {
+		originalLabels[key] = value
+	}
+
+	updatedRule := *originalRule
+	updatedRule.Labels = desiredLabels
+
+	source, err := c.GetPrometheusRuleSource(ctx, types.NamespacedName(*prId))
+	if err != nil {
+		return nil, err
+	}
+
+	if source == SourcePlatform {
+		changes, arc, deleted, err := c.updatePlatformAlertRule(ctx, alertRuleId, updatedRule)
+		if err != nil {
+			return nil, err
+		}
+
+		result := &UpdateAlertRuleLabelsResult{
+			AlertRuleId:  alertRuleId,
+			Source:       SourcePlatform,
+			LabelChanges: changes,
+		}
+		if arc != nil {
+			result.AlertRelabelConfig = fmt.Sprintf("%s/%s", arc.Namespace, arc.Name)
+			result.AlertRelabelConfigDeleted = deleted
+			if !deleted {
+				result.RelabelConfigs = arc.Spec.Configs
+			}
+		}
+		return result, nil
+	}
+
+	// Setting the current labels is a no-op, so that requests can be retried
+	changes := calculateLabelChanges(originalLabels, desiredLabels)
+	if len(changes) == 0 {
+		return &UpdateAlertRuleLabelsResult{
+			AlertRuleId:    alertRuleId,
+			Source:         SourceUserDefined,
+			LabelChanges:   []LabelChange{},
+			PrometheusRule: fmt.Sprintf("%s/%s", prId.Namespace, prId.Name),
+		}, nil
+	}
+
+	newAlertRuleId, err := c.UpdateUserDefinedAlertRule(ctx, alertRuleId, updatedRule)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UpdateAlertRuleLabelsResult{
+		AlertRuleId:    newAlertRuleId,
+		Source:         SourceUserDefined,
+		LabelChanges:   toLabelChanges(originalLabels, changes),
+		PrometheusRule: fmt.Sprintf("%s/%s", prId.Namespace, prId.Name),
+	}, nil
+}
+
+func toLabelChanges(originalLabels map[string]string, changes []labelChange) []LabelChange {
+	result := make([]LabelChange, 0, len(changes))
+
+	for _, change := range changes {
+		label := change.label()
+		oldValue, existed := originalLabels[label]
+
+		switch {
+		case change.action == "LabelDrop":
+			result = append(result, LabelChange{Operation: "remove", Label: label, OldValue: oldValue})
+		case existed:
+			result = append(result, LabelChange{Operation: "update", Label: label, OldValue: oldValue, NewValue: change.value})
+		default:
+			result = append(result, LabelChange{Operation: "add", Label: label, NewValue: change.value})
+		}
+	}
+
+	return result
+}
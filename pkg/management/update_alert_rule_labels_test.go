@@ -0,0 +1,213 @@
+package management_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management/mapper"
+	"github.com/machadovilaca/alerts-ui-management/pkg/management/testutils"
+)
+
+var _ = Describe("UpdateAlertRuleLabels", func() {
+	var (
+		ctx        context.Context
+		mockK8s    *testutils.MockClient
+		mockPR     *testutils.MockPrometheusRuleInterface
+		mockARC    *testutils.MockAlertRelabelConfigInterface
+		mockMapper *testutils.MockMapperClient
+		client     management.Client
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		mockPR = &testutils.MockPrometheusRuleInterface{}
+		mockARC = &testutils.MockAlertRelabelConfigInterface{}
+		mockK8s = &testutils.MockClient{
+			PrometheusRulesFunc: func() k8s.PrometheusRuleInterface {
+				return mockPR
+			},
+			AlertRelabelConfigsFunc: func() k8s.AlertRelabelConfigInterface {
+				return mockARC
+			},
+		}
+
+		mockPR.SetPrometheusRules(map[string]*monitoringv1.PrometheusRule{
+			"openshift-monitoring/platform-rules": {
+				ObjectMeta: metav1.ObjectMeta{Name: "platform-rules", Namespace: "openshift-monitoring"},
+				Spec: monitoringv1.PrometheusRuleSpec{
+					Groups: []monitoringv1.RuleGroup{{
+						Name: "platform-group",
+						Rules: []monitoringv1.Rule{{
+							Alert:  "PlatformAlert",
+							Expr:   intstr.FromString("up == 0"),
+							Labels: map[string]string{"severity": "warning", "team": "platform"},
+						}},
+					}},
+				},
+			},
+			"user-namespace/user-rules": {
+				ObjectMeta: metav1.ObjectMeta{Name: "user-rules", Namespace: "user-namespace"},
+				Spec: monitoringv1.PrometheusRuleSpec{
+					Groups: []monitoringv1.RuleGroup{{
+						Name: "user-group",
+						Rules: []monitoringv1.Rule{{
+							Alert:  "UserAlert",
+							Expr:   intstr.FromString("up == 0"),
+							Labels: map[string]string{"severity": "warning", "team": "app"},
+						}},
+					}},
+				},
+			},
+		})
+
+		mockMapper = &testutils.MockMapperClient{
+			FindAlertRuleByIdFunc: func(id mapper.PrometheusAlertRuleId) (*mapper.PrometheusRuleId, error) {
+				switch id {
+				case "platform-rule-id":
+					return &mapper.PrometheusRuleId{Namespace: "openshift-monitoring", Name: "platform-rules"}, nil
+				case "user-rule-id":
+					return &mapper.PrometheusRuleId{Namespace: "user-namespace", Name: "user-rules"}, nil
+				}
+				return nil, errors.New("alert rule not found")
+			},
+			GetAlertingRuleIdFunc: func(alertRule *monitoringv1.Rule) mapper.PrometheusAlertRuleId {
+				switch {
+				case alertRule.Alert == "PlatformAlert":
+					return "platform-rule-id"
+				case alertRule.Alert == "UserAlert" && alertRule.Labels["severity"] == "warning":
+					return "user-rule-id"
+				}
+				return "updated-user-rule-id"
+			},
+		}
+
+		client = management.NewWithCustomMapper(ctx, mockK8s, mockMapper)
+	})
+
+	Context("when updating labels of a platform alert rule", func() {
+		It("should write an AlertRelabelConfig and report the relabel configs", func() {
+			result, err := client.UpdateAlertRuleLabels(ctx, "platform-rule-id", map[string]string{
+				"severity":      "critical",
+				"alert_rule_id": "platform-rule-id",
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(result.AlertRuleId).To(Equal("platform-rule-id"))
+			Expect(result.Source).To(Equal(management.SourcePlatform))
+			Expect(result.AlertRelabelConfig).To(Equal("openshift-monitoring/alertmanagement-platform-rule-id"))
+			Expect(result.RelabelConfigs).To(HaveLen(2))
+			Expect(result.PrometheusRule).To(BeEmpty())
+			Expect(result.LabelChanges).To(Equal([]management.LabelChange{
+				{Operation: "update", Label: "severity", OldValue: "warning", NewValue: "critical"},
+				{Operation: "remove", Label: "team", OldValue: "platform"},
+			}))
+
+			arc, found, err := mockARC.Get(ctx, "openshift-monitoring", "alertmanagement-platform-rule-id")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(arc.Spec.Configs).To(Equal(result.RelabelConfigs))
+		})
+
+		It("should delete the AlertRelabelConfig when the labels are reverted", func() {
+			By("overriding the labels")
+			_, err := client.UpdateAlertRuleLabels(ctx, "platform-rule-id", map[string]string{
+				"severity": "critical",
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			By("setting the labels of the PrometheusRule again")
+			result, err := client.UpdateAlertRuleLabels(ctx, "platform-rule-id", map[string]string{
+				"severity": "warning",
+				"team":     "platform",
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(result.AlertRelabelConfig).To(Equal("openshift-monitoring/alertmanagement-platform-rule-id"))
+			Expect(result.AlertRelabelConfigDeleted).To(BeTrue())
+			Expect(result.RelabelConfigs).To(BeEmpty())
+			Expect(result.LabelChanges).To(Equal([]management.LabelChange{
+				{Operation: "update", Label: "severity", OldValue: "critical", NewValue: "warning"},
+				{Operation: "add", Label: "team", NewValue: "platform"},
+			}))
+
+			_, found, err := mockARC.Get(ctx, "openshift-monitoring", "alertmanagement-platform-rule-id")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeFalse())
+
+			By("reverting again without an AlertRelabelConfig")
+			result, err = client.UpdateAlertRuleLabels(ctx, "platform-rule-id", map[string]string{
+				"severity": "warning",
+				"team":     "platform",
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.AlertRelabelConfig).To(BeEmpty())
+			Expect(result.AlertRelabelConfigDeleted).To(BeFalse())
+			Expect(result.LabelChanges).To(BeEmpty())
+		})
+	})
+
+	Context("when updating labels of a user-defined alert rule", func() {
+		It("should update the PrometheusRule and report the new rule ID", func() {
+			result, err := client.UpdateAlertRuleLabels(ctx, "user-rule-id", map[string]string{
+				"severity": "critical",
+				"team":     "app",
+				"owner":    "sre",
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(result.AlertRuleId).To(Equal("updated-user-rule-id"))
+			Expect(result.Source).To(Equal(management.SourceUserDefined))
+			Expect(result.PrometheusRule).To(Equal("user-namespace/user-rules"))
+			Expect(result.RelabelConfigs).To(BeEmpty())
+			Expect(result.LabelChanges).To(Equal([]management.LabelChange{
+				{Operation: "add", Label: "owner", NewValue: "sre"},
+				{Operation: "update", Label: "severity", OldValue: "warning", NewValue: "critical"},
+			}))
+
+			pr, _, err := mockPR.Get(ctx, "user-namespace", "user-rules")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pr.Spec.Groups[0].Rules[0].Labels).To(Equal(map[string]string{
+				"severity": "critical",
+				"team":     "app",
+				"owner":    "sre",
+			}))
+			Expect(pr.Spec.Groups[0].Rules[0].Expr.String()).To(Equal("up == 0"))
+
+			arcs, err := mockARC.List(ctx, "")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(arcs).To(BeEmpty())
+		})
+
+		It("should not write the PrometheusRule when the labels are unchanged", func() {
+			mockPR.UpdateFunc = func(ctx context.Context, pr monitoringv1.PrometheusRule) error {
+				Fail("the PrometheusRule should not be updated")
+				return nil
+			}
+
+			result, err := client.UpdateAlertRuleLabels(ctx, "user-rule-id", map[string]string{
+				"severity": "warning",
+				"team":     "app",
+			})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.AlertRuleId).To(Equal("user-rule-id"))
+			Expect(result.LabelChanges).To(BeEmpty())
+		})
+	})
+
+	It("should return a NotFoundError when the alert rule does not exist", func() {
+		_, err := client.UpdateAlertRuleLabels(ctx, "missing", map[string]string{"severity": "critical"})
+
+		var notFoundErr *management.NotFoundError
+		Expect(errors.As(err, &notFoundErr)).To(BeTrue())
+	})
+})
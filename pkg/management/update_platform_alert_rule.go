@@ -2,14 +2,16 @@ package management
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"regexp"
+	"sort"
 	"strings"
 
 	osmv1 "github.com/openshift/api/monitoring/v1"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	"github.com/machadovilaca/alerts-ui-management/pkg/management/mapper"
 )
@@ -17,29 +19,88 @@ import (
 const openshiftMonitoringNamespace = "openshift-monitoring"
 
 func (c *client) UpdatePlatformAlertRule(ctx context.Context, alertRuleId string, alertRule monitoringv1.Rule) error {
-	prId, err := c.mapper.FindAlertRuleById(mapper.PrometheusAlertRuleId(alertRuleId))
+	_, _, _, err := c.updatePlatformAlertRule(ctx, alertRuleId, alertRule)
+	return err
+}
+
+// updatePlatformAlertRule overrides the labels of a platform alert rule with an AlertRelabelConfig, or deletes
+// the AlertRelabelConfig if the labels are the ones of the PrometheusRule. It returns the changes applied, the
+// written AlertRelabelConfig and whether it was deleted
+func (c *client) updatePlatformAlertRule(ctx context.Context, alertRuleId string, alertRule monitoringv1.Rule) ([]LabelChange, *osmv1.AlertRelabelConfig, bool, error) {
+	prId, err := c.findPrometheusRuleId(alertRuleId)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	source, err := c.GetPrometheusRuleSource(ctx, types.NamespacedName(*prId))
 	if err != nil {
-		return err
+		return nil, nil, false, err
 	}
 
-	if !IsPlatformAlertRule(types.NamespacedName(*prId)) {
-		return errors.New("cannot update non-platform alert rule from " + prId.Namespace + "/" + prId.Name)
+	if source != SourcePlatform {
+		return nil, nil, false, &NotAllowedError{Message: "cannot update non-platform alert rule from " + prId.Namespace + "/" + prId.Name}
 	}
 
-	originalRule, err := c.getOriginalPlatformRule(ctx, prId, alertRuleId)
+	originalRule, err := c.getOriginalRule(ctx, prId, alertRuleId)
 	if err != nil {
-		return err
+		return nil, nil, false, err
 	}
 
+	if originalRule.Record != "" {
+		return nil, nil, false, &NotAllowedError{Message: "cannot update platform recording rule, AlertRelabelConfigs only apply to alerts"}
+	}
+
+	// Setting the labels of the PrometheusRule reverts the override, so that
+	// Alertmanager receives the labels shown for the rule again
 	labelChanges := calculateLabelChanges(originalRule.Labels, alertRule.Labels)
 	if len(labelChanges) == 0 {
-		return errors.New("no label changes detected; platform alert rules can only have labels updated")
+		changes, arc, err := c.deleteAlertRelabelConfig(ctx, alertRuleId, originalRule)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		return changes, arc, arc != nil, nil
+	}
+
+	arc, err := c.applyLabelChangesViaAlertRelabelConfig(ctx, alertRuleId, originalRule.Alert, labelChanges)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	return toLabelChanges(originalRule.Labels, labelChanges), arc, false, nil
+}
+
+// deleteAlertRelabelConfig deletes the AlertRelabelConfig overriding the labels of a platform alert rule, if
+// any, and returns the changes from the overridden labels back to the labels of the rule
+func (c *client) deleteAlertRelabelConfig(ctx context.Context, alertRuleId string, originalRule *monitoringv1.Rule) ([]LabelChange, *osmv1.AlertRelabelConfig, error) {
+	arcName := alertRelabelConfigName(alertRuleId)
+
+	k8sClient, err := c.k8sClientFor(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	arc, found, err := k8sClient.AlertRelabelConfigs().Get(ctx, openshiftMonitoringNamespace, arcName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get AlertRelabelConfig %s/%s: %w", openshiftMonitoringNamespace, arcName, err)
+	}
+	if !found {
+		return []LabelChange{}, nil, nil
+	}
+
+	overriddenLabels, err := applyRelabelConfigs(originalRule.Alert, originalRule.Labels, arc.Spec.Configs)
+	if err != nil {
+		// The override dropped the alert, so all its labels are restored
+		overriddenLabels = map[string]string{}
+	}
+
+	if err := k8sClient.AlertRelabelConfigs().Delete(ctx, openshiftMonitoringNamespace, arcName); err != nil {
+		return nil, nil, fmt.Errorf("failed to delete AlertRelabelConfig %s/%s: %w", openshiftMonitoringNamespace, arcName, err)
 	}
 
-	return c.applyLabelChangesViaAlertRelabelConfig(ctx, alertRuleId, originalRule.Alert, labelChanges)
+	return toLabelChanges(overriddenLabels, calculateLabelChanges(overriddenLabels, originalRule.Labels)), arc, nil
 }
 
-func (c *client) getOriginalPlatformRule(ctx context.Context, prId *mapper.PrometheusRuleId, alertRuleId string) (*monitoringv1.Rule, error) {
+func (c *client) getOriginalRule(ctx context.Context, prId *mapper.PrometheusRuleId, alertRuleId string) (*monitoringv1.Rule, error) {
 	pr, found, err := c.k8sClient.PrometheusRules().Get(ctx, prId.Namespace, prId.Name)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get PrometheusRule %s/%s: %w", prId.Namespace, prId.Name, err)
@@ -49,13 +110,12 @@ func (c *client) getOriginalPlatformRule(ctx context.Context, prId *mapper.Prome
 		return nil, &NotFoundError{Resource: "PrometheusRule", Id: fmt.Sprintf("%s/%s", prId.Namespace, prId.Name)}
 	}
 
-	for groupIdx := range pr.Spec.Groups {
-		for ruleIdx := range pr.Spec.Groups[groupIdx].Rules {
-			rule := &pr.Spec.Groups[groupIdx].Rules[ruleIdx]
-			if c.shouldUpdateRule(*rule, alertRuleId) {
-				return rule, nil
-			}
-		}
+	if err := checkResourceVersion(ctx, pr); err != nil {
+		return nil, err
+	}
+
+	if groupIdx, ruleIdx, found := c.findRule(pr, alertRuleId); found {
+		return &pr.Spec.Groups[groupIdx].Rules[ruleIdx], nil
 	}
 
 	return nil, fmt.Errorf("alert rule with id %s not found in PrometheusRule %s/%s", alertRuleId, prId.Namespace, prId.Name)
@@ -97,15 +157,32 @@ func calculateLabelChanges(originalLabels, newLabels map[string]string) []labelC
 		}
 	}
 
+	// Sort changes by label name so that the generated relabel configs are stable
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].label() < changes[j].label()
+	})
+
 	return changes
 }
 
-func (c *client) applyLabelChangesViaAlertRelabelConfig(ctx context.Context, alertRuleId string, alertName string, changes []labelChange) error {
-	arcName := fmt.Sprintf("alertmanagement-%s", strings.ToLower(strings.ReplaceAll(alertRuleId, "/", "-")))
+func (lc labelChange) label() string {
+	if lc.action == "LabelDrop" {
+		return lc.sourceLabel
+	}
+	return lc.targetLabel
+}
+
+func (c *client) applyLabelChangesViaAlertRelabelConfig(ctx context.Context, alertRuleId string, alertName string, changes []labelChange) (*osmv1.AlertRelabelConfig, error) {
+	arcName := alertRelabelConfigName(alertRuleId)
+
+	k8sClient, err := c.k8sClientFor(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-	existingArc, found, err := c.k8sClient.AlertRelabelConfigs().Get(ctx, openshiftMonitoringNamespace, arcName)
+	existingArc, found, err := k8sClient.AlertRelabelConfigs().Get(ctx, openshiftMonitoringNamespace, arcName)
 	if err != nil {
-		return fmt.Errorf("failed to get AlertRelabelConfig %s/%s: %w", openshiftMonitoringNamespace, arcName, err)
+		return nil, fmt.Errorf("failed to get AlertRelabelConfig %s/%s: %w", openshiftMonitoringNamespace, arcName, err)
 	}
 
 	relabelConfigs := c.buildRelabelConfigs(alertName, changes)
@@ -117,9 +194,9 @@ func (c *client) applyLabelChangesViaAlertRelabelConfig(ctx context.Context, ale
 			Configs: relabelConfigs,
 		}
 
-		err = c.k8sClient.AlertRelabelConfigs().Update(ctx, *arc)
+		err = k8sClient.AlertRelabelConfigs().Update(ctx, *arc)
 		if err != nil {
-			return fmt.Errorf("failed to update AlertRelabelConfig %s/%s: %w", arc.Namespace, arc.Name, err)
+			return nil, fmt.Errorf("failed to update AlertRelabelConfig %s/%s: %w", arc.Namespace, arc.Name, err)
 		}
 	} else {
 		arc = &osmv1.AlertRelabelConfig{
@@ -132,13 +209,29 @@ func (c *client) applyLabelChangesViaAlertRelabelConfig(ctx context.Context, ale
 			},
 		}
 
-		_, err = c.k8sClient.AlertRelabelConfigs().Create(ctx, *arc)
+		_, err = k8sClient.AlertRelabelConfigs().Create(ctx, *arc)
 		if err != nil {
-			return fmt.Errorf("failed to create AlertRelabelConfig %s/%s: %w", arc.Namespace, arc.Name, err)
+			return nil, fmt.Errorf("failed to create AlertRelabelConfig %s/%s: %w", arc.Namespace, arc.Name, err)
 		}
 	}
 
-	return nil
+	return arc, nil
+}
+
+// invalidResourceNameChars matches the characters that cannot be used in the names of Kubernetes resources
+var invalidResourceNameChars = regexp.MustCompile(`[^a-z0-9.-]`)
+
+// alertRelabelConfigName returns the name of the AlertRelabelConfig overriding the labels of a platform alert rule
+// The name is derived from the unqualified ID, so that the rule keeps its AlertRelabelConfig whether or not
+// it is referenced with its location
+func alertRelabelConfigName(alertRuleId string) string {
+	baseId, _ := mapper.ParseAlertingRuleId(mapper.PrometheusAlertRuleId(alertRuleId))
+
+	name := "alertmanagement-" + invalidResourceNameChars.ReplaceAllString(strings.ToLower(string(baseId)), "-")
+	if len(name) > validation.DNS1123SubdomainMaxLength {
+		name = name[:validation.DNS1123SubdomainMaxLength]
+	}
+	return strings.TrimRight(name, ".-")
 }
 
 func (c *client) buildRelabelConfigs(alertName string, changes []labelChange) []osmv1.RelabelConfig {
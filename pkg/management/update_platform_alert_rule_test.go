@@ -136,6 +136,53 @@ var _ = Describe("UpdatePlatformAlertRule", func() {
 			Expect(ownerAdd).To(BeTrue())
 		})
 
+		It("should name the AlertRelabelConfig after the unqualified ID", func() {
+			existingRule := monitoringv1.Rule{
+				Alert:  "PlatformAlert",
+				Expr:   intstr.FromString("up == 0"),
+				Labels: map[string]string{"severity": "warning"},
+			}
+
+			mockPR.SetPrometheusRules(map[string]*monitoringv1.PrometheusRule{
+				"openshift-monitoring/openshift-platform-alerts": {
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "openshift-platform-alerts",
+						Namespace: "openshift-monitoring",
+					},
+					Spec: monitoringv1.PrometheusRuleSpec{
+						Groups: []monitoringv1.RuleGroup{
+							{
+								Name:  "platform-group",
+								Rules: []monitoringv1.Rule{existingRule},
+							},
+						},
+					},
+				},
+			})
+
+			alertRuleId := "Test_Platform_Rule_Id@openshift-monitoring/openshift-platform-alerts/0/0"
+			mockMapper.FindAlertRuleByIdFunc = func(id mapper.PrometheusAlertRuleId) (*mapper.PrometheusRuleId, error) {
+				return &mapper.PrometheusRuleId{
+					Namespace: "openshift-monitoring",
+					Name:      "openshift-platform-alerts",
+				}, nil
+			}
+			mockMapper.MatchesAlertingRuleIdFunc = func(alertRule *monitoringv1.Rule, id mapper.PrometheusAlertRuleId) bool {
+				return alertRule.Alert == "PlatformAlert" && id == "Test_Platform_Rule_Id"
+			}
+
+			updatedRule := existingRule
+			updatedRule.Labels = map[string]string{"severity": "critical"}
+
+			err := client.UpdatePlatformAlertRule(ctx, alertRuleId, updatedRule)
+			Expect(err).ToNot(HaveOccurred())
+
+			arcs, err := mockARC.List(ctx, "openshift-monitoring")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(arcs).To(HaveLen(1))
+			Expect(arcs[0].Name).To(Equal("alertmanagement-test-platform-rule-id"))
+		})
+
 		It("should update existing AlertRelabelConfig when one already exists", func() {
 			By("setting up the existing platform rule and AlertRelabelConfig")
 			existingRule := monitoringv1.Rule{
@@ -163,7 +210,7 @@ var _ = Describe("UpdatePlatformAlertRule", func() {
 
 			existingARC := &osmv1.AlertRelabelConfig{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:      "test-platform-rule-id-relabel",
+					Name:      "alertmanagement-test-platform-rule-id",
 					Namespace: "openshift-monitoring",
 				},
 				Spec: osmv1.AlertRelabelConfigSpec{
@@ -318,9 +365,12 @@ var _ = Describe("UpdatePlatformAlertRule", func() {
 			err := client.UpdatePlatformAlertRule(ctx, alertRuleId, updatedRule)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("cannot update non-platform alert rule"))
+
+			var notAllowedErr *management.NotAllowedError
+			Expect(errors.As(err, &notAllowedErr)).To(BeTrue())
 		})
 
-		It("should return error when no label changes detected", func() {
+		It("should delete the AlertRelabelConfig when the labels are reverted", func() {
 			By("setting up the existing platform rule")
 			existingRule := monitoringv1.Rule{
 				Alert: "PlatformAlert",
@@ -363,7 +413,17 @@ var _ = Describe("UpdatePlatformAlertRule", func() {
 				return mapper.PrometheusAlertRuleId("other-id")
 			}
 
-			By("updating with same labels")
+			By("overriding the labels")
+			err := client.UpdatePlatformAlertRule(ctx, alertRuleId, monitoringv1.Rule{
+				Alert:  "PlatformAlert",
+				Labels: map[string]string{"severity": "critical"},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			_, found, err := mockARC.Get(ctx, "openshift-monitoring", "alertmanagement-test-platform-rule-id")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeTrue())
+
+			By("updating with the labels of the PrometheusRule")
 			updatedRule := monitoringv1.Rule{
 				Alert: "PlatformAlert",
 				Expr:  intstr.FromString("up == 0"),
@@ -372,9 +432,11 @@ var _ = Describe("UpdatePlatformAlertRule", func() {
 				},
 			}
 
-			err := client.UpdatePlatformAlertRule(ctx, alertRuleId, updatedRule)
-			Expect(err).To(HaveOccurred())
-			Expect(err.Error()).To(ContainSubstring("no label changes detected"))
+			err = client.UpdatePlatformAlertRule(ctx, alertRuleId, updatedRule)
+			Expect(err).ToNot(HaveOccurred())
+			_, found, err = mockARC.Get(ctx, "openshift-monitoring", "alertmanagement-test-platform-rule-id")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeFalse())
 		})
 
 		It("should return error when alert rule not found", func() {
@@ -394,7 +456,10 @@ var _ = Describe("UpdatePlatformAlertRule", func() {
 
 			err := client.UpdatePlatformAlertRule(ctx, alertRuleId, updatedRule)
 			Expect(err).To(HaveOccurred())
-			Expect(err.Error()).To(ContainSubstring("alert rule not found"))
+
+			var notFoundErr *management.NotFoundError
+			Expect(errors.As(err, &notFoundErr)).To(BeTrue())
+			Expect(notFoundErr.Id).To(Equal(alertRuleId))
 		})
 	})
 })
@@ -0,0 +1,37 @@
+package management
+
+import (
+	"context"
+	"fmt"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func (c *client) UpdateRuleGroup(ctx context.Context, prId types.NamespacedName, groupName string, group monitoringv1.RuleGroup) (RuleGroup, error) {
+	if err := validateRuleGroup(group); err != nil {
+		return RuleGroup{}, err
+	}
+
+	pr, err := c.editUserDefinedPrometheusRule(ctx, prId, func(pr *monitoringv1.PrometheusRule) error {
+		idx := ruleGroupIndex(pr, groupName)
+		if idx < 0 {
+			return ruleGroupNotFound(prId, groupName)
+		}
+
+		if group.Name != groupName && ruleGroupIndex(pr, group.Name) >= 0 {
+			return &ConflictError{Message: fmt.Sprintf("rule group %s already exists in PrometheusRule %s/%s", group.Name, prId.Namespace, prId.Name)}
+		}
+
+		// The settings of the group are replaced and its rules are kept
+		updated := group
+		updated.Rules = pr.Spec.Groups[idx].Rules
+		pr.Spec.Groups[idx] = updated
+		return nil
+	})
+	if err != nil {
+		return RuleGroup{}, err
+	}
+
+	return ruleGroupFrom(pr, pr.Spec.Groups[ruleGroupIndex(pr, group.Name)], SourceUserDefined), nil
+}
@@ -0,0 +1,36 @@
+package management
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
+)
+
+func (c *client) UpdateSilence(ctx context.Context, silenceId string, silence k8s.Silence) (string, error) {
+	existing, err := c.GetSilence(ctx, silenceId)
+	if err != nil {
+		return "", err
+	}
+
+	if existing.Status != nil && existing.Status.State == k8s.SilenceStateExpired {
+		return "", &NotAllowedError{Message: fmt.Sprintf("silence %s is expired", silenceId)}
+	}
+
+	// The start of active silences cannot be moved, so it is kept if unset
+	if silence.StartsAt.IsZero() {
+		silence.StartsAt = existing.StartsAt
+	}
+
+	silence, err = prepareSilence(ctx, silence)
+	if err != nil {
+		return "", err
+	}
+	silence.Id = silenceId
+
+	newSilenceId, err := c.k8sClient.AlertmanagerSilences().Update(ctx, silence)
+	if err != nil {
+		return "", silenceError(err)
+	}
+	return newSilenceId, nil
+}
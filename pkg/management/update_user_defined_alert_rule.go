@@ -6,56 +6,90 @@ import (
 
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
 
 	"github.com/machadovilaca/alerts-ui-management/pkg/management/mapper"
 )
 
-func (c *client) UpdateUserDefinedAlertRule(ctx context.Context, alertRuleId string, alertRule monitoringv1.Rule) error {
-	prId, err := c.mapper.FindAlertRuleById(mapper.PrometheusAlertRuleId(alertRuleId))
-	if err != nil {
-		return err
+func (c *client) UpdateUserDefinedAlertRule(ctx context.Context, alertRuleId string, alertRule monitoringv1.Rule) (string, error) {
+	if err := validateRule(alertRule); err != nil {
+		return "", err
 	}
 
-	if IsPlatformAlertRule(types.NamespacedName(*prId)) {
-		return fmt.Errorf("cannot update alert rule in a platform-managed PrometheusRule")
+	prId, err := c.findPrometheusRuleId(alertRuleId)
+	if err != nil {
+		return "", err
 	}
 
-	pr, found, err := c.k8sClient.PrometheusRules().Get(ctx, prId.Namespace, prId.Name)
+	source, err := c.GetPrometheusRuleSource(ctx, types.NamespacedName(*prId))
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	if !found {
-		return &NotFoundError{Resource: "PrometheusRule", Id: fmt.Sprintf("%s/%s", prId.Namespace, prId.Name)}
+	if source == SourcePlatform {
+		return "", &NotAllowedError{Message: "cannot update alert rule in a platform-managed PrometheusRule"}
+	}
+
+	k8sClient, err := c.k8sClientFor(ctx)
+	if err != nil {
+		return "", err
 	}
 
-	updated := false
-	for groupIdx := range pr.Spec.Groups {
-		for ruleIdx := range pr.Spec.Groups[groupIdx].Rules {
-			rule := &pr.Spec.Groups[groupIdx].Rules[ruleIdx]
-			if c.shouldUpdateRule(*rule, alertRuleId) {
-				pr.Spec.Groups[groupIdx].Rules[ruleIdx] = alertRule
-				updated = true
-				break
-			}
+	var newAlertRule monitoringv1.Rule
+	var location mapper.AlertRuleLocation
+
+	// The PrometheusRule is re-read on conflicts, so that concurrent edits of
+	// other rules in the same PrometheusRule are preserved
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		pr, found, err := k8sClient.PrometheusRules().Get(ctx, prId.Namespace, prId.Name)
+		if err != nil {
+			return err
 		}
-		if updated {
-			break
+
+		if !found {
+			return &NotFoundError{Resource: "PrometheusRule", Id: fmt.Sprintf("%s/%s", prId.Namespace, prId.Name)}
 		}
-	}
 
-	if !updated {
-		return fmt.Errorf("alert rule with id %s not found in PrometheusRule %s/%s", alertRuleId, prId.Namespace, prId.Name)
-	}
+		if err := checkResourceVersion(ctx, pr); err != nil {
+			return err
+		}
 
-	err = c.k8sClient.PrometheusRules().Update(ctx, *pr)
+		groupIdx, ruleIdx, found := c.findRule(pr, alertRuleId)
+		if !found {
+			return fmt.Errorf("alert rule with id %s not found in PrometheusRule %s/%s", alertRuleId, prId.Namespace, prId.Name)
+		}
+
+		if ruleType(pr.Spec.Groups[groupIdx].Rules[ruleIdx]) != ruleType(alertRule) {
+			return &InvalidArgumentError{Message: "cannot change the type of a rule between alerting and recording"}
+		}
+
+		stableId, err := c.stableAlertRuleId(&pr.Spec.Groups[groupIdx].Rules[ruleIdx], pr.Namespace)
+		if err != nil {
+			return err
+		}
+		newAlertRule = withAlertRuleId(alertRule, stableId)
+		pr.Spec.Groups[groupIdx].Rules[ruleIdx] = newAlertRule
+		location = mapper.AlertRuleLocation{PrometheusRuleId: *prId, GroupName: pr.Spec.Groups[groupIdx].Name, GroupIndex: groupIdx, RuleIndex: ruleIdx}
+
+		err = k8sClient.PrometheusRules().Update(ctx, *pr)
+		if err != nil {
+			return fmt.Errorf("failed to update PrometheusRule %s/%s: %w", pr.Namespace, pr.Name, err)
+		}
+
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to update PrometheusRule %s/%s: %w", pr.Namespace, pr.Name, err)
+		return "", err
 	}
 
-	return nil
+	return string(c.mapper.GetUniqueAlertingRuleId(&newAlertRule, location)), nil
 }
 
-func (c *client) shouldUpdateRule(rule monitoringv1.Rule, alertRuleId string) bool {
-	return alertRuleId == string(c.mapper.GetAlertingRuleId(&rule))
+// stableAlertRuleId returns the stable ID of the alert rule, generating one for
+// the namespace if the rule was created before stable IDs were introduced
+func (c *client) stableAlertRuleId(rule *monitoringv1.Rule, namespace string) (mapper.PrometheusAlertRuleId, error) {
+	if id := rule.Annotations[mapper.AlertRuleIdAnnotation]; id != "" {
+		return mapper.PrometheusAlertRuleId(id), nil
+	}
+	return mapper.NewAlertingRuleId(namespace)
 }
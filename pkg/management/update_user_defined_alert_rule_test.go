@@ -2,11 +2,14 @@ package management_test
 
 import (
 	"context"
+	"errors"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/machadovilaca/alerts-ui-management/pkg/k8s"
@@ -88,8 +91,9 @@ var _ = Describe("UpdateUserDefinedAlertRule", func() {
 				},
 			}
 
-			err := client.UpdateUserDefinedAlertRule(ctx, alertRuleId, updatedRule)
+			newAlertRuleId, err := client.UpdateUserDefinedAlertRule(ctx, alertRuleId, updatedRule)
 			Expect(err).ToNot(HaveOccurred())
+			Expect(newAlertRuleId).To(Equal("other-id"))
 
 			By("verifying the update succeeded")
 			updatedPR, found, err := mockPR.Get(ctx, "user-namespace", "user-rule")
@@ -162,7 +166,7 @@ var _ = Describe("UpdateUserDefinedAlertRule", func() {
 				Expr:  intstr.FromString("cpu_usage > 90"),
 			}
 
-			err := client.UpdateUserDefinedAlertRule(ctx, alertRuleId, updatedRule)
+			_, err := client.UpdateUserDefinedAlertRule(ctx, alertRuleId, updatedRule)
 			Expect(err).ToNot(HaveOccurred())
 
 			By("verifying only the targeted rule was updated")
@@ -221,7 +225,7 @@ var _ = Describe("UpdateUserDefinedAlertRule", func() {
 				Expr:  intstr.FromString("up == 1"),
 			}
 
-			err := client.UpdateUserDefinedAlertRule(ctx, alertRuleId, updatedRule)
+			_, err := client.UpdateUserDefinedAlertRule(ctx, alertRuleId, updatedRule)
 
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("not found"))
@@ -241,10 +245,159 @@ var _ = Describe("UpdateUserDefinedAlertRule", func() {
 				Expr:  intstr.FromString("up == 1"),
 			}
 
-			err := client.UpdateUserDefinedAlertRule(ctx, alertRuleId, updatedRule)
+			_, err := client.UpdateUserDefinedAlertRule(ctx, alertRuleId, updatedRule)
 
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("platform-managed"))
+
+			var notAllowedErr *management.NotAllowedError
+			Expect(errors.As(err, &notAllowedErr)).To(BeTrue())
+		})
+	})
+
+	Context("when the PrometheusRule is edited concurrently", func() {
+		var (
+			alertRuleId string
+			newPR       func(resourceVersion string, alerts ...string) *monitoringv1.PrometheusRule
+		)
+
+		BeforeEach(func() {
+			alertRuleId = "target-rule-id"
+			newPR = func(resourceVersion string, alerts ...string) *monitoringv1.PrometheusRule {
+				pr := &monitoringv1.PrometheusRule{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "user-rule",
+						Namespace:       "user-namespace",
+						ResourceVersion: resourceVersion,
+					},
+				}
+				group := monitoringv1.RuleGroup{Name: "test-group"}
+				for _, alert := range alerts {
+					group.Rules = append(group.Rules, monitoringv1.Rule{Alert: alert, Expr: intstr.FromString("up == 0")})
+				}
+				pr.Spec.Groups = []monitoringv1.RuleGroup{group}
+				return pr
+			}
+
+			mockPR.SetPrometheusRules(map[string]*monitoringv1.PrometheusRule{
+				"user-namespace/user-rule": newPR("1", "TargetAlert"),
+			})
+
+			mockMapper.FindAlertRuleByIdFunc = func(id mapper.PrometheusAlertRuleId) (*mapper.PrometheusRuleId, error) {
+				return &mapper.PrometheusRuleId{Namespace: "user-namespace", Name: "user-rule"}, nil
+			}
+			mockMapper.GetAlertingRuleIdFunc = func(alertRule *monitoringv1.Rule) mapper.PrometheusAlertRuleId {
+				if alertRule.Alert == "TargetAlert" {
+					return mapper.PrometheusAlertRuleId(alertRuleId)
+				}
+				return mapper.PrometheusAlertRuleId(alertRule.Alert)
+			}
+		})
+
+		It("should re-read the PrometheusRule and re-apply the change on conflict", func() {
+			By("simulating another rule being added before the first update")
+			updates := 0
+			mockPR.UpdateFunc = func(ctx context.Context, pr monitoringv1.PrometheusRule) error {
+				updates++
+				if updates == 1 {
+					mockPR.PrometheusRules["user-namespace/user-rule"] = newPR("2", "TargetAlert", "ConcurrentAlert")
+					return apierrors.NewConflict(schema.GroupResource{Resource: "prometheusrules"}, pr.Name, errors.New("object has been modified"))
+				}
+				mockPR.PrometheusRules["user-namespace/user-rule"] = &pr
+				return nil
+			}
+
+			_, err := client.UpdateUserDefinedAlertRule(ctx, alertRuleId, monitoringv1.Rule{Alert: "UpdatedAlert", Expr: intstr.FromString("up == 1")})
+			Expect(err).ToNot(HaveOccurred())
+
+			By("verifying both edits are preserved")
+			Expect(updates).To(Equal(2))
+			updatedPR, _, _ := mockPR.Get(ctx, "user-namespace", "user-rule")
+			Expect(updatedPR.Spec.Groups[0].Rules).To(HaveLen(2))
+			Expect(updatedPR.Spec.Groups[0].Rules[0].Alert).To(Equal("UpdatedAlert"))
+			Expect(updatedPR.Spec.Groups[0].Rules[1].Alert).To(Equal("ConcurrentAlert"))
+		})
+
+		It("should update when the expected resourceVersion matches", func() {
+			_, err := client.UpdateUserDefinedAlertRule(management.WithResourceVersion(ctx, "1"), alertRuleId, monitoringv1.Rule{Alert: "UpdatedAlert", Expr: intstr.FromString("up == 1")})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should return PreconditionFailedError when the expected resourceVersion does not match", func() {
+			mockPR.UpdateFunc = func(ctx context.Context, pr monitoringv1.PrometheusRule) error {
+				Fail("the PrometheusRule should not be updated")
+				return nil
+			}
+
+			_, err := client.UpdateUserDefinedAlertRule(management.WithResourceVersion(ctx, "0"), alertRuleId, monitoringv1.Rule{Alert: "UpdatedAlert", Expr: intstr.FromString("up == 1")})
+
+			var preconditionFailedErr *management.PreconditionFailedError
+			Expect(errors.As(err, &preconditionFailedErr)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("has resourceVersion 1, expected 0"))
+		})
+	})
+
+	Context("when rules have stable IDs", func() {
+		var ruleMapper mapper.Client
+
+		BeforeEach(func() {
+			ruleMapper = mapper.New(mockK8s)
+			client = management.NewWithCustomMapper(ctx, mockK8s, ruleMapper)
+		})
+
+		setPrometheusRule := func(rule monitoringv1.Rule) {
+			pr := &monitoringv1.PrometheusRule{
+				ObjectMeta: metav1.ObjectMeta{Name: "user-rule", Namespace: "user-namespace"},
+				Spec: monitoringv1.PrometheusRuleSpec{
+					Groups: []monitoringv1.RuleGroup{{Name: "test-group", Rules: []monitoringv1.Rule{rule}}},
+				},
+			}
+			mockPR.SetPrometheusRules(map[string]*monitoringv1.PrometheusRule{"user-namespace/user-rule": pr})
+			ruleMapper.AddPrometheusRule(pr)
+		}
+
+		It("should keep the stable ID of the rule", func() {
+			setPrometheusRule(monitoringv1.Rule{
+				Alert:       "TestAlert",
+				Expr:        intstr.FromString("up == 0"),
+				Annotations: map[string]string{mapper.AlertRuleIdAnnotation: "stable-id"},
+			})
+
+			newAlertRuleId, err := client.UpdateUserDefinedAlertRule(ctx, "stable-id", monitoringv1.Rule{
+				Alert:       "TestAlert",
+				Expr:        intstr.FromString("up == 1"),
+				Annotations: map[string]string{"summary": "Fixed typo"},
+				Labels:      map[string]string{"alert_rule_id": "stable-id", "severity": "warning"},
+			})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(newAlertRuleId).To(Equal("stable-id"))
+
+			updatedPR, _, _ := mockPR.Get(ctx, "user-namespace", "user-rule")
+			updatedRule := updatedPR.Spec.Groups[0].Rules[0]
+			Expect(updatedRule.Annotations).To(Equal(map[string]string{
+				"summary":                    "Fixed typo",
+				mapper.AlertRuleIdAnnotation: "stable-id",
+			}))
+			Expect(updatedRule.Labels).To(Equal(map[string]string{"severity": "warning"}))
+		})
+
+		It("should assign a stable ID to a rule identified by its legacy hash-based ID", func() {
+			legacyRule := monitoringv1.Rule{Alert: "TestAlert", Expr: intstr.FromString("up == 0")}
+			setPrometheusRule(legacyRule)
+			legacyId := ruleMapper.GetAlertingRuleId(&legacyRule)
+
+			newAlertRuleId, err := client.UpdateUserDefinedAlertRule(ctx, string(legacyId), monitoringv1.Rule{
+				Alert: "TestAlert",
+				Expr:  intstr.FromString("up == 1"),
+			})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(newAlertRuleId).ToNot(BeEmpty())
+			Expect(newAlertRuleId).ToNot(Equal(string(legacyId)))
+
+			updatedPR, _, _ := mockPR.Get(ctx, "user-namespace", "user-rule")
+			Expect(updatedPR.Spec.Groups[0].Rules[0].Annotations).To(HaveKeyWithValue(mapper.AlertRuleIdAnnotation, newAlertRuleId))
 		})
 	})
 })
@@ -0,0 +1,134 @@
+package management
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/prometheus/prometheus/template"
+)
+
+// validateRule checks that the rule is either an alerting or a recording rule,
+// and that recording rules only set the fields Prometheus accepts for them
+// The fields are then checked the way Prometheus checks them when loading the
+// rule, and reported in a ValidationError
+func validateRule(rule monitoringv1.Rule) error {
+	if rule.Alert == "" && rule.Record == "" {
+		return &InvalidArgumentError{Message: "either alert or record must be set"}
+	}
+
+	if rule.Alert != "" && rule.Record != "" {
+		return &InvalidArgumentError{Message: "only one of alert and record can be set"}
+	}
+
+	if rule.Record != "" && (rule.For != nil || rule.KeepFiringFor != nil || len(rule.Annotations) > 0) {
+		return &InvalidArgumentError{Message: "recording rules cannot have for, keep_firing_for or annotations"}
+	}
+
+	var errs []FieldError
+	errs = append(errs, validateRecord(rule.Record)...)
+	errs = append(errs, validateExpr(rule.Expr.String())...)
+	errs = append(errs, validateDuration("for", rule.For)...)
+	errs = append(errs, validateDuration("keep_firing_for", (*monitoringv1.Duration)(rule.KeepFiringFor))...)
+
+	// Label and annotation names follow the legacy rule relabel configs and
+	// alert filters use, rather than the UTF-8 names Prometheus may accept
+	for name, value := range rule.Labels {
+		field := "labels." + name
+		if !labelNameRegexp.MatchString(name) || name == model.MetricNameLabel {
+			errs = append(errs, FieldError{Field: field, Message: fmt.Sprintf("invalid label name %q", name)})
+		}
+		if !model.LabelValue(value).IsValid() {
+			errs = append(errs, FieldError{Field: field, Message: "label value must be valid UTF-8"})
+		} else if rule.Alert != "" {
+			errs = append(errs, validateTemplate(field, rule.Alert, value)...)
+		}
+	}
+
+	for name, value := range rule.Annotations {
+		field := "annotations." + name
+		if !labelNameRegexp.MatchString(name) {
+			errs = append(errs, FieldError{Field: field, Message: fmt.Sprintf("invalid annotation name %q", name)})
+		}
+		errs = append(errs, validateTemplate(field, rule.Alert, value)...)
+	}
+
+	if len(errs) > 0 {
+		return &ValidationError{Errors: sortFieldErrors(errs)}
+	}
+	return nil
+}
+
+func validateRecord(record string) []FieldError {
+	if record == "" {
+		return nil
+	}
+
+	if !model.IsValidMetricName(model.LabelValue(record)) {
+		return []FieldError{{Field: "record", Message: fmt.Sprintf("invalid metric name %q", record)}}
+	}
+
+	// Braces are valid in UTF-8 metric names, but usually mean the expression
+	// was put in the name
+	if strings.ContainsAny(record, "{}") {
+		return []FieldError{{Field: "record", Message: "record must not contain braces, should they be in expr?"}}
+	}
+
+	return nil
+}
+
+// validateExpr checks that expr is a PromQL expression returning a vector
+func validateExpr(expr string) []FieldError {
+	if strings.TrimSpace(expr) == "" {
+		return []FieldError{{Field: "expr", Message: "expr must be set"}}
+	}
+
+	parsed, err := parser.ParseExpr(expr)
+	if err != nil {
+		return []FieldError{{Field: "expr", Message: fmt.Sprintf("invalid PromQL expression: %v", err)}}
+	}
+
+	if parsed.Type() != parser.ValueTypeVector {
+		return []FieldError{{Field: "expr", Message: fmt.Sprintf("expression must return an instant vector, got %s", parsed.Type())}}
+	}
+
+	return nil
+}
+
+func validateDuration(field string, duration *monitoringv1.Duration) []FieldError {
+	if duration == nil {
+		return nil
+	}
+
+	if _, err := model.ParseDuration(string(*duration)); err != nil {
+		return []FieldError{{Field: field, Message: fmt.Sprintf("invalid duration %q: %v", *duration, err)}}
+	}
+
+	return nil
+}
+
+// validateTemplate checks that text parses as a template of an alerting rule,
+// with the variables Prometheus defines for them
+func validateTemplate(field string, alert string, text string) []FieldError {
+	defs := []string{
+		"{{$labels := .Labels}}",
+		"{{$externalLabels := .ExternalLabels}}",
+		"{{$externalURL := .ExternalURL}}",
+		"{{$value := .Value}}",
+	}
+
+	data := template.AlertTemplateData(map[string]string{}, map[string]string{}, "", promql.Sample{})
+	expander := template.NewTemplateExpander(context.Background(), strings.Join(append(defs, text), ""),
+		"__alert_"+alert, data, model.TimeFromUnixNano(time.Now().UnixNano()), nil, nil, nil)
+
+	if err := expander.ParseTest(); err != nil {
+		return []FieldError{{Field: field, Message: fmt.Sprintf("invalid template: %v", err)}}
+	}
+
+	return nil
+}